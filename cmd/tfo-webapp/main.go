@@ -2,13 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/brian-abo/tfo-webapp/internal/config"
 	"github.com/brian-abo/tfo-webapp/internal/database"
+	"github.com/brian-abo/tfo-webapp/internal/jobs"
+	"github.com/brian-abo/tfo-webapp/internal/mail"
+	"github.com/brian-abo/tfo-webapp/internal/scheduler"
 	"github.com/brian-abo/tfo-webapp/internal/web"
 )
 
@@ -16,6 +22,12 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	if len(os.Args) > 1 && os.Args[1] == "verify-lottery" {
+		code := verifyLottery(ctx, os.Args[2:], os.Stdout)
+		stop()
+		os.Exit(code)
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("loading config: %v", err)
@@ -31,10 +43,49 @@ func main() {
 		}
 	}()
 
-	router := web.NewRouter(db)
+	sender, err := mail.NewSender(cfg.Mail)
+	if err != nil {
+		log.Fatalf("configuring mail: %v", err)
+	}
+
+	// The router, worker and scheduler share one set of services.
+	services := web.NewServices(db, sender, cfg.BaseURL)
+	worker := jobs.NewWorker(db)
+	services.RegisterJobs(worker)
+	router := web.NewRouter(db, cfg, services)
+
+	// The worker stops with ctx, finishing the jobs it's running.
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		worker.Run(ctx)
+	}()
+
+	// Every replica runs the scheduler; only one at a time leads.
+	sched := scheduler.New(db, services.Hunt, services.Confirmation)
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		sched.Run(ctx)
+	}()
+
+	srv := &http.Server{Addr: cfg.Addr, Handler: router}
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("shutting down server: %v", err)
+		}
+	}()
 
 	log.Printf("listening on %s", cfg.Addr)
-	if err := http.ListenAndServe(cfg.Addr, router); err != nil {
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal(err)
 	}
+	<-shutdownDone
+	<-workerDone
+	<-schedulerDone
 }
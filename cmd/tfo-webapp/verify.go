@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/config"
+	"github.com/brian-abo/tfo-webapp/internal/database"
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+)
+
+// verifyLottery implements "tfo-webapp verify-lottery <hunt-id>": it
+// replays a hunt's recorded draw and reports whether every position
+// matches. It returns the process exit code: 0 for a match, 1 for a
+// mismatch and 2 for usage or connection errors.
+func verifyLottery(ctx context.Context, args []string, out io.Writer) int {
+	if len(args) != 1 {
+		log.Print("usage: tfo-webapp verify-lottery <hunt-id>")
+		return 2
+	}
+	huntID, err := uuid.Parse(args[0])
+	if err != nil {
+		log.Printf("invalid hunt ID %q: %v", args[0], err)
+		return 2
+	}
+
+	url, err := config.DatabaseURL()
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	db, err := database.Connect(ctx, url)
+	if err != nil {
+		log.Printf("connecting to database: %v", err)
+		return 2
+	}
+	defer func() { _ = db.Close() }()
+
+	v, err := lottery.NewVerifier(db).Verify(ctx, huntID)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+
+	_, _ = fmt.Fprintf(out, "algorithm:  %s\n", v.AlgorithmVersion)
+	_, _ = fmt.Fprintf(out, "seed:       %d\n", v.AuditSeed)
+	_, _ = fmt.Fprintf(out, "drawn at:   %s\n", v.DrawnAt.Format("2006-01-02 15:04:05 MST"))
+	_, _ = fmt.Fprintf(out, "entries:    %d\n", len(v.Checks))
+	_, _ = fmt.Fprintf(out, "input hash: %s\n\n", v.InputHash)
+	for _, c := range v.Checks {
+		status := "ok"
+		if !c.Match() {
+			status = "MISMATCH (replay: " + c.Replayed.String() + ")"
+		}
+		_, _ = fmt.Fprintf(out, "%4d  %s  %s\n", c.Position, c.Recorded, status)
+	}
+	if !v.Match() {
+		_, _ = fmt.Fprintf(out, "\n%d positions do not match the replay\n", v.Mismatches())
+		return 1
+	}
+	_, _ = fmt.Fprintln(out, "\nall positions match the replay")
+	return 0
+}
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE membership_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    decided_by_id UUID NOT NULL REFERENCES users(id),
+    decided_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT membership_decisions_reason_present CHECK (reason <> '')
+);
+
+CREATE INDEX idx_membership_decisions_user_id ON membership_decisions (user_id, decided_at DESC);
+
+-- +goose Down
+DROP TABLE membership_decisions;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN contact_preference TEXT NOT NULL DEFAULT 'email',
+    ADD CONSTRAINT users_contact_preference_check CHECK (
+        contact_preference IN ('email', 'phone', 'text')
+    );
+
+-- +goose Down
+ALTER TABLE users
+    DROP CONSTRAINT users_contact_preference_check,
+    DROP COLUMN contact_preference;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE hunt_status_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hunt_id UUID NOT NULL REFERENCES hunts(id),
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by_id UUID REFERENCES users(id),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_hunt_status_changes_hunt_id ON hunt_status_changes (hunt_id, changed_at);
+
+-- +goose Down
+DROP TABLE hunt_status_changes;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE hunts ADD COLUMN state TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_hunts_state ON hunts (state);
+
+-- +goose Down
+DROP INDEX idx_hunts_state;
+ALTER TABLE hunts DROP COLUMN state;
@@ -0,0 +1,8 @@
+-- +goose Up
+DROP INDEX idx_lottery_results_position;
+ALTER TABLE lottery_results
+    ADD CONSTRAINT lottery_results_hunt_position_unique UNIQUE (hunt_id, position);
+
+-- +goose Down
+ALTER TABLE lottery_results DROP CONSTRAINT lottery_results_hunt_position_unique;
+CREATE INDEX idx_lottery_results_position ON lottery_results (hunt_id, position);
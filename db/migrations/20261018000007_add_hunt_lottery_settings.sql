@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE hunts
+    ADD COLUMN lottery_algorithm TEXT NOT NULL DEFAULT 'uniform-v1',
+    ADD COLUMN lottery_weights JSONB NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE hunts
+    DROP COLUMN lottery_weights,
+    DROP COLUMN lottery_algorithm;
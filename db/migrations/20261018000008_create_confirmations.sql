@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE hunts ADD COLUMN confirmation_window_hours INTEGER NOT NULL DEFAULT 72
+    CONSTRAINT hunts_confirmation_window_positive CHECK (confirmation_window_hours > 0);
+
+CREATE TABLE confirmations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    lottery_result_id UUID NOT NULL REFERENCES lottery_results(id),
+    hunt_id UUID NOT NULL REFERENCES hunts(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    deadline TIMESTAMPTZ NOT NULL,
+    decision TEXT NOT NULL DEFAULT 'pending'
+        CHECK (decision IN ('pending', 'accepted', 'declined', 'expired')),
+    reason TEXT NOT NULL DEFAULT '',
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT confirmations_result_unique UNIQUE (lottery_result_id)
+);
+
+CREATE INDEX idx_confirmations_hunt_id ON confirmations (hunt_id);
+CREATE INDEX idx_confirmations_user_id ON confirmations (user_id);
+CREATE INDEX idx_confirmations_pending_deadline ON confirmations (deadline) WHERE decision = 'pending';
+
+-- +goose Down
+DROP TABLE confirmations;
+ALTER TABLE hunts DROP COLUMN confirmation_window_hours;
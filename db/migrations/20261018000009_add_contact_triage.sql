@@ -0,0 +1,31 @@
+-- +goose Up
+ALTER TABLE contact_submissions
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'new'
+        CONSTRAINT contact_submissions_status_check CHECK (status IN ('new', 'in_progress', 'resolved', 'spam')),
+    ADD COLUMN assignee_id UUID REFERENCES users(id),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector('english', name || ' ' || email || ' ' || message)
+    ) STORED;
+
+CREATE INDEX idx_contact_submissions_status ON contact_submissions (status);
+CREATE INDEX idx_contact_submissions_assignee_id ON contact_submissions (assignee_id);
+CREATE INDEX idx_contact_submissions_search ON contact_submissions USING GIN (search);
+
+CREATE TABLE contact_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL REFERENCES contact_submissions(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id),
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_contact_notes_submission_id ON contact_notes (submission_id, created_at);
+
+-- +goose Down
+DROP TABLE contact_notes;
+ALTER TABLE contact_submissions
+    DROP COLUMN search,
+    DROP COLUMN updated_at,
+    DROP COLUMN assignee_id,
+    DROP COLUMN status;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE contact_submissions ADD COLUMN region_id TEXT;
+
+CREATE INDEX idx_contact_submissions_region_id ON contact_submissions (region_id);
+
+-- +goose Down
+DROP INDEX idx_contact_submissions_region_id;
+ALTER TABLE contact_submissions DROP COLUMN region_id;
@@ -0,0 +1,58 @@
+-- +goose Up
+CREATE TABLE regions (
+    id TEXT PRIMARY KEY CHECK (id ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    name TEXT NOT NULL,
+    color TEXT NOT NULL CHECK (color ~ '^#[0-9A-Fa-f]{6}$'),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Each state belongs to at most one region.
+CREATE TABLE region_states (
+    state TEXT PRIMARY KEY,
+    region_id TEXT NOT NULL REFERENCES regions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_region_states_region_id ON region_states (region_id);
+
+CREATE TABLE regional_leaders (
+    region_id TEXT PRIMARY KEY REFERENCES regions(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO regions (id, name, color, position) VALUES
+    ('west-coast', 'West Coast', '#009FDE', 1),
+    ('midwest', 'Midwest', '#81D742', 2),
+    ('east-coast', 'East Coast', '#DD3333', 3),
+    ('southern', 'Southern', '#EEEE22', 4);
+
+INSERT INTO region_states (region_id, state)
+SELECT 'west-coast', unnest(ARRAY['AK', 'AZ', 'CA', 'CO', 'HI', 'ID', 'MT', 'NV', 'NM', 'OR', 'UT', 'WA', 'WY'])
+UNION ALL
+SELECT 'midwest', unnest(ARRAY['IL', 'IN', 'IA', 'KS', 'MI', 'MN', 'MO', 'NE', 'ND', 'OH', 'SD', 'WI'])
+UNION ALL
+SELECT 'east-coast', unnest(ARRAY['CT', 'DE', 'ME', 'MD', 'MA', 'NH', 'NJ', 'NY', 'NC', 'PA', 'RI', 'SC', 'VT', 'VA', 'WV', 'DC'])
+UNION ALL
+SELECT 'southern', unnest(ARRAY['AL', 'AR', 'FL', 'GA', 'KY', 'LA', 'MS', 'OK', 'TN', 'TX']);
+
+INSERT INTO regional_leaders (region_id, name, email) VALUES
+    ('west-coast', 'David Lee', 'westcoast@thefallenoutdoors.org'),
+    ('midwest', 'James Wilson', 'midwest@thefallenoutdoors.org'),
+    ('east-coast', 'Tom Anderson', 'eastcoast@thefallenoutdoors.org'),
+    ('southern', 'Maria Garcia', 'southern@thefallenoutdoors.org');
+
+UPDATE contact_submissions SET region_id = NULL
+WHERE region_id IS NOT NULL AND region_id NOT IN (SELECT id FROM regions);
+
+ALTER TABLE contact_submissions
+    ADD CONSTRAINT contact_submissions_region_id_fkey
+    FOREIGN KEY (region_id) REFERENCES regions(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE contact_submissions DROP CONSTRAINT contact_submissions_region_id_fkey;
+DROP TABLE regional_leaders;
+DROP TABLE region_states;
+DROP TABLE regions;
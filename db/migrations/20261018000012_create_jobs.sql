@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CONSTRAINT jobs_status_check CHECK (status IN ('pending', 'running', 'done', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 8 CHECK (max_attempts > 0),
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- A running job whose lease has expired was abandoned by its worker
+    -- and may be claimed again.
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_jobs_pending ON jobs (run_at) WHERE status = 'pending';
+CREATE INDEX idx_jobs_running ON jobs (locked_until) WHERE status = 'running';
+CREATE INDEX idx_jobs_status ON jobs (status, created_at);
+
+-- +goose Down
+DROP TABLE jobs;
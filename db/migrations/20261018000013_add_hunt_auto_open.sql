@@ -0,0 +1,6 @@
+-- +goose Up
+-- auto_open marks a draft the scheduler opens when its signup window starts.
+ALTER TABLE hunts ADD COLUMN auto_open BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE hunts DROP COLUMN auto_open;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- previous_token_hash keeps the token a session was rotated from, which
+-- stays valid briefly after rotated_at so requests already in flight with
+-- the old cookie aren't logged out.
+ALTER TABLE sessions ADD COLUMN previous_token_hash TEXT;
+CREATE INDEX idx_sessions_previous_token_hash ON sessions (previous_token_hash) WHERE previous_token_hash IS NOT NULL;
+
+-- +goose Down
+DROP INDEX idx_sessions_previous_token_hash;
+ALTER TABLE sessions DROP COLUMN previous_token_hash;
@@ -0,0 +1,108 @@
+// Package aar implements staff writing after-action reports for completed
+// hunts.
+package aar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+var (
+	// ErrHuntNotCompleted is returned when writing a report for a hunt
+	// that hasn't been completed.
+	ErrHuntNotCompleted = errors.New("hunt is not completed")
+	// ErrNotOnRoster is returned when tagging someone who didn't accept a
+	// place on the hunt.
+	ErrNotOnRoster = errors.New("participant is not on the confirmed roster")
+	// ErrAARExists is returned when creating a second report for a hunt.
+	ErrAARExists = repository.ErrAARExists
+)
+
+// Service saves after-action reports.
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates an after-action report Service.
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// Create writes the report for a completed hunt and tags participants,
+// who must be on the hunt's confirmed roster. Returns ErrAARExists if the
+// hunt already has a report.
+func (s *Service) Create(ctx context.Context, report model.HuntAfterActionReport, participants []uuid.UUID) (model.HuntAfterActionReport, error) {
+	var created model.HuntAfterActionReport
+	err := s.withCompletedHunt(ctx, report.HuntID, participants, func(reports *repository.AARRepository) error {
+		var err error
+		created, err = reports.Create(ctx, report)
+		if err != nil {
+			return err
+		}
+		return reports.SetParticipants(ctx, created.ID, participants)
+	})
+	if err != nil {
+		return model.HuntAfterActionReport{}, fmt.Errorf("creating after-action report: %w", err)
+	}
+	return created, nil
+}
+
+// Update saves changes to a hunt's report and replaces its participants.
+func (s *Service) Update(ctx context.Context, report model.HuntAfterActionReport, participants []uuid.UUID) (model.HuntAfterActionReport, error) {
+	var updated model.HuntAfterActionReport
+	err := s.withCompletedHunt(ctx, report.HuntID, participants, func(reports *repository.AARRepository) error {
+		var err error
+		updated, err = reports.Update(ctx, report)
+		if err != nil {
+			return err
+		}
+		return reports.SetParticipants(ctx, updated.ID, participants)
+	})
+	if err != nil {
+		return model.HuntAfterActionReport{}, fmt.Errorf("updating after-action report: %w", err)
+	}
+	return updated, nil
+}
+
+// withCompletedHunt runs fn in a transaction after checking the hunt is
+// completed and every participant is on its confirmed roster.
+func (s *Service) withCompletedHunt(ctx context.Context, huntID uuid.UUID, participants []uuid.UUID, fn func(*repository.AARRepository) error) error {
+	return repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		hunt, err := repository.NewHuntRepository(tx).GetByID(ctx, huntID)
+		if err != nil {
+			return err
+		}
+		if hunt.Status != model.HuntStatusCompleted {
+			return ErrHuntNotCompleted
+		}
+		roster, err := repository.NewUserRepository(tx).ListConfirmed(ctx, huntID)
+		if err != nil {
+			return err
+		}
+		if err := checkRoster(roster, participants); err != nil {
+			return err
+		}
+		return fn(repository.NewAARRepository(tx))
+	})
+}
+
+// checkRoster returns ErrNotOnRoster unless every participant is in roster.
+func checkRoster(roster []model.User, participants []uuid.UUID) error {
+	confirmed := make(map[uuid.UUID]bool, len(roster))
+	for _, u := range roster {
+		confirmed[u.ID] = true
+	}
+	for _, id := range participants {
+		if !confirmed[id] {
+			return ErrNotOnRoster
+		}
+	}
+	return nil
+}
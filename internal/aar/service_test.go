@@ -0,0 +1,29 @@
+package aar
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestCheckRoster(t *testing.T) {
+	roster := []model.User{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	t.Run("accepts members of the confirmed roster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(checkRoster(roster, []uuid.UUID{roster[1].ID})).To(Succeed())
+		g.Expect(checkRoster(roster, nil)).To(Succeed())
+	})
+
+	t.Run("rejects anyone else", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := checkRoster(roster, []uuid.UUID{roster[0].ID, uuid.New()})
+
+		g.Expect(err).To(MatchError(ErrNotOnRoster))
+	})
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// OnboardingPath is where users with incomplete profiles are sent.
+const OnboardingPath = "/onboarding"
+
+// ErrorRenderer writes an error page for the given status code. The
+// message explains why access was denied.
+type ErrorRenderer func(w http.ResponseWriter, r *http.Request, status int, message string)
+
+// Authorizer guards routes by login state, membership and role.
+type Authorizer struct {
+	renderError ErrorRenderer
+}
+
+// NewAuthorizer creates an Authorizer that renders denials with renderError.
+func NewAuthorizer(renderError ErrorRenderer) *Authorizer {
+	return &Authorizer{renderError: renderError}
+}
+
+// RequireLogin rejects anonymous requests with 401.
+func (a *Authorizer) RequireLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := UserFromContext(r.Context()); !ok {
+			a.renderError(w, r, http.StatusUnauthorized, "Please log in to continue.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireMember allows logged-in users with a complete profile and an
+// active membership. Incomplete profiles are redirected to onboarding;
+// inactive memberships get 403.
+func (a *Authorizer) RequireMember(next http.Handler) http.Handler {
+	return a.RequireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, _ := UserFromContext(r.Context())
+		if !u.IsAccountComplete() {
+			redirect(w, r, OnboardingPath)
+			return
+		}
+		if !u.IsActive() {
+			a.renderError(w, r, http.StatusForbidden, membershipMessage(u.MembershipStatus))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// RequireRole allows active members whose role is at least role.
+func (a *Authorizer) RequireRole(role model.Role, next http.Handler) http.Handler {
+	return a.RequireMember(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, _ := UserFromContext(r.Context())
+		if !u.Role.AtLeast(role) {
+			a.renderError(w, r, http.StatusForbidden, "You don't have permission to view this page.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+func membershipMessage(status model.MembershipStatus) string {
+	switch status {
+	case model.MembershipPending:
+		return "Your membership is awaiting approval. We'll let you know once it's active."
+	case model.MembershipSuspended:
+		return "Your membership is suspended. Please contact us for details."
+	default:
+		return "Your membership is not active."
+	}
+}
+
+// redirect sends the client to path, using HX-Redirect for htmx requests
+// so the whole page navigates instead of swapping a fragment.
+func redirect(w http.ResponseWriter, r *http.Request, path string) {
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", path)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, path, http.StatusSeeOther)
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func serveAuthorized(guard func(http.Handler) http.Handler, user *model.User, hx bool) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	h := guard(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { reached = true }))
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	if user != nil {
+		req = req.WithContext(WithUser(req.Context(), *user))
+	}
+	if hx {
+		req.Header.Set("HX-Request", "true")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, reached
+}
+
+func newTestAuthorizer() *Authorizer {
+	return NewAuthorizer(func(w http.ResponseWriter, _ *http.Request, status int, message string) {
+		http.Error(w, message, status)
+	})
+}
+
+func activeUser(role model.Role) *model.User {
+	return &model.User{
+		Name:             "Jane Doe",
+		BranchOfService:  "Army",
+		Role:             role,
+		MembershipStatus: model.MembershipActive,
+	}
+}
+
+func TestAuthorizer_RequireLogin(t *testing.T) {
+	a := newTestAuthorizer()
+
+	t.Run("returns 401 when anonymous", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rec, reached := serveAuthorized(a.RequireLogin, nil, false)
+
+		g.Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		g.Expect(reached).To(BeFalse())
+	})
+
+	t.Run("allows incomplete profiles", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, reached := serveAuthorized(a.RequireLogin, &model.User{Name: "New"}, false)
+
+		g.Expect(reached).To(BeTrue())
+	})
+}
+
+func TestAuthorizer_RequireMember(t *testing.T) {
+	a := newTestAuthorizer()
+
+	t.Run("redirects incomplete profiles to onboarding", func(t *testing.T) {
+		g := NewWithT(t)
+		u := activeUser(model.RoleMember)
+		u.BranchOfService = ""
+
+		rec, reached := serveAuthorized(a.RequireMember, u, false)
+
+		g.Expect(rec.Code).To(Equal(http.StatusSeeOther))
+		g.Expect(rec.Header().Get("Location")).To(Equal(OnboardingPath))
+		g.Expect(reached).To(BeFalse())
+	})
+
+	t.Run("uses HX-Redirect for htmx requests", func(t *testing.T) {
+		g := NewWithT(t)
+		u := activeUser(model.RoleMember)
+		u.BranchOfService = ""
+
+		rec, _ := serveAuthorized(a.RequireMember, u, true)
+
+		g.Expect(rec.Header().Get("HX-Redirect")).To(Equal(OnboardingPath))
+	})
+
+	t.Run("returns 403 for pending members", func(t *testing.T) {
+		g := NewWithT(t)
+		u := activeUser(model.RoleMember)
+		u.MembershipStatus = model.MembershipPending
+
+		rec, reached := serveAuthorized(a.RequireMember, u, false)
+
+		g.Expect(rec.Code).To(Equal(http.StatusForbidden))
+		g.Expect(rec.Body.String()).To(ContainSubstring("awaiting approval"))
+		g.Expect(reached).To(BeFalse())
+	})
+
+	t.Run("returns 403 for deleted users", func(t *testing.T) {
+		g := NewWithT(t)
+		u := activeUser(model.RoleMember)
+		u.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+		rec, _ := serveAuthorized(a.RequireMember, u, false)
+
+		g.Expect(rec.Code).To(Equal(http.StatusForbidden))
+	})
+
+	t.Run("allows active members", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, reached := serveAuthorized(a.RequireMember, activeUser(model.RoleMember), false)
+
+		g.Expect(reached).To(BeTrue())
+	})
+}
+
+func TestAuthorizer_RequireRole(t *testing.T) {
+	a := newTestAuthorizer()
+	requireStaff := func(next http.Handler) http.Handler { return a.RequireRole(model.RoleStaff, next) }
+
+	t.Run("returns 401 when anonymous", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rec, _ := serveAuthorized(requireStaff, nil, false)
+
+		g.Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	t.Run("returns 403 for members", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rec, reached := serveAuthorized(requireStaff, activeUser(model.RoleMember), false)
+
+		g.Expect(rec.Code).To(Equal(http.StatusForbidden))
+		g.Expect(reached).To(BeFalse())
+	})
+
+	t.Run("allows staff and admins", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, reached := serveAuthorized(requireStaff, activeUser(model.RoleStaff), false)
+		g.Expect(reached).To(BeTrue())
+
+		_, reached = serveAuthorized(requireStaff, activeUser(model.RoleAdmin), false)
+		g.Expect(reached).To(BeTrue())
+	})
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, u model.User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFromContext returns the authenticated user, if any.
+func UserFromContext(ctx context.Context) (model.User, bool) {
+	u, ok := ctx.Value(userKey{}).(model.User)
+	return u, ok
+}
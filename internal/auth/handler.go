@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+const (
+	stateCookieName   = "tfo_oauth"
+	stateCookiePath   = "/auth"
+	stateCookieMaxAge = 10 * time.Minute
+)
+
+// UserStore finds or creates the local user for a provider identity.
+type UserStore interface {
+	UpsertFacebookUser(ctx context.Context, facebookID, email, name string) (model.User, error)
+}
+
+// SessionStarter begins a login session for an authenticated user.
+type SessionStarter interface {
+	Start(ctx context.Context, w http.ResponseWriter, userID uuid.UUID) error
+}
+
+// Handler serves the login redirect and the OAuth callback.
+type Handler struct {
+	provider *Provider
+	users    UserStore
+	sessions SessionStarter
+	secure   bool
+}
+
+// NewHandler creates an auth Handler. Cookies are marked Secure when the
+// provider's redirect URL uses https.
+func NewHandler(provider *Provider, users UserStore, sessions SessionStarter) *Handler {
+	return &Handler{
+		provider: provider,
+		users:    users,
+		sessions: sessions,
+		secure:   strings.HasPrefix(provider.cfg.RedirectURL, "https://"),
+	}
+}
+
+// Login starts the authorization-code flow by redirecting to the provider.
+// The state and PKCE verifier are kept in a short-lived cookie scoped to
+// the callback path.
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	state, err := randomToken(16)
+	if err != nil {
+		log.Printf("login: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	verifier, err := randomToken(32)
+	if err != nil {
+		log.Printf("login: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    state + "." + verifier,
+		Path:     stateCookiePath,
+		MaxAge:   int(stateCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   h.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, h.provider.AuthCodeURL(state, verifier), http.StatusFound)
+}
+
+// Callback completes the flow: it verifies state, exchanges the code,
+// fetches the provider profile, upserts the matching user and starts a
+// session. Users without a complete profile continue to onboarding.
+func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(stateCookieName)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Path:     stateCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	if err != nil {
+		http.Error(w, "Login session expired, please try again", http.StatusBadRequest)
+		return
+	}
+
+	state, verifier, ok := strings.Cut(cookie.Value, ".")
+	query := r.URL.Query()
+	if !ok || subtle.ConstantTimeCompare([]byte(state), []byte(query.Get("state"))) != 1 {
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	if query.Get("error") != "" {
+		http.Error(w, "Login was cancelled", http.StatusUnauthorized)
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		http.Error(w, "Missing authorization code", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.provider.Exchange(r.Context(), code, verifier)
+	if err != nil {
+		log.Printf("oauth callback: %v", err)
+		http.Error(w, "Login failed", http.StatusBadGateway)
+		return
+	}
+
+	profile, err := h.provider.FetchProfile(r.Context(), token)
+	if err != nil {
+		log.Printf("oauth callback: %v", err)
+		http.Error(w, "Login failed", http.StatusBadGateway)
+		return
+	}
+
+	if profile.Email == "" {
+		http.Error(w, "An email address is required to log in; please grant email permission", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.users.UpsertFacebookUser(r.Context(), profile.ID, profile.Email, profile.Name)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserDeleted) {
+			http.Error(w, "This account has been deactivated", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, repository.ErrFacebookAccountMismatch) {
+			http.Error(w, "This email address is already linked to a different Facebook account", http.StatusConflict)
+			return
+		}
+		log.Printf("oauth callback: upserting user: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.sessions.Start(r.Context(), w, user.ID); err != nil {
+		log.Printf("oauth callback: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if !user.IsAccountComplete() {
+		http.Redirect(w, r, OnboardingPath, http.StatusFound)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusFound)
+}
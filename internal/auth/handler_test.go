@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// stubProvider is a minimal OAuth2 server standing in for Facebook.
+type stubProvider struct {
+	server    *httptest.Server
+	challenge string
+	profile   Profile
+}
+
+func newStubProvider(t *testing.T, profile Profile) *stubProvider {
+	t.Helper()
+
+	s := &stubProvider{profile: profile}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("code") != "good-code" || codeChallenge(r.PostForm.Get("code_verifier")) != s.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "stub-token", "token_type": "bearer"})
+	})
+	mux.HandleFunc("GET /me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer stub-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(s.profile)
+	})
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *stubProvider) config() ProviderConfig {
+	return ProviderConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		AuthURL:      s.server.URL + "/authorize",
+		TokenURL:     s.server.URL + "/token",
+		UserInfoURL:  s.server.URL + "/me",
+		RedirectURL:  "http://localhost:8080/auth/facebook/callback",
+		Scopes:       []string{"email", "public_profile"},
+	}
+}
+
+type fakeUserStore struct {
+	calls  []Profile
+	branch string
+	err    error
+}
+
+func (f *fakeUserStore) UpsertFacebookUser(_ context.Context, facebookID, email, name string) (model.User, error) {
+	f.calls = append(f.calls, Profile{ID: facebookID, Email: email, Name: name})
+	if f.err != nil {
+		return model.User{}, f.err
+	}
+	return model.User{ID: uuid.New(), Email: email, Name: name, BranchOfService: f.branch}, nil
+}
+
+type fakeSessionStarter struct {
+	started []uuid.UUID
+}
+
+func (f *fakeSessionStarter) Start(_ context.Context, _ http.ResponseWriter, userID uuid.UUID) error {
+	f.started = append(f.started, userID)
+	return nil
+}
+
+// startLogin runs the Login handler and returns the state cookie and the
+// parsed provider redirect.
+func startLogin(t *testing.T, h *Handler, stub *stubProvider) (*http.Cookie, url.Values) {
+	t.Helper()
+	g := NewWithT(t)
+
+	rec := httptest.NewRecorder()
+	h.Login(rec, httptest.NewRequest(http.MethodGet, "/login", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusFound))
+
+	loc, err := url.Parse(rec.Header().Get("Location"))
+	g.Expect(err).ToNot(HaveOccurred())
+	q := loc.Query()
+	stub.challenge = q.Get("code_challenge")
+
+	cookies := rec.Result().Cookies()
+	g.Expect(cookies).To(HaveLen(1))
+	return cookies[0], q
+}
+
+func callback(h *Handler, cookie *http.Cookie, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/auth/facebook/callback?"+query, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.Callback(rec, req)
+	return rec
+}
+
+func TestHandler_Login(t *testing.T) {
+	t.Run("redirects to provider with state and PKCE challenge", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, Profile{})
+		h := NewHandler(NewProvider(stub.config()), &fakeUserStore{}, &fakeSessionStarter{})
+
+		cookie, q := startLogin(t, h, stub)
+
+		g.Expect(q.Get("client_id")).To(Equal("client-id"))
+		g.Expect(q.Get("response_type")).To(Equal("code"))
+		g.Expect(q.Get("code_challenge_method")).To(Equal("S256"))
+		g.Expect(q.Get("scope")).To(Equal("email,public_profile"))
+		g.Expect(q.Get("state")).ToNot(BeEmpty())
+		g.Expect(cookie.HttpOnly).To(BeTrue())
+		g.Expect(cookie.Value).To(HavePrefix(q.Get("state") + "."))
+	})
+}
+
+func TestHandler_Callback(t *testing.T) {
+	profile := Profile{ID: "fb-123", Name: "Jane Doe", Email: "jane@example.com"}
+
+	t.Run("upserts user, starts a session and redirects home", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, profile)
+		users := &fakeUserStore{branch: "Army"}
+		sessions := &fakeSessionStarter{}
+		h := NewHandler(NewProvider(stub.config()), users, sessions)
+		cookie, q := startLogin(t, h, stub)
+
+		rec := callback(h, cookie, "code=good-code&state="+q.Get("state"))
+
+		g.Expect(rec.Code).To(Equal(http.StatusFound))
+		g.Expect(rec.Header().Get("Location")).To(Equal("/"))
+		g.Expect(users.calls).To(Equal([]Profile{profile}))
+		g.Expect(sessions.started).To(HaveLen(1))
+	})
+
+	t.Run("sends users with incomplete profiles to onboarding", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, profile)
+		sessions := &fakeSessionStarter{}
+		h := NewHandler(NewProvider(stub.config()), &fakeUserStore{}, sessions)
+		cookie, q := startLogin(t, h, stub)
+
+		rec := callback(h, cookie, "code=good-code&state="+q.Get("state"))
+
+		g.Expect(rec.Code).To(Equal(http.StatusFound))
+		g.Expect(rec.Header().Get("Location")).To(Equal(OnboardingPath))
+		g.Expect(sessions.started).To(HaveLen(1))
+	})
+
+	t.Run("rejects mismatched state", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, profile)
+		users := &fakeUserStore{}
+		h := NewHandler(NewProvider(stub.config()), users, &fakeSessionStarter{})
+		cookie, _ := startLogin(t, h, stub)
+
+		rec := callback(h, cookie, "code=good-code&state=forged")
+
+		g.Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		g.Expect(users.calls).To(BeEmpty())
+	})
+
+	t.Run("rejects missing state cookie", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, profile)
+		h := NewHandler(NewProvider(stub.config()), &fakeUserStore{}, &fakeSessionStarter{})
+
+		rec := callback(h, nil, "code=good-code&state=anything")
+
+		g.Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	t.Run("fails when token exchange is rejected", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, profile)
+		users := &fakeUserStore{}
+		h := NewHandler(NewProvider(stub.config()), users, &fakeSessionStarter{})
+		cookie, q := startLogin(t, h, stub)
+
+		rec := callback(h, cookie, "code=bad-code&state="+q.Get("state"))
+
+		g.Expect(rec.Code).To(Equal(http.StatusBadGateway))
+		g.Expect(users.calls).To(BeEmpty())
+	})
+
+	t.Run("requires an email from the provider", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, Profile{ID: "fb-123", Name: "No Email"})
+		users := &fakeUserStore{}
+		h := NewHandler(NewProvider(stub.config()), users, &fakeSessionStarter{})
+		cookie, q := startLogin(t, h, stub)
+
+		rec := callback(h, cookie, "code=good-code&state="+q.Get("state"))
+
+		g.Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		g.Expect(users.calls).To(BeEmpty())
+	})
+
+	t.Run("refuses deleted users", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, profile)
+		h := NewHandler(NewProvider(stub.config()), &fakeUserStore{err: repository.ErrUserDeleted}, &fakeSessionStarter{})
+		cookie, q := startLogin(t, h, stub)
+
+		rec := callback(h, cookie, "code=good-code&state="+q.Get("state"))
+
+		g.Expect(rec.Code).To(Equal(http.StatusForbidden))
+	})
+	t.Run("refuses emails linked to another facebook account", func(t *testing.T) {
+		g := NewWithT(t)
+		stub := newStubProvider(t, profile)
+		sessions := &fakeSessionStarter{}
+		h := NewHandler(NewProvider(stub.config()), &fakeUserStore{err: repository.ErrFacebookAccountMismatch}, sessions)
+		cookie, q := startLogin(t, h, stub)
+
+		rec := callback(h, cookie, "code=good-code&state="+q.Get("state"))
+
+		g.Expect(rec.Code).To(Equal(http.StatusConflict))
+		g.Expect(sessions.started).To(BeEmpty())
+	})
+}
@@ -0,0 +1,161 @@
+// Package auth implements login via OAuth2 and related request helpers.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default Facebook endpoints. Tests and local development can point the
+// provider at a stub server by overriding these in ProviderConfig.
+const (
+	FacebookAuthURL     = "https://www.facebook.com/v19.0/dialog/oauth"
+	FacebookTokenURL    = "https://graph.facebook.com/v19.0/oauth/access_token"
+	FacebookUserInfoURL = "https://graph.facebook.com/v19.0/me?fields=id,name,email"
+)
+
+const httpTimeout = 10 * time.Second
+
+// ProviderConfig describes an OAuth2 provider and the registered client.
+type ProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Profile is the identity returned by the provider's user info endpoint.
+type Profile struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Provider performs the OAuth2 authorization-code flow with PKCE.
+type Provider struct {
+	cfg    ProviderConfig
+	client *http.Client
+}
+
+// NewProvider creates a Provider for the given configuration.
+func NewProvider(cfg ProviderConfig) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// AuthCodeURL returns the provider URL the user is redirected to for login.
+// The state is echoed back on the callback; the verifier's S256 challenge
+// binds the later token exchange to this request.
+func (p *Provider) AuthCodeURL(state, verifier string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.cfg.ClientID)
+	v.Set("redirect_uri", p.cfg.RedirectURL)
+	v.Set("state", state)
+	v.Set("code_challenge", codeChallenge(verifier))
+	v.Set("code_challenge_method", "S256")
+	if len(p.cfg.Scopes) > 0 {
+		v.Set("scope", strings.Join(p.cfg.Scopes, ","))
+	}
+
+	sep := "?"
+	if strings.Contains(p.cfg.AuthURL, "?") {
+		sep = "&"
+	}
+	return p.cfg.AuthURL + sep + v.Encode()
+}
+
+// Exchange trades an authorization code for an access token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("building token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.doJSON(req, &tok); err != nil {
+		return "", fmt.Errorf("exchanging code: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", errors.New("exchanging code: response missing access_token")
+	}
+	return tok.AccessToken, nil
+}
+
+// FetchProfile retrieves the authenticated user's profile.
+func (p *Provider) FetchProfile(ctx context.Context, accessToken string) (Profile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.UserInfoURL, nil)
+	if err != nil {
+		return Profile{}, fmt.Errorf("building profile request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var prof Profile
+	if err := p.doJSON(req, &prof); err != nil {
+		return Profile{}, fmt.Errorf("fetching profile: %w", err)
+	}
+	if prof.ID == "" {
+		return Profile{}, errors.New("fetching profile: response missing id")
+	}
+	return prof, nil
+}
+
+func (p *Provider) doJSON(req *http.Request, dst any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// randomToken returns n random bytes encoded as unpadded base64url.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// codeChallenge derives the PKCE S256 challenge for a verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
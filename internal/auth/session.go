@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+const (
+	sessionCookieName = "tfo_session"
+
+	// touchInterval limits how often a session's last-seen time is written.
+	touchInterval = time.Minute
+
+	// rotationGrace is how long a rotated-out token keeps working, so
+	// requests sent with the old cookie while the new one was on its way
+	// aren't logged out.
+	rotationGrace = 30 * time.Second
+)
+
+// SessionConfig controls session lifetime and cookie signing.
+type SessionConfig struct {
+	// Secret is the HMAC key used to sign session cookies.
+	Secret []byte
+	// IdleTimeout ends a session after this long without a request.
+	IdleTimeout time.Duration
+	// MaxAge ends a session this long after login regardless of activity.
+	MaxAge time.Duration
+	// RotateInterval issues a fresh session token this often.
+	RotateInterval time.Duration
+	// Secure marks the session cookie as HTTPS-only.
+	Secure bool
+}
+
+// SessionStore persists server-side sessions.
+type SessionStore interface {
+	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) (model.Session, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (model.Session, error)
+	Touch(ctx context.Context, id uuid.UUID) error
+	Rotate(ctx context.Context, id uuid.UUID, currentHash, tokenHash string) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+// UserGetter loads the user that owns a session.
+type UserGetter interface {
+	GetByID(ctx context.Context, id uuid.UUID) (model.User, error)
+}
+
+// Sessions issues, validates and revokes login sessions.
+type Sessions struct {
+	store SessionStore
+	users UserGetter
+	cfg   SessionConfig
+	now   func() time.Time
+}
+
+// NewSessions creates a session manager.
+func NewSessions(store SessionStore, users UserGetter, cfg SessionConfig) *Sessions {
+	return &Sessions{store: store, users: users, cfg: cfg, now: time.Now}
+}
+
+// Start creates a new session for userID and sets its cookie.
+func (s *Sessions) Start(ctx context.Context, w http.ResponseWriter, userID uuid.UUID) error {
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	expiresAt := s.now().Add(s.cfg.MaxAge)
+	if _, err := s.store.Create(ctx, userID, hashToken(token), expiresAt); err != nil {
+		return fmt.Errorf("starting session: %w", err)
+	}
+	s.setCookie(w, token, expiresAt)
+	return nil
+}
+
+// Middleware loads the session's user into the request context. Requests
+// without a valid session pass through anonymously. Tokens older than the
+// rotation interval are replaced transparently.
+func (s *Sessions) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, ok := s.load(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := s.users.GetByID(r.Context(), sess.UserID)
+		if err != nil {
+			if !errors.Is(err, repository.ErrNotFound) {
+				log.Printf("loading session user: %v", err)
+			}
+			s.clearCookie(w)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s.refresh(r.Context(), w, sess)
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	})
+}
+
+// Logout revokes the current session server-side, clears the cookie and
+// redirects home.
+func (s *Sessions) Logout(w http.ResponseWriter, r *http.Request) {
+	if sess, ok := s.load(r); ok {
+		if err := s.store.Revoke(r.Context(), sess.ID); err != nil {
+			log.Printf("logout: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	s.clearCookie(w)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// load returns the valid session referenced by the request cookie.
+func (s *Sessions) load(r *http.Request) (model.Session, bool) {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return model.Session{}, false
+	}
+	token, ok := s.verify(cookie.Value)
+	if !ok {
+		return model.Session{}, false
+	}
+
+	hash := hashToken(token)
+	sess, err := s.store.GetByTokenHash(r.Context(), hash)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("loading session: %v", err)
+		}
+		return model.Session{}, false
+	}
+	if !sess.IsValid(s.now(), s.cfg.IdleTimeout) {
+		return model.Session{}, false
+	}
+	if sess.TokenHash != hash && s.now().Sub(sess.RotatedAt) >= rotationGrace {
+		// The cookie's token was rotated out and its grace period is over.
+		return model.Session{}, false
+	}
+	return sess, true
+}
+
+// refresh rotates a stale token or records activity. Failures are logged
+// rather than failing the request, since the current token remains valid.
+// Losing a race to rotate leaves the cookie alone: the winner's response
+// carries the new token and this one's stays valid for rotationGrace.
+func (s *Sessions) refresh(ctx context.Context, w http.ResponseWriter, sess model.Session) {
+	now := s.now()
+	if now.Sub(sess.RotatedAt) >= s.cfg.RotateInterval {
+		token, err := randomToken(32)
+		if err != nil {
+			log.Printf("rotating session: %v", err)
+			return
+		}
+		err = s.store.Rotate(ctx, sess.ID, sess.TokenHash, hashToken(token))
+		if errors.Is(err, repository.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			log.Printf("rotating session: %v", err)
+			return
+		}
+		s.setCookie(w, token, sess.ExpiresAt)
+		return
+	}
+	if now.Sub(sess.LastSeenAt) >= touchInterval {
+		if err := s.store.Touch(ctx, sess.ID); err != nil {
+			log.Printf("touching session: %v", err)
+		}
+	}
+}
+
+func (s *Sessions) setCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token + "." + s.sign(token),
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   s.cfg.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (s *Sessions) clearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.cfg.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (s *Sessions) sign(token string) string {
+	mac := hmac.New(sha256.New, s.cfg.Secret)
+	mac.Write([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a cookie value's signature and returns the bare token.
+func (s *Sessions) verify(value string) (string, bool) {
+	token, sig, ok := strings.Cut(value, ".")
+	if !ok || token == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(token))) {
+		return "", false
+	}
+	return token, true
+}
+
+// hashToken returns the value stored server-side for a session token, so a
+// leaked sessions table cannot be replayed as cookies.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+type fakeSessionStore struct {
+	// byHash indexes sessions by their current and previous token hashes.
+	byHash  map[string]*model.Session
+	now     func() time.Time
+	touched int
+}
+
+func newFakeSessionStore(now func() time.Time) *fakeSessionStore {
+	return &fakeSessionStore{byHash: map[string]*model.Session{}, now: now}
+}
+
+func (f *fakeSessionStore) Create(_ context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) (model.Session, error) {
+	now := f.now()
+	s := &model.Session{
+		ID: uuid.New(), UserID: userID, TokenHash: tokenHash,
+		CreatedAt: now, LastSeenAt: now, RotatedAt: now, ExpiresAt: expiresAt,
+	}
+	f.byHash[tokenHash] = s
+	return *s, nil
+}
+
+func (f *fakeSessionStore) GetByTokenHash(_ context.Context, tokenHash string) (model.Session, error) {
+	s, ok := f.byHash[tokenHash]
+	if !ok {
+		return model.Session{}, repository.ErrNotFound
+	}
+	return *s, nil
+}
+
+func (f *fakeSessionStore) find(id uuid.UUID) *model.Session {
+	for _, s := range f.byHash {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessionStore) Touch(_ context.Context, id uuid.UUID) error {
+	s := f.find(id)
+	s.LastSeenAt = f.now()
+	f.touched++
+	return nil
+}
+
+func (f *fakeSessionStore) Rotate(_ context.Context, id uuid.UUID, currentHash, tokenHash string) error {
+	s := f.find(id)
+	if s.TokenHash != currentHash || s.IsRevoked() {
+		return repository.ErrNotFound
+	}
+	if s.PreviousTokenHash.Valid {
+		delete(f.byHash, s.PreviousTokenHash.String)
+	}
+	s.PreviousTokenHash = sql.NullString{String: currentHash, Valid: true}
+	s.TokenHash = tokenHash
+	s.RotatedAt = f.now()
+	s.LastSeenAt = f.now()
+	f.byHash[tokenHash] = s
+	return nil
+}
+
+func (f *fakeSessionStore) Revoke(_ context.Context, id uuid.UUID) error {
+	s := f.find(id)
+	s.RevokedAt = sql.NullTime{Time: f.now(), Valid: true}
+	return nil
+}
+
+type fakeUserGetter map[uuid.UUID]model.User
+
+func (f fakeUserGetter) GetByID(_ context.Context, id uuid.UUID) (model.User, error) {
+	u, ok := f[id]
+	if !ok {
+		return model.User{}, repository.ErrNotFound
+	}
+	return u, nil
+}
+
+type sessionFixture struct {
+	sessions *Sessions
+	store    *fakeSessionStore
+	user     model.User
+	clock    time.Time
+}
+
+func newSessionFixture() *sessionFixture {
+	f := &sessionFixture{
+		user:  model.User{ID: uuid.New(), Name: "Jane Doe"},
+		clock: time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+	now := func() time.Time { return f.clock }
+	f.store = newFakeSessionStore(now)
+	f.sessions = NewSessions(f.store, fakeUserGetter{f.user.ID: f.user}, SessionConfig{
+		Secret:         []byte("test-secret"),
+		IdleTimeout:    time.Hour,
+		MaxAge:         24 * time.Hour,
+		RotateInterval: 30 * time.Minute,
+	})
+	f.sessions.now = now
+	return f
+}
+
+// login starts a session and returns its cookie.
+func (f *sessionFixture) login(t *testing.T) *http.Cookie {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	if err := f.sessions.Start(t.Context(), rec, f.user.ID); err != nil {
+		t.Fatalf("starting session: %v", err)
+	}
+	return rec.Result().Cookies()[0]
+}
+
+// serve runs a request through the middleware and reports the user seen
+// by the wrapped handler.
+func (f *sessionFixture) serve(cookie *http.Cookie) (*httptest.ResponseRecorder, model.User, bool) {
+	var (
+		seen   model.User
+		loaded bool
+	)
+	h := f.sessions.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen, loaded = UserFromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, seen, loaded
+}
+
+func TestSessions_Middleware(t *testing.T) {
+	t.Run("loads user for a valid session", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		cookie := f.login(t)
+
+		_, user, ok := f.serve(cookie)
+
+		g.Expect(ok).To(BeTrue())
+		g.Expect(user.ID).To(Equal(f.user.ID))
+	})
+
+	t.Run("passes anonymous requests through", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+
+		_, _, ok := f.serve(nil)
+
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("ignores cookies with a bad signature", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		cookie := f.login(t)
+		cookie.Value += "x"
+
+		_, _, ok := f.serve(cookie)
+
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("expires idle sessions", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		cookie := f.login(t)
+		f.clock = f.clock.Add(time.Hour)
+
+		_, _, ok := f.serve(cookie)
+
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("expires sessions at max age despite activity", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		cookie := f.login(t)
+		for range 30 {
+			f.clock = f.clock.Add(50 * time.Minute)
+			rec, _, _ := f.serve(cookie)
+			if rotated := rec.Result().Cookies(); len(rotated) > 0 {
+				cookie = rotated[0]
+			}
+		}
+
+		_, _, ok := f.serve(cookie)
+
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("rotates stale tokens and invalidates the old cookie after a grace period", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		old := f.login(t)
+		f.clock = f.clock.Add(45 * time.Minute)
+
+		rec, _, ok := f.serve(old)
+		g.Expect(ok).To(BeTrue())
+		cookies := rec.Result().Cookies()
+		g.Expect(cookies).To(HaveLen(1))
+		g.Expect(cookies[0].Value).ToNot(Equal(old.Value))
+
+		f.clock = f.clock.Add(rotationGrace)
+		_, _, ok = f.serve(old)
+		g.Expect(ok).To(BeFalse())
+		_, _, ok = f.serve(cookies[0])
+		g.Expect(ok).To(BeTrue())
+	})
+
+	t.Run("accepts the old cookie just after rotation without rotating again", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		old := f.login(t)
+		f.clock = f.clock.Add(45 * time.Minute)
+		rec, _, _ := f.serve(old)
+		rotated := rec.Result().Cookies()[0]
+
+		// A request sent before the browser stored the new cookie.
+		f.clock = f.clock.Add(time.Second)
+		rec, user, ok := f.serve(old)
+
+		g.Expect(ok).To(BeTrue())
+		g.Expect(user.ID).To(Equal(f.user.ID))
+		g.Expect(rec.Result().Cookies()).To(BeEmpty())
+		_, _, ok = f.serve(rotated)
+		g.Expect(ok).To(BeTrue())
+	})
+
+	t.Run("records activity without rotating", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		cookie := f.login(t)
+		f.clock = f.clock.Add(5 * time.Minute)
+
+		rec, _, _ := f.serve(cookie)
+
+		g.Expect(rec.Result().Cookies()).To(BeEmpty())
+		g.Expect(f.store.touched).To(Equal(1))
+	})
+}
+
+func TestSessions_Logout(t *testing.T) {
+	t.Run("revokes the session server-side", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newSessionFixture()
+		cookie := f.login(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		f.sessions.Logout(rec, req)
+
+		g.Expect(rec.Code).To(Equal(http.StatusSeeOther))
+		g.Expect(rec.Result().Cookies()[0].MaxAge).To(BeNumerically("<", 0))
+
+		_, _, ok := f.serve(cookie)
+		g.Expect(ok).To(BeFalse())
+	})
+}
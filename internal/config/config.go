@@ -6,26 +6,74 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/mail"
 )
 
 // Config holds all application configuration.
 type Config struct {
 	Addr        string
+	BaseURL     string
 	DatabaseURL string
+	OAuth       auth.ProviderConfig
+	Session     auth.SessionConfig
+	Mail        mail.Config
 }
 
+// minSessionSecretLen is the shortest accepted SESSION_SECRET, in bytes.
+const minSessionSecretLen = 32
+
 // Load reads configuration from environment variables and flags.
 // Secrets come from environment variables; non-secret config from flags.
 func Load() (Config, error) {
 	var cfg Config
 
 	flag.StringVar(&cfg.Addr, "addr", ":8080", "HTTP listen address")
+	flag.StringVar(&cfg.BaseURL, "base-url", "http://localhost:8080", "Public base URL of the site")
+	flag.StringVar(&cfg.OAuth.ClientID, "facebook-client-id", "", "Facebook OAuth client ID")
+	flag.StringVar(&cfg.OAuth.AuthURL, "oauth-auth-url", auth.FacebookAuthURL, "OAuth authorization endpoint")
+	flag.StringVar(&cfg.OAuth.TokenURL, "oauth-token-url", auth.FacebookTokenURL, "OAuth token endpoint")
+	flag.StringVar(&cfg.OAuth.UserInfoURL, "oauth-userinfo-url", auth.FacebookUserInfoURL, "OAuth user info endpoint")
+	flag.DurationVar(&cfg.Session.IdleTimeout, "session-idle-timeout", 72*time.Hour, "Log out after this long without activity")
+	flag.DurationVar(&cfg.Session.MaxAge, "session-max-age", 30*24*time.Hour, "Log out this long after login regardless of activity")
+	flag.DurationVar(&cfg.Session.RotateInterval, "session-rotate-interval", time.Hour, "Issue a fresh session token this often")
+	flag.StringVar(&cfg.Mail.Transport, "mail-transport", mail.TransportStdout, "How to send email: smtp, file or stdout")
+	flag.StringVar(&cfg.Mail.From, "mail-from", "The Fallen Outdoors <noreply@thefallenoutdoors.org>", "Sender address for outgoing email")
+	flag.StringVar(&cfg.Mail.Dir, "mail-dir", "tmp/mail", "Directory the file mail transport writes to")
+	flag.StringVar(&cfg.Mail.SMTPAddr, "smtp-addr", "localhost:587", "SMTP relay host:port")
+	flag.StringVar(&cfg.Mail.SMTPUsername, "smtp-username", "", "SMTP username; empty sends unauthenticated")
 	flag.Parse()
 
-	cfg.DatabaseURL = os.Getenv("DATABASE_URL")
-	if cfg.DatabaseURL == "" {
-		return Config{}, fmt.Errorf("DATABASE_URL environment variable is required")
+	var err error
+	if cfg.DatabaseURL, err = DatabaseURL(); err != nil {
+		return Config{}, err
+	}
+
+	secret := os.Getenv("SESSION_SECRET")
+	if len(secret) < minSessionSecretLen {
+		return Config{}, fmt.Errorf("SESSION_SECRET environment variable must be at least %d bytes", minSessionSecretLen)
 	}
+	cfg.Session.Secret = []byte(secret)
+
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	cfg.Session.Secure = strings.HasPrefix(cfg.BaseURL, "https://")
+	cfg.OAuth.ClientSecret = os.Getenv("FACEBOOK_CLIENT_SECRET")
+	cfg.Mail.SMTPPassword = os.Getenv("SMTP_PASSWORD")
+	cfg.OAuth.RedirectURL = cfg.BaseURL + "/auth/facebook/callback"
+	cfg.OAuth.Scopes = []string{"email", "public_profile"}
 
 	return cfg, nil
 }
+
+// DatabaseURL reads the database connection string from DATABASE_URL.
+// Command-line tools that only need the database use it instead of Load.
+func DatabaseURL() (string, error) {
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		return "", fmt.Errorf("DATABASE_URL environment variable is required")
+	}
+	return url, nil
+}
@@ -0,0 +1,279 @@
+// Package confirmation implements selected members accepting or declining
+// their place on a hunt, and offering places that free up to the next
+// alternates in draw order.
+package confirmation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/jobs"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+var (
+	// ErrInvalidDecision is returned for decisions other than accept or
+	// decline.
+	ErrInvalidDecision = errors.New("invalid decision")
+	// ErrAlreadyResponded is returned when the confirmation was already
+	// answered or has expired.
+	ErrAlreadyResponded = errors.New("already responded")
+	// ErrDeadlinePassed is returned when responding after the deadline.
+	ErrDeadlinePassed = errors.New("confirmation deadline has passed")
+	// ErrHuntNotClosed is returned when responding to a selection on a
+	// hunt that isn't closed, such as one that was cancelled or has
+	// already taken place.
+	ErrHuntNotClosed = errors.New("hunt is no longer taking responses")
+	// ErrCannotWithdraw is returned when withdrawing a place that wasn't
+	// accepted, or after the hunt has finished.
+	ErrCannotWithdraw = errors.New("place can't be withdrawn")
+)
+
+// Notifier tells members they've been offered a place on a hunt.
+type Notifier interface {
+	PlaceOffered(ctx context.Context, member model.User, hunt model.Hunt, c model.Confirmation, promoted bool) error
+}
+
+// JobPlaceOffered is the kind of job that tells a member they've been
+// offered a place and asks them to confirm it.
+const JobPlaceOffered = "confirmation.place_offered"
+
+type placeOfferedJob struct {
+	ConfirmationID uuid.UUID `json:"confirmation_id"`
+	Promoted       bool      `json:"promoted"`
+}
+
+// Service records members' answers to their selections.
+type Service struct {
+	db       *sql.DB
+	notifier Notifier
+}
+
+// NewService creates a confirmation Service.
+func NewService(db *sql.DB, notifier Notifier) *Service {
+	return &Service{db: db, notifier: notifier}
+}
+
+// Offer records c, offering its member a place on the hunt, and enqueues
+// the notification asking them to confirm. Promoted marks a place offered
+// to an alternate rather than drawn. Call it with the transaction making
+// the offer.
+func Offer(ctx context.Context, tx *sql.Tx, c model.Confirmation, promoted bool) (model.Confirmation, error) {
+	created, err := repository.NewConfirmationRepository(tx).Create(ctx, c)
+	if err != nil {
+		return model.Confirmation{}, err
+	}
+	if err := jobs.Enqueue(ctx, tx, JobPlaceOffered, placeOfferedJob{ConfirmationID: created.ID, Promoted: promoted}); err != nil {
+		return model.Confirmation{}, err
+	}
+	return created, nil
+}
+
+// RegisterJobs registers the handlers for the jobs the Service enqueues.
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	w.Register(JobPlaceOffered, jobs.Handle(s.placeOffered))
+}
+
+func (s *Service) placeOffered(ctx context.Context, job placeOfferedJob) error {
+	c, err := repository.NewConfirmationRepository(s.db).GetByID(ctx, job.ConfirmationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	if !c.IsPending() {
+		// Answered, expired or cancelled before the job ran; there's
+		// nothing left to ask.
+		return nil
+	}
+	hunt, err := repository.NewHuntRepository(s.db).GetByID(ctx, c.HuntID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	member, err := repository.NewUserRepository(s.db).GetByID(ctx, c.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	return s.notifier.PlaceOffered(ctx, member, hunt, c, job.Promoted)
+}
+
+// Respond records member's decision on their confirmation. Declining
+// offers the freed place to the next alternate in the same transaction.
+// Returns repository.ErrNotFound if the confirmation isn't the member's.
+func (s *Service) Respond(ctx context.Context, member model.User, id uuid.UUID, decision model.ConfirmationDecision, reason string, now time.Time) (model.Confirmation, error) {
+	var updated model.Confirmation
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		confirmations := repository.NewConfirmationRepository(tx)
+		c, hunt, err := lock(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := validateResponse(c, hunt, member, decision, now); err != nil {
+			return err
+		}
+		if err := confirmations.Respond(ctx, c.ID, decision, strings.TrimSpace(reason)); err != nil {
+			return err
+		}
+		if decision == model.ConfirmationDeclined {
+			if _, err := promote(ctx, tx, hunt, now); err != nil {
+				return err
+			}
+		}
+		updated, err = confirmations.GetByID(ctx, c.ID)
+		return err
+	})
+	if err != nil {
+		return model.Confirmation{}, fmt.Errorf("responding to confirmation: %w", err)
+	}
+	return updated, nil
+}
+
+// Withdraw gives up a place member already accepted, offering it to the
+// next alternate. Places can be withdrawn until the hunt is completed.
+func (s *Service) Withdraw(ctx context.Context, member model.User, id uuid.UUID, reason string, now time.Time) (model.Confirmation, error) {
+	var updated model.Confirmation
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		confirmations := repository.NewConfirmationRepository(tx)
+		c, hunt, err := lock(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if c.UserID != member.ID {
+			return repository.ErrNotFound
+		}
+		if !c.IsFinal() || hunt.Status != model.HuntStatusClosed {
+			return ErrCannotWithdraw
+		}
+		if err := confirmations.Withdraw(ctx, c.ID, strings.TrimSpace(reason)); err != nil {
+			return err
+		}
+		if _, err := promote(ctx, tx, hunt, now); err != nil {
+			return err
+		}
+		updated, err = confirmations.GetByID(ctx, c.ID)
+		return err
+	})
+	if err != nil {
+		return model.Confirmation{}, fmt.Errorf("withdrawing confirmation: %w", err)
+	}
+	return updated, nil
+}
+
+// Promote expires the hunt's confirmations that are past their deadline
+// and offers every free place to the next alternates, returning how many
+// were offered a place. It is safe to call repeatedly: places are only
+// offered while the hunt is short of its primary capacity.
+func (s *Service) Promote(ctx context.Context, huntID uuid.UUID, now time.Time) (int, error) {
+	var promoted int
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		hunt, err := repository.NewHuntRepository(tx).GetByIDForUpdate(ctx, huntID)
+		if err != nil {
+			return err
+		}
+		promoted, err = promote(ctx, tx, hunt, now)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("promoting alternates: %w", err)
+	}
+	return promoted, nil
+}
+
+// lock takes the row locks for answering confirmation id: the hunt's first,
+// as the drawer and Promote do, then the confirmation's.
+func lock(ctx context.Context, tx *sql.Tx, id uuid.UUID) (model.Confirmation, model.Hunt, error) {
+	confirmations := repository.NewConfirmationRepository(tx)
+	c, err := confirmations.GetByID(ctx, id)
+	if err != nil {
+		return model.Confirmation{}, model.Hunt{}, err
+	}
+	hunt, err := repository.NewHuntRepository(tx).GetByIDForUpdate(ctx, c.HuntID)
+	if err != nil {
+		return model.Confirmation{}, model.Hunt{}, err
+	}
+	c, err = confirmations.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		return model.Confirmation{}, model.Hunt{}, err
+	}
+	return c, hunt, nil
+}
+
+// promote fills hunt's free primary places from its alternates in draw
+// order. Callers hold the hunt's row lock, so concurrent promotions can't
+// offer the same place twice. Only closed hunts promote; completed and
+// cancelled hunts keep their final roster.
+func promote(ctx context.Context, tx *sql.Tx, hunt model.Hunt, now time.Time) (int, error) {
+	if hunt.Status != model.HuntStatusClosed {
+		return 0, nil
+	}
+	confirmations := repository.NewConfirmationRepository(tx)
+	if _, err := confirmations.ExpireOverdue(ctx, hunt.ID, now); err != nil {
+		return 0, err
+	}
+	held, err := confirmations.CountHoldingByHunt(ctx, hunt.ID)
+	if err != nil {
+		return 0, err
+	}
+	free := openPlaces(hunt, held)
+	if free == 0 {
+		return 0, nil
+	}
+
+	candidates, err := repository.NewLotteryResultRepository(tx).ListCandidates(ctx, hunt.ID, hunt.TotalCapacity(), free)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range candidates {
+		if _, err := Offer(ctx, tx, model.Confirmation{
+			LotteryResultID: c.Result.ID,
+			HuntID:          hunt.ID,
+			UserID:          c.UserID,
+			Deadline:        hunt.ConfirmationDeadline(now),
+		}, true); err != nil {
+			return 0, err
+		}
+	}
+	return len(candidates), nil
+}
+
+// openPlaces returns how many of hunt's primary places aren't held by a
+// pending or accepted confirmation.
+func openPlaces(hunt model.Hunt, held int) int {
+	return max(hunt.PrimaryCapacity-held, 0)
+}
+
+// validateResponse checks that member may answer c, a selection on hunt,
+// with decision at now. Selections are only answered while the hunt is
+// closed: after the draw and before it takes place or is cancelled.
+func validateResponse(c model.Confirmation, hunt model.Hunt, member model.User, decision model.ConfirmationDecision, now time.Time) error {
+	if c.UserID != member.ID {
+		return repository.ErrNotFound
+	}
+	if decision != model.ConfirmationAccepted && decision != model.ConfirmationDeclined {
+		return ErrInvalidDecision
+	}
+	if hunt.Status != model.HuntStatusClosed {
+		return ErrHuntNotClosed
+	}
+	if !c.IsPending() {
+		return ErrAlreadyResponded
+	}
+	if !c.CanRespond(now) {
+		return ErrDeadlinePassed
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package confirmation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestValidateResponse(t *testing.T) {
+	member := model.User{ID: uuid.New()}
+	deadline := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pending := model.Confirmation{UserID: member.ID, Decision: model.ConfirmationPending, Deadline: deadline}
+	before := deadline.Add(-time.Hour)
+	closed := model.Hunt{Status: model.HuntStatusClosed}
+
+	t.Run("allows the member to accept before the deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(validateResponse(pending, closed, member, model.ConfirmationAccepted, before)).To(Succeed())
+	})
+
+	t.Run("hides other members' confirmations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateResponse(pending, closed, model.User{ID: uuid.New()}, model.ConfirmationAccepted, before)
+
+		g.Expect(err).To(MatchError(repository.ErrNotFound))
+	})
+
+	t.Run("rejects decisions other than accept or decline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateResponse(pending, closed, member, model.ConfirmationExpired, before)
+
+		g.Expect(err).To(MatchError(ErrInvalidDecision))
+	})
+
+	t.Run("rejects answers once the hunt is no longer closed", func(t *testing.T) {
+		for _, status := range []model.HuntStatus{model.HuntStatusCancelled, model.HuntStatusCompleted} {
+			g := NewWithT(t)
+
+			err := validateResponse(pending, model.Hunt{Status: status}, member, model.ConfirmationAccepted, before)
+
+			g.Expect(err).To(MatchError(ErrHuntNotClosed), string(status))
+		}
+	})
+
+	t.Run("rejects a second answer", func(t *testing.T) {
+		g := NewWithT(t)
+		answered := pending
+		answered.Decision = model.ConfirmationDeclined
+
+		err := validateResponse(answered, closed, member, model.ConfirmationAccepted, before)
+
+		g.Expect(err).To(MatchError(ErrAlreadyResponded))
+	})
+
+	t.Run("rejects answers after the deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateResponse(pending, closed, member, model.ConfirmationAccepted, deadline)
+
+		g.Expect(err).To(MatchError(ErrDeadlinePassed))
+	})
+}
+
+func TestOpenPlaces(t *testing.T) {
+	t.Run("counts places not held", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(openPlaces(model.Hunt{PrimaryCapacity: 10}, 7)).To(Equal(3))
+	})
+
+	t.Run("never goes negative", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(openPlaces(model.Hunt{PrimaryCapacity: 2}, 3)).To(BeZero())
+	})
+}
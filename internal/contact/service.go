@@ -0,0 +1,186 @@
+// Package contact implements contact form submissions: routing them to
+// the right regional leader, and staff triage through assignment, status
+// and internal notes.
+package contact
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/jobs"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+var (
+	// ErrNotStaff is returned when someone other than active staff
+	// triages a submission.
+	ErrNotStaff = errors.New("only staff can triage contact submissions")
+	// ErrInvalidStatus is returned for an unknown triage status.
+	ErrInvalidStatus = errors.New("invalid contact status")
+	// ErrAssigneeNotStaff is returned when assigning a submission to
+	// someone who isn't active staff.
+	ErrAssigneeNotStaff = errors.New("submissions can only be assigned to staff")
+	// ErrNoteRequired is returned when adding an empty note.
+	ErrNoteRequired = errors.New("a note is required")
+)
+
+// Leader is the regional director told about messages sent to their
+// region.
+type Leader struct {
+	RegionID string
+	Name     string
+	Email    string
+}
+
+// Notifier tells a regional leader about a message sent to their region.
+type Notifier interface {
+	MessageReceived(ctx context.Context, leader Leader, sub model.ContactSubmission) error
+}
+
+// JobMessageReceived is the kind of job that tells a regional leader
+// about a message sent to their region.
+const JobMessageReceived = "contact.message_received"
+
+type messageReceivedJob struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	Leader       Leader    `json:"leader"`
+}
+
+// Service stores and triages contact submissions.
+type Service struct {
+	db       *sql.DB
+	notifier Notifier
+}
+
+// NewService creates a contact Service.
+func NewService(db *sql.DB, notifier Notifier) *Service {
+	return &Service{db: db, notifier: notifier}
+}
+
+// Submit stores a contact form submission. A message sent to a region is
+// recorded against it and a job to tell its leader is enqueued with it,
+// so a failure to reach the leader is retried rather than losing the
+// message. leader is nil for messages not sent to a region or sent to one
+// without a leader.
+func (s *Service) Submit(ctx context.Context, sub model.ContactSubmission, leader *Leader) (model.ContactSubmission, error) {
+	if leader != nil {
+		sub.RegionID = sql.NullString{String: leader.RegionID, Valid: true}
+	}
+	var stored model.ContactSubmission
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		stored, err = repository.NewContactRepository(tx).Insert(ctx, sub)
+		if err != nil || leader == nil {
+			return err
+		}
+		return jobs.Enqueue(ctx, tx, JobMessageReceived, messageReceivedJob{SubmissionID: stored.ID, Leader: *leader})
+	})
+	if err != nil {
+		return model.ContactSubmission{}, fmt.Errorf("submitting contact message: %w", err)
+	}
+	return stored, nil
+}
+
+// RegisterJobs registers the handlers for the jobs the Service enqueues.
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	w.Register(JobMessageReceived, jobs.Handle(s.messageReceived))
+}
+
+func (s *Service) messageReceived(ctx context.Context, job messageReceivedJob) error {
+	sub, err := repository.NewContactRepository(s.db).GetByID(ctx, job.SubmissionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	return s.notifier.MessageReceived(ctx, job.Leader, sub)
+}
+
+// Triage sets a submission's status and assignee. It returns
+// ErrAssigneeNotStaff, leaving the submission unchanged, if the assignee
+// isn't active staff; the handler shows that as a 422.
+func (s *Service) Triage(ctx context.Context, staff model.User, id uuid.UUID, status model.ContactStatus, assignee uuid.NullUUID) (model.ContactSubmission, error) {
+	if err := checkStaff(staff); err != nil {
+		return model.ContactSubmission{}, err
+	}
+	if !status.IsValid() {
+		return model.ContactSubmission{}, fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+
+	var updated model.ContactSubmission
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		if assignee.Valid {
+			u, err := repository.NewUserRepository(tx).GetByID(ctx, assignee.UUID)
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrAssigneeNotStaff
+			}
+			if err != nil {
+				return err
+			}
+			if err := checkAssignee(u); err != nil {
+				return err
+			}
+		}
+		var err error
+		updated, err = repository.NewContactRepository(tx).UpdateTriage(ctx, id, status, assignee)
+		return err
+	})
+	if err != nil {
+		return model.ContactSubmission{}, fmt.Errorf("triaging contact submission: %w", err)
+	}
+	return updated, nil
+}
+
+// AddNote records an internal note on a submission.
+func (s *Service) AddNote(ctx context.Context, staff model.User, id uuid.UUID, body string) (model.ContactNote, error) {
+	if err := checkStaff(staff); err != nil {
+		return model.ContactNote{}, err
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return model.ContactNote{}, ErrNoteRequired
+	}
+
+	var note model.ContactNote
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		contacts := repository.NewContactRepository(tx)
+		if _, err := contacts.GetByID(ctx, id); err != nil {
+			return err
+		}
+		var err error
+		note, err = contacts.InsertNote(ctx, model.ContactNote{SubmissionID: id, AuthorID: staff.ID, Body: body})
+		return err
+	})
+	if err != nil {
+		return model.ContactNote{}, fmt.Errorf("adding contact note: %w", err)
+	}
+	return note, nil
+}
+
+// checkStaff returns ErrNotStaff unless u is active staff.
+func checkStaff(u model.User) error {
+	if !isActiveStaff(u) {
+		return ErrNotStaff
+	}
+	return nil
+}
+
+// checkAssignee returns ErrAssigneeNotStaff unless u can be assigned
+// submissions.
+func checkAssignee(u model.User) error {
+	if !isActiveStaff(u) {
+		return ErrAssigneeNotStaff
+	}
+	return nil
+}
+
+func isActiveStaff(u model.User) bool {
+	return u.Role.AtLeast(model.RoleStaff) && u.IsActive()
+}
@@ -0,0 +1,48 @@
+package contact
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestCheckStaff(t *testing.T) {
+	t.Run("allows active staff and admins", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, role := range []model.Role{model.RoleStaff, model.RoleAdmin} {
+			u := model.User{ID: uuid.New(), Role: role, MembershipStatus: model.MembershipActive}
+			g.Expect(checkStaff(u)).To(Succeed())
+			g.Expect(checkAssignee(u)).To(Succeed())
+		}
+	})
+
+	t.Run("rejects members", func(t *testing.T) {
+		g := NewWithT(t)
+
+		u := model.User{ID: uuid.New(), Role: model.RoleMember, MembershipStatus: model.MembershipActive}
+
+		g.Expect(checkStaff(u)).To(MatchError(ErrNotStaff))
+		g.Expect(checkAssignee(u)).To(MatchError(ErrAssigneeNotStaff))
+	})
+
+	t.Run("rejects suspended and deleted staff", func(t *testing.T) {
+		g := NewWithT(t)
+
+		suspended := model.User{ID: uuid.New(), Role: model.RoleStaff, MembershipStatus: model.MembershipSuspended}
+		deleted := model.User{
+			ID:               uuid.New(),
+			Role:             model.RoleStaff,
+			MembershipStatus: model.MembershipActive,
+			DeletedAt:        sql.NullTime{Time: time.Now(), Valid: true},
+		}
+
+		g.Expect(checkAssignee(suspended)).To(MatchError(ErrAssigneeNotStaff))
+		g.Expect(checkAssignee(deleted)).To(MatchError(ErrAssigneeNotStaff))
+	})
+}
@@ -0,0 +1,171 @@
+package aaradmin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/aar"
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/aaradmin"
+)
+
+// Handler handles the staff after-action report editor.
+type Handler struct {
+	hunts   *repository.HuntRepository
+	users   *repository.UserRepository
+	reports *repository.AARRepository
+	service *aar.Service
+}
+
+// NewHandler creates an aaradmin Handler.
+func NewHandler(hunts *repository.HuntRepository, users *repository.UserRepository, reports *repository.AARRepository, service *aar.Service) *Handler {
+	return &Handler{hunts: hunts, users: users, reports: reports, service: service}
+}
+
+// Edit renders the hunt's report, or an empty form if it has none yet.
+func (h *Handler) Edit(w http.ResponseWriter, r *http.Request) {
+	hunt, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	form, err := h.savedForm(r, hunt.ID)
+	if err != nil {
+		log.Printf("getting after-action report: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.render(w, r, http.StatusOK, page.Props{Hunt: hunt, Form: form})
+}
+
+// Save creates or updates the hunt's report.
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	hunt, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := page.Form{
+		ReportID:     r.FormValue("report_id"),
+		Description:  r.FormValue("description"),
+		ImageURLs:    r.FormValue("image_urls"),
+		Participants: r.PostForm["participants"],
+	}
+	report, participants, errs := form.Validate(hunt.ID)
+	if len(errs) > 0 {
+		h.render(w, r, http.StatusUnprocessableEntity, page.Props{Hunt: hunt, Form: form, Errors: errs})
+		return
+	}
+
+	var err error
+	if form.IsNew() {
+		staff, _ := auth.UserFromContext(r.Context())
+		report.CreatedByID = staff.ID
+		_, err = h.service.Create(r.Context(), report, participants)
+	} else {
+		_, err = h.service.Update(r.Context(), report, participants)
+	}
+	if !h.saved(w, r, hunt, form, err) {
+		return
+	}
+
+	saved, err := h.savedForm(r, hunt.ID)
+	if err != nil {
+		log.Printf("getting after-action report: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.render(w, r, http.StatusOK, page.Props{Hunt: hunt, Form: saved, Message: "Report saved."})
+}
+
+// saved handles the outcome of saving a report, writing an error response
+// and returning false if it failed.
+func (h *Handler) saved(w http.ResponseWriter, r *http.Request, hunt model.Hunt, form page.Form, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, aar.ErrAARExists):
+		h.render(w, r, http.StatusConflict, page.Props{Hunt: hunt, Form: form, Error: saveMessage(err)})
+	case errors.Is(err, aar.ErrHuntNotCompleted), errors.Is(err, aar.ErrNotOnRoster), errors.Is(err, repository.ErrNotFound):
+		h.render(w, r, http.StatusUnprocessableEntity, page.Props{Hunt: hunt, Form: form, Error: saveMessage(err)})
+	default:
+		log.Printf("saving after-action report: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+	return false
+}
+
+// savedForm returns the editor form for the hunt's saved report, or an
+// empty form if it has none.
+func (h *Handler) savedForm(r *http.Request, huntID uuid.UUID) (page.Form, error) {
+	report, err := h.reports.GetByHunt(r.Context(), huntID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return page.Form{}, nil
+	}
+	if err != nil {
+		return page.Form{}, err
+	}
+	participants, err := h.reports.ListParticipants(r.Context(), report.ID)
+	if err != nil {
+		return page.Form{}, err
+	}
+	return page.FormFromReport(report, participants), nil
+}
+
+// load fetches the hunt named in the path, writing 404 or 500 and
+// returning false if it can't.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request) (model.Hunt, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return model.Hunt{}, false
+	}
+	hunt, err := h.hunts.GetByID(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.NotFound(w, r)
+		return model.Hunt{}, false
+	}
+	if err != nil {
+		log.Printf("getting hunt: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return model.Hunt{}, false
+	}
+	return hunt, true
+}
+
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, status int, props page.Props) {
+	roster, err := h.users.ListConfirmed(r.Context(), props.Hunt.ID)
+	if err != nil {
+		log.Printf("listing confirmed roster: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	props.Roster = roster
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Editor(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+func saveMessage(err error) string {
+	switch {
+	case errors.Is(err, aar.ErrAARExists):
+		return "This hunt already has an after-action report, probably written by another staff member while you were editing. Reload the page to edit it."
+	case errors.Is(err, aar.ErrHuntNotCompleted):
+		return "Reports can only be written for completed hunts."
+	case errors.Is(err, aar.ErrNotOnRoster):
+		return "Participants must be members who accepted a place on this hunt."
+	default:
+		return "This report couldn't be found. Reload the page and try again."
+	}
+}
@@ -0,0 +1,156 @@
+package confirmation
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/confirmation"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/confirmation"
+)
+
+// Handler handles selected members confirming their place on a hunt.
+type Handler struct {
+	confirmations *repository.ConfirmationRepository
+	hunts         *repository.HuntRepository
+	service       *confirmation.Service
+}
+
+// NewHandler creates a confirmation Handler.
+func NewHandler(confirmations *repository.ConfirmationRepository, hunts *repository.HuntRepository, service *confirmation.Service) *Handler {
+	return &Handler{confirmations: confirmations, hunts: hunts, service: service}
+}
+
+// Show renders the logged-in member's confirmation. Other members'
+// confirmations yield 404.
+func (h *Handler) Show(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	h.render(w, r, http.StatusOK, page.Props{Confirmation: c})
+}
+
+// Respond records the member's accept or decline.
+func (h *Handler) Respond(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	member, _ := auth.UserFromContext(r.Context())
+	decision := model.ConfirmationDecision(r.FormValue("decision"))
+	updated, err := h.service.Respond(r.Context(), member, c.ID, decision, r.FormValue("reason"), time.Now())
+	switch {
+	case err == nil:
+		h.render(w, r, http.StatusOK, page.Props{Confirmation: updated, Message: "Thanks, we've recorded your answer."})
+	case errors.Is(err, confirmation.ErrInvalidDecision),
+		errors.Is(err, confirmation.ErrAlreadyResponded),
+		errors.Is(err, confirmation.ErrDeadlinePassed):
+		if current, err := h.confirmations.GetByID(r.Context(), c.ID); err == nil {
+			c = current
+		}
+		h.render(w, r, http.StatusUnprocessableEntity, page.Props{
+			Confirmation: c,
+			Reason:       r.FormValue("reason"),
+			Error:        responseMessage(err),
+		})
+	case errors.Is(err, confirmation.ErrHuntNotClosed):
+		h.render(w, r, http.StatusConflict, page.Props{Confirmation: c, Error: responseMessage(err)})
+	default:
+		log.Printf("responding to confirmation: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// Withdraw gives up a place the member already accepted.
+func (h *Handler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	member, _ := auth.UserFromContext(r.Context())
+	updated, err := h.service.Withdraw(r.Context(), member, c.ID, r.FormValue("reason"), time.Now())
+	switch {
+	case err == nil:
+		h.render(w, r, http.StatusOK, page.Props{Confirmation: updated, Message: "You've given up your place. Thanks for letting us know."})
+	case errors.Is(err, confirmation.ErrCannotWithdraw):
+		h.render(w, r, http.StatusUnprocessableEntity, page.Props{
+			Confirmation: c,
+			Reason:       r.FormValue("reason"),
+			Error:        responseMessage(err),
+		})
+	default:
+		log.Printf("withdrawing confirmation: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// load fetches the logged-in member's confirmation named in the path,
+// writing 404 or 500 and returning false if it can't.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request) (model.Confirmation, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return model.Confirmation{}, false
+	}
+	member, _ := auth.UserFromContext(r.Context())
+	c, err := h.confirmations.GetByID(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && c.UserID != member.ID) {
+		http.NotFound(w, r)
+		return model.Confirmation{}, false
+	}
+	if err != nil {
+		log.Printf("getting confirmation: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return model.Confirmation{}, false
+	}
+	return c, true
+}
+
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, status int, props page.Props) {
+	hunt, err := h.hunts.GetByID(r.Context(), props.Confirmation.HuntID)
+	if err != nil {
+		log.Printf("getting hunt: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	props.Hunt = hunt
+	props.Now = time.Now()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Page(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+func responseMessage(err error) string {
+	switch {
+	case errors.Is(err, confirmation.ErrAlreadyResponded):
+		return "You've already responded to this selection."
+	case errors.Is(err, confirmation.ErrDeadlinePassed):
+		return "Sorry, the deadline to respond has passed."
+	case errors.Is(err, confirmation.ErrHuntNotClosed):
+		return "This hunt is no longer taking responses."
+	case errors.Is(err, confirmation.ErrCannotWithdraw):
+		return "This place can no longer be given up."
+	default:
+		return "Please choose accept or decline."
+	}
+}
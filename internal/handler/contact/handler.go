@@ -1,34 +1,55 @@
 package contact
 
 import (
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"net/mail"
 	"strings"
 
+	"github.com/brian-abo/tfo-webapp/internal/contact"
+	"github.com/brian-abo/tfo-webapp/internal/model"
 	"github.com/brian-abo/tfo-webapp/internal/repository"
-	"github.com/brian-abo/tfo-webapp/web/features/contact"
+	page "github.com/brian-abo/tfo-webapp/web/features/contact"
 )
 
 // Handler handles contact page requests.
 type Handler struct {
-	repo *repository.ContactRepository
+	service *contact.Service
+	regions *repository.RegionRepository
 }
 
-// NewHandler creates a contact Handler with the given repository.
-func NewHandler(repo *repository.ContactRepository) *Handler {
-	return &Handler{repo: repo}
+// NewHandler creates a contact Handler with the given service.
+func NewHandler(service *contact.Service, regions *repository.RegionRepository) *Handler {
+	return &Handler{service: service, regions: regions}
 }
 
-// Index renders the contact page.
+// Index renders the contact page with the regions and leaders on record.
 func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
-	if err := contact.Page().Render(r.Context(), w); err != nil {
+	regions, err := h.regions.List(r.Context())
+	if err != nil {
+		log.Printf("listing regions: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	leaders, err := h.regions.ListLeaders(r.Context())
+	if err != nil {
+		log.Printf("listing regional leaders: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	props := page.PageProps{Regions: regions, Leaders: page.Leaders(regions, leaders)}
+	if err := page.Page(props).Render(r.Context(), w); err != nil {
 		log.Printf("render error: %v", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
-// Submit handles contact form submissions.
+// Submit handles contact form submissions. Messages sent to a region on
+// the map are recorded against it and forwarded to its leader, if it has
+// one.
 func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "Bad Request", http.StatusBadRequest)
@@ -38,6 +59,7 @@ func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 	name := strings.TrimSpace(r.FormValue("name"))
 	email := strings.TrimSpace(r.FormValue("email"))
 	message := strings.TrimSpace(r.FormValue("message"))
+	regionID := strings.TrimSpace(r.FormValue("region_id"))
 
 	if name == "" || email == "" || message == "" {
 		http.Error(w, "All fields are required", http.StatusBadRequest)
@@ -49,7 +71,33 @@ func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := h.repo.Insert(r.Context(), name, email, message); err != nil {
+	sub := model.ContactSubmission{Name: name, Email: email, Message: message}
+	var leader *contact.Leader
+	if regionID != "" {
+		region, err := h.regions.GetByID(r.Context(), regionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "Unknown region", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("getting region: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		sub.RegionID = sql.NullString{String: region.ID, Valid: true}
+
+		l, err := h.regions.GetLeader(r.Context(), region.ID)
+		switch {
+		case err == nil:
+			leader = &contact.Leader{RegionID: l.RegionID, Name: l.Name, Email: l.Email}
+		case !errors.Is(err, repository.ErrNotFound):
+			log.Printf("getting regional leader: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if _, err := h.service.Submit(r.Context(), sub, leader); err != nil {
 		log.Printf("storing contact submission: %v", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
@@ -0,0 +1,246 @@
+package contactadmin
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/contact"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/contactadmin"
+)
+
+// Handler handles the staff contact inbox.
+type Handler struct {
+	contacts *repository.ContactRepository
+	users    *repository.UserRepository
+	regions  *repository.RegionRepository
+	service  *contact.Service
+}
+
+// NewHandler creates a contactadmin Handler.
+func NewHandler(
+	contacts *repository.ContactRepository,
+	users *repository.UserRepository,
+	regions *repository.RegionRepository,
+	service *contact.Service,
+) *Handler {
+	return &Handler{contacts: contacts, users: users, regions: regions, service: service}
+}
+
+// Inbox lists submissions matching the query's filters, newest first.
+func (h *Handler) Inbox(w http.ResponseWriter, r *http.Request) {
+	filter := page.FilterFromQuery(r.URL.Query())
+	pageNum, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || pageNum < 1 {
+		pageNum = 1
+	}
+
+	staff, err := h.users.ListStaff(r.Context())
+	if err != nil {
+		log.Printf("listing staff: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	regions, err := h.regionOptions(r.Context())
+	if err != nil {
+		log.Printf("listing regions: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	viewer, _ := auth.UserFromContext(r.Context())
+	submissions, total, err := h.contacts.List(r.Context(),
+		contactFilter(filter, viewer.ID),
+		repository.Page{Limit: page.PageSize, Offset: (pageNum - 1) * page.PageSize},
+	)
+	if err != nil {
+		log.Printf("listing contact submissions: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Inbox(page.InboxProps{
+		Filter:      filter,
+		Submissions: submissions,
+		Total:       total,
+		Page:        pageNum,
+		Staff:       staff,
+		Regions:     regions,
+	}).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+// Show renders a submission with its triage controls and notes.
+func (h *Handler) Show(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	h.render(w, r, http.StatusOK, page.DetailProps{Submission: sub})
+}
+
+// Triage saves a submission's status and assignee.
+func (h *Handler) Triage(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	var assignee uuid.NullUUID
+	if v := r.FormValue("assignee_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			h.render(w, r, http.StatusUnprocessableEntity, page.DetailProps{Submission: sub, Error: triageMessage(contact.ErrAssigneeNotStaff)})
+			return
+		}
+		assignee = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	staff, _ := auth.UserFromContext(r.Context())
+	updated, err := h.service.Triage(r.Context(), staff, sub.ID, model.ContactStatus(r.FormValue("status")), assignee)
+	switch {
+	case err == nil:
+		h.render(w, r, http.StatusOK, page.DetailProps{Submission: updated, Message: "Saved."})
+	case errors.Is(err, contact.ErrInvalidStatus), errors.Is(err, contact.ErrAssigneeNotStaff):
+		h.render(w, r, http.StatusUnprocessableEntity, page.DetailProps{Submission: sub, Error: triageMessage(err)})
+	case errors.Is(err, contact.ErrNotStaff):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, repository.ErrNotFound):
+		http.NotFound(w, r)
+	default:
+		log.Printf("triaging contact submission: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// AddNote records an internal note on a submission.
+func (h *Handler) AddNote(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	staff, _ := auth.UserFromContext(r.Context())
+	_, err := h.service.AddNote(r.Context(), staff, sub.ID, r.FormValue("body"))
+	switch {
+	case err == nil:
+		http.Redirect(w, r, page.DetailURL(sub.ID), http.StatusSeeOther)
+	case errors.Is(err, contact.ErrNoteRequired):
+		h.render(w, r, http.StatusUnprocessableEntity, page.DetailProps{Submission: sub, Error: "Please write a note before saving."})
+	case errors.Is(err, contact.ErrNotStaff):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, repository.ErrNotFound):
+		http.NotFound(w, r)
+	default:
+		log.Printf("adding contact note: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// load fetches the submission named in the path, writing 404 or 500 and
+// returning false if it can't.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request) (model.ContactSubmission, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return model.ContactSubmission{}, false
+	}
+	sub, err := h.contacts.GetByID(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.NotFound(w, r)
+		return model.ContactSubmission{}, false
+	}
+	if err != nil {
+		log.Printf("getting contact submission: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return model.ContactSubmission{}, false
+	}
+	return sub, true
+}
+
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, status int, props page.DetailProps) {
+	notes, err := h.contacts.ListNotes(r.Context(), props.Submission.ID)
+	if err != nil {
+		log.Printf("listing contact notes: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	staff, err := h.users.ListStaff(r.Context())
+	if err != nil {
+		log.Printf("listing staff: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	regions, err := h.regionOptions(r.Context())
+	if err != nil {
+		log.Printf("listing regions: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	props.Staff = staff
+	props.Regions = regions
+	for _, n := range notes {
+		props.Notes = append(props.Notes, page.Note{Author: n.AuthorName, Body: n.Note.Body, CreatedAt: n.Note.CreatedAt})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Detail(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+// contactFilter converts the inbox's filter into a repository filter.
+// Unparseable assignees match nothing rather than everything.
+func contactFilter(f page.Filter, viewer uuid.UUID) repository.ContactFilter {
+	filter := repository.ContactFilter{Status: model.ContactStatus(f.Status), RegionID: f.Region, Search: f.Search}
+	switch f.Assignee {
+	case "":
+	case page.AssignedToMe:
+		filter.Assignee = uuid.NullUUID{UUID: viewer, Valid: true}
+	case page.Unassigned:
+		filter.Unassigned = true
+	default:
+		id, _ := uuid.Parse(f.Assignee)
+		filter.Assignee = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	return filter
+}
+
+// regionOptions returns the regions messages can be sent to.
+func (h *Handler) regionOptions(ctx context.Context) ([]page.RegionOption, error) {
+	regions, err := h.regions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]page.RegionOption, len(regions))
+	for i, r := range regions {
+		options[i] = page.RegionOption{ID: r.ID, Name: r.Name}
+	}
+	return options, nil
+}
+
+func triageMessage(err error) string {
+	switch {
+	case errors.Is(err, contact.ErrAssigneeNotStaff):
+		return "Messages can only be assigned to active staff."
+	default:
+		return "Please choose a valid status."
+	}
+}
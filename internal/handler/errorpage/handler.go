@@ -0,0 +1,18 @@
+package errorpage
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/brian-abo/tfo-webapp/web/features/errorpage"
+)
+
+// Render writes an error page with the given status. It satisfies
+// auth.ErrorRenderer.
+func Render(w http.ResponseWriter, r *http.Request, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := errorpage.Page(errorpage.PropsFor(status, message)).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
@@ -0,0 +1,380 @@
+package huntadmin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/confirmation"
+	"github.com/brian-abo/tfo-webapp/internal/hunt"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/huntadmin"
+)
+
+// Handler handles the staff hunt management console.
+type Handler struct {
+	hunts         *repository.HuntRepository
+	signups       *repository.SignupRepository
+	results       *repository.LotteryResultRepository
+	confirmations *repository.ConfirmationRepository
+	service       *hunt.Service
+	promoter      *confirmation.Service
+	loc           *time.Location
+}
+
+// NewHandler creates a huntadmin Handler. Form times are entered and
+// shown in loc.
+func NewHandler(
+	hunts *repository.HuntRepository,
+	signups *repository.SignupRepository,
+	results *repository.LotteryResultRepository,
+	confirmations *repository.ConfirmationRepository,
+	service *hunt.Service,
+	promoter *confirmation.Service,
+	loc *time.Location,
+) *Handler {
+	return &Handler{
+		hunts:         hunts,
+		signups:       signups,
+		results:       results,
+		confirmations: confirmations,
+		service:       service,
+		promoter:      promoter,
+		loc:           loc,
+	}
+}
+
+// Index lists hunts with the requested status, all by default.
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	status := model.HuntStatus(r.URL.Query().Get("status"))
+	if !isTab(status) {
+		status = ""
+	}
+	pageNum, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || pageNum < 1 {
+		pageNum = 1
+	}
+	h.renderIndex(w, r, http.StatusOK, page.IndexProps{Status: status, Page: pageNum})
+}
+
+// New renders an empty hunt form, or one cloned from the hunt named by
+// the from query parameter.
+func (h *Handler) New(w http.ResponseWriter, r *http.Request) {
+	props := page.FormProps{Form: page.NewForm()}
+	if from := r.URL.Query().Get("from"); from != "" {
+		src, ok := h.load(w, r, from)
+		if !ok {
+			return
+		}
+		props.Form = page.CloneForm(src, h.loc)
+	}
+	h.renderForm(w, r, http.StatusOK, props)
+}
+
+// Create saves a new hunt as a draft, publishing it or scheduling it to
+// open if requested.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	form := formFromRequest(r)
+	submitted, errs := form.Validate(h.loc)
+	if len(errs) > 0 {
+		h.renderForm(w, r, http.StatusUnprocessableEntity, page.FormProps{Form: form, Errors: errs})
+		return
+	}
+
+	publish := r.FormValue("publish")
+	submitted.AutoOpen = publish == page.PublishOnSchedule
+	created, err := h.hunts.Create(r.Context(), submitted)
+	if err != nil {
+		log.Printf("creating hunt: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if publish == page.PublishNow && !h.publish(w, r, created.ID) {
+		return
+	}
+	http.Redirect(w, r, page.TabURL(""), http.StatusSeeOther)
+}
+
+// Edit renders the form for an existing hunt.
+func (h *Handler) Edit(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	h.renderForm(w, r, http.StatusOK, page.FormProps{
+		HuntID:   existing.ID,
+		Status:   existing.Status,
+		AutoOpen: existing.AutoOpen,
+		Form:     page.FormFromHunt(existing, h.loc),
+	})
+}
+
+// Update saves changes to a draft or open hunt, publishing a draft or
+// scheduling it to open if requested.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if !page.IsEditable(existing) {
+		http.Error(w, "This hunt can no longer be edited", http.StatusConflict)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := formFromRequest(r)
+	submitted, errs := form.Validate(h.loc)
+	if len(errs) > 0 {
+		h.renderForm(w, r, http.StatusUnprocessableEntity, page.FormProps{
+			HuntID:   existing.ID,
+			Status:   existing.Status,
+			AutoOpen: existing.AutoOpen,
+			Form:     form,
+			Errors:   errs,
+		})
+		return
+	}
+
+	publish := r.FormValue("publish")
+	submitted.ID = existing.ID
+	submitted.AutoOpen = publish == page.PublishOnSchedule
+	updated, err := h.hunts.Update(r.Context(), submitted)
+	switch {
+	case errors.Is(err, repository.ErrHuntNotEditable):
+		http.Error(w, "This hunt was closed while you were editing, so your changes weren't saved", http.StatusConflict)
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		http.NotFound(w, r)
+		return
+	case err != nil:
+		log.Printf("updating hunt: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if publish == page.PublishNow && updated.Status == model.HuntStatusDraft && !h.publish(w, r, existing.ID) {
+		return
+	}
+	http.Redirect(w, r, page.TabURL(""), http.StatusSeeOther)
+}
+
+// ChangeStatus closes, completes or cancels a hunt.
+func (h *Handler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	staff, _ := auth.UserFromContext(r.Context())
+	to := model.HuntStatus(r.FormValue("status"))
+	_, err := h.service.Transition(r.Context(), existing.ID, to, uuid.NullUUID{UUID: staff.ID, Valid: true}, r.FormValue("reason"))
+	switch {
+	case err == nil:
+		http.Redirect(w, r, page.TabURL(to), http.StatusSeeOther)
+	case errors.Is(err, hunt.ErrInvalidTransition), errors.Is(err, hunt.ErrReasonRequired):
+		h.renderIndex(w, r, http.StatusUnprocessableEntity, page.IndexProps{
+			Status:   existing.Status,
+			Page:     1,
+			Error:    statusMessage(err),
+			ErrorFor: existing.ID,
+		})
+	default:
+		log.Printf("changing hunt status: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// Entries shows who entered a hunt and the eligibility recorded for each
+// entry.
+func (h *Handler) Entries(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	rows, err := h.signups.ListEntriesByHunt(r.Context(), existing.ID)
+	if err != nil {
+		log.Printf("listing hunt entries: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	props := page.EntriesProps{Hunt: existing}
+	for _, row := range rows {
+		props.Entries = append(props.Entries, page.NewEntry(row.Signup, row.Name, row.Email))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := page.Entries(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+// Roster shows a drawn hunt's primaries, promoted alternates and waiting
+// alternates.
+func (h *Handler) Roster(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	results, err := h.results.ListByHunt(r.Context(), existing.ID)
+	if err != nil {
+		log.Printf("listing lottery results: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	rows, err := h.signups.ListEntriesByHunt(r.Context(), existing.ID)
+	if err != nil {
+		log.Printf("listing hunt entries: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	confirmations, err := h.confirmations.ListByHunt(r.Context(), existing.ID)
+	if err != nil {
+		log.Printf("listing confirmations: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	entries := make([]page.Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, page.NewEntry(row.Signup, row.Name, row.Email))
+	}
+
+	props := page.RosterProps{
+		Hunt: existing,
+		Rows: page.NewRoster(existing, results, entries, confirmations),
+		Now:  time.Now(),
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := page.Roster(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+// Promote expires overdue confirmations and offers any free places to the
+// next alternates, then returns to the roster.
+func (h *Handler) Promote(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if _, err := h.promoter.Promote(r.Context(), existing.ID, time.Now()); err != nil {
+		log.Printf("promoting alternates: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, page.RosterURL(existing.ID), http.StatusSeeOther)
+}
+
+// publish opens a saved draft, writing an error response and returning
+// false if that fails.
+func (h *Handler) publish(w http.ResponseWriter, r *http.Request, id uuid.UUID) bool {
+	staff, _ := auth.UserFromContext(r.Context())
+	if _, err := h.service.Open(r.Context(), id, uuid.NullUUID{UUID: staff.ID, Valid: true}); err != nil {
+		log.Printf("publishing hunt: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// load fetches the hunt with the given ID, writing 404 or 500 and
+// returning false if it can't.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request, rawID string) (model.Hunt, bool) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		http.NotFound(w, r)
+		return model.Hunt{}, false
+	}
+	existing, err := h.hunts.GetByID(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.NotFound(w, r)
+		return model.Hunt{}, false
+	}
+	if err != nil {
+		log.Printf("getting hunt: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return model.Hunt{}, false
+	}
+	return existing, true
+}
+
+func (h *Handler) renderIndex(w http.ResponseWriter, r *http.Request, status int, props page.IndexProps) {
+	hunts, total, err := h.hunts.List(r.Context(),
+		repository.HuntFilter{Status: props.Status},
+		repository.Page{Limit: page.PageSize, Offset: (props.Page - 1) * page.PageSize},
+	)
+	if err != nil {
+		log.Printf("listing hunts: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	props.Hunts = hunts
+	props.Total = total
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Index(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+func (h *Handler) renderForm(w http.ResponseWriter, r *http.Request, status int, props page.FormProps) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.FormPage(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+func formFromRequest(r *http.Request) page.Form {
+	return page.Form{
+		Title:             r.FormValue("title"),
+		Description:       r.FormValue("description"),
+		Location:          r.FormValue("location"),
+		State:             r.FormValue("state"),
+		ImageURLs:         r.FormValue("image_urls"),
+		Qualifiers:        r.FormValue("qualifiers"),
+		HuntDate:          r.FormValue("hunt_date"),
+		SignupWindowStart: r.FormValue("signup_window_start"),
+		SignupWindowEnd:   r.FormValue("signup_window_end"),
+		PrimaryCapacity:   r.FormValue("primary_capacity"),
+		AlternateCapacity: r.FormValue("alternate_capacity"),
+		ConfirmationHours: r.FormValue("confirmation_window_hours"),
+		LotteryAlgorithm:  r.FormValue("lottery_algorithm"),
+		MissedDrawTickets: r.FormValue("missed_draw_tickets"),
+		FirstTimerBonus:   r.FormValue("first_timer_bonus"),
+	}
+}
+
+func isTab(status model.HuntStatus) bool {
+	for _, t := range page.StatusTabs() {
+		if t.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func statusMessage(err error) string {
+	if errors.Is(err, hunt.ErrReasonRequired) {
+		return "Please give a reason so entrants know why."
+	}
+	return "That change isn't allowed for this hunt's current status."
+}
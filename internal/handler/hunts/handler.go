@@ -0,0 +1,345 @@
+package hunts
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	"github.com/brian-abo/tfo-webapp/internal/signup"
+	confirmationPage "github.com/brian-abo/tfo-webapp/web/features/confirmation"
+	"github.com/brian-abo/tfo-webapp/web/features/contact"
+	page "github.com/brian-abo/tfo-webapp/web/features/hunts"
+)
+
+// Handler handles the public hunt listing and detail pages and members
+// entering hunt lotteries.
+type Handler struct {
+	hunts         *repository.HuntRepository
+	signups       *repository.SignupRepository
+	confirmations *repository.ConfirmationRepository
+	reports       *repository.AARRepository
+	regions       *repository.RegionRepository
+	service       *signup.Service
+	verifier      *lottery.Verifier
+}
+
+// NewHandler creates a hunts Handler.
+func NewHandler(
+	hunts *repository.HuntRepository,
+	signups *repository.SignupRepository,
+	confirmations *repository.ConfirmationRepository,
+	reports *repository.AARRepository,
+	regions *repository.RegionRepository,
+	service *signup.Service,
+	verifier *lottery.Verifier,
+) *Handler {
+	return &Handler{
+		hunts:         hunts,
+		signups:       signups,
+		confirmations: confirmations,
+		reports:       reports,
+		regions:       regions,
+		service:       service,
+		verifier:      verifier,
+	}
+}
+
+// Index lists upcoming open hunts matching the query's filters. htmx
+// requests from the filter form receive only the results fragment.
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	regions, err := h.regionOptions(r.Context())
+	if err != nil {
+		log.Printf("listing regions: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	filter := page.FilterFromQuery(r.URL.Query())
+	pageNum, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || pageNum < 1 {
+		pageNum = 1
+	}
+
+	from := filter.FromDate()
+	if from.Before(now) {
+		from = now
+	}
+	hunts, total, err := h.hunts.List(r.Context(),
+		repository.HuntFilter{
+			Status: model.HuntStatusOpen,
+			States: filter.States(regions),
+			From:   from,
+			To:     filter.ToDate(),
+		},
+		repository.Page{Limit: page.PageSize, Offset: (pageNum - 1) * page.PageSize},
+	)
+	if err != nil {
+		log.Printf("listing hunts: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	props := page.ListProps{
+		Hunts:   hunts,
+		Total:   total,
+		Page:    pageNum,
+		Filter:  filter,
+		Regions: regions,
+		States:  contact.StateCodes(),
+		Now:     now,
+	}
+	component := page.List(props)
+	if isHTMX(r) && r.Header.Get("HX-Target") == "hunt-results" {
+		component = page.Results(props)
+	}
+	if err := component.Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+// Show renders a single hunt. Drafts are not public and yield 404.
+func (h *Handler) Show(w http.ResponseWriter, r *http.Request) {
+	hunt, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	h.renderDetail(w, r, http.StatusOK, hunt, page.PanelProps{})
+}
+
+// Verify replays a hunt's lottery draw and shows whether the recorded
+// positions match.
+func (h *Handler) Verify(w http.ResponseWriter, r *http.Request) {
+	hunt, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	props := page.VerifyProps{Hunt: hunt}
+	v, err := h.verifier.Verify(r.Context(), hunt.ID)
+	switch {
+	case err == nil:
+		props.Verification = &v
+	case !errors.Is(err, lottery.ErrNotDrawn):
+		log.Printf("verifying lottery: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := page.Verify(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+// Report renders a completed hunt's after-action report. Hunts without a
+// report yield 404.
+func (h *Handler) Report(w http.ResponseWriter, r *http.Request) {
+	hunt, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if hunt.Status != model.HuntStatusCompleted {
+		http.NotFound(w, r)
+		return
+	}
+	report, err := h.reports.GetByHunt(r.Context(), hunt.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("getting after-action report: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	participants, err := h.reports.ListParticipants(r.Context(), report.ID)
+	if err != nil {
+		log.Printf("listing after-action report participants: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.render(w, r, http.StatusOK, page.Report(page.ReportProps{Hunt: hunt, Report: report, Participants: participants}))
+}
+
+// Enter signs the logged-in member up for a hunt's lottery.
+func (h *Handler) Enter(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, "You're entered. Good luck!", func(ctx context.Context, u model.User, id uuid.UUID, attested []string, now time.Time) error {
+		_, err := h.service.Enter(ctx, u, id, attested, now)
+		return err
+	})
+}
+
+// Reenter undoes the logged-in member's withdrawal from a hunt's lottery.
+func (h *Handler) Reenter(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, "You're back in the lottery.", h.service.Reenter)
+}
+
+// Withdraw takes the logged-in member out of a hunt's lottery.
+func (h *Handler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, "You've withdrawn from this lottery.", func(ctx context.Context, u model.User, id uuid.UUID, _ []string, now time.Time) error {
+		return h.service.Withdraw(ctx, u, id, now)
+	})
+}
+
+// act runs a signup action for the logged-in member and shows the result
+// in the signup panel. fn receives the qualifiers the member attested to.
+// Non-htmx requests are redirected back to the hunt on success.
+func (h *Handler) act(w http.ResponseWriter, r *http.Request, success string, fn func(context.Context, model.User, uuid.UUID, []string, time.Time) error) {
+	hunt, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	member, _ := auth.UserFromContext(r.Context())
+
+	err := fn(r.Context(), member, hunt.ID, r.PostForm["qualifier"], time.Now())
+	switch {
+	case err == nil:
+		if !isHTMX(r) {
+			http.Redirect(w, r, page.DetailURL(hunt.ID), http.StatusSeeOther)
+			return
+		}
+		h.renderDetail(w, r, http.StatusOK, hunt, page.PanelProps{Message: success})
+	case errors.Is(err, signup.ErrAlreadyEntered),
+		errors.Is(err, signup.ErrWithdrawn),
+		errors.Is(err, signup.ErrNotEntered),
+		errors.Is(err, signup.ErrQualifiersNotAttested),
+		errors.Is(err, signup.ErrSignupsClosed),
+		errors.Is(err, signup.ErrNotActive):
+		h.renderDetail(w, r, http.StatusUnprocessableEntity, hunt, page.PanelProps{Error: signupMessage(err)})
+	default:
+		log.Printf("signup action: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// load fetches the public hunt named in the path, writing 404 or 500 and
+// returning false if it can't.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request) (model.Hunt, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return model.Hunt{}, false
+	}
+	hunt, err := h.hunts.GetByID(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && hunt.Status == model.HuntStatusDraft) {
+		http.NotFound(w, r)
+		return model.Hunt{}, false
+	}
+	if err != nil {
+		log.Printf("getting hunt: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return model.Hunt{}, false
+	}
+	return hunt, true
+}
+
+// renderDetail renders the hunt page, or just the signup panel for htmx
+// requests. htmx only swaps 2xx responses, so errors are sent as 200 to
+// htmx and status otherwise.
+func (h *Handler) renderDetail(w http.ResponseWriter, r *http.Request, status int, hunt model.Hunt, panel page.PanelProps) {
+	now := time.Now()
+	u, loggedIn := auth.UserFromContext(r.Context())
+	var entry *model.Signup
+	if loggedIn {
+		s, err := h.signups.GetByUserAndHunt(r.Context(), u.ID, hunt.ID)
+		switch {
+		case err == nil:
+			entry = &s
+		case !errors.Is(err, repository.ErrNotFound):
+			log.Printf("getting signup: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	if loggedIn && page.IsDrawn(hunt) {
+		c, err := h.confirmations.GetByHuntAndUser(r.Context(), hunt.ID, u.ID)
+		switch {
+		case err == nil:
+			panel.Confirmation = &c
+			panel.ConfirmationURL = confirmationPage.URL(c.ID)
+		case !errors.Is(err, repository.ErrNotFound):
+			log.Printf("getting confirmation: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	panel.HuntID = hunt.ID
+	panel.State = page.EntryStateOf(loggedIn, entry)
+	panel.Open = hunt.CanAcceptSignups(now)
+	panel.Qualifiers = hunt.QualifierList()
+
+	if isHTMX(r) {
+		h.render(w, r, http.StatusOK, page.Panel(panel))
+		return
+	}
+	props := page.DetailProps{Hunt: hunt, Now: now, Panel: panel}
+	if hunt.Status == model.HuntStatusCompleted {
+		_, err := h.reports.GetByHunt(r.Context(), hunt.ID)
+		switch {
+		case err == nil:
+			props.HasReport = true
+		case !errors.Is(err, repository.ErrNotFound):
+			log.Printf("getting after-action report: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	h.render(w, r, status, page.Detail(props))
+}
+
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, status int, component templ.Component) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := component.Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+func signupMessage(err error) string {
+	switch {
+	case errors.Is(err, signup.ErrAlreadyEntered):
+		return "You're already entered in this lottery."
+	case errors.Is(err, signup.ErrWithdrawn):
+		return "You withdrew from this lottery. Use re-enter to join again."
+	case errors.Is(err, signup.ErrNotEntered):
+		return "You aren't entered in this lottery."
+	case errors.Is(err, signup.ErrNotActive):
+		return "Your membership must be active to enter."
+	case errors.Is(err, signup.ErrQualifiersNotAttested):
+		return "Please confirm you meet each qualifier."
+	default:
+		return "Signups for this hunt are closed."
+	}
+}
+
+// regionOptions returns the region filter, each region with the states
+// it covers.
+func (h *Handler) regionOptions(ctx context.Context) ([]page.RegionOption, error) {
+	regions, err := h.regions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]page.RegionOption, len(regions))
+	for i, r := range regions {
+		opts[i] = page.RegionOption{ID: r.ID, Name: r.Name, States: r.States}
+	}
+	return opts, nil
+}
@@ -0,0 +1,98 @@
+package jobadmin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/jobadmin"
+)
+
+// Handler handles the staff view of the background job queue.
+type Handler struct {
+	jobs *repository.JobRepository
+}
+
+// NewHandler creates a jobadmin Handler.
+func NewHandler(jobs *repository.JobRepository) *Handler {
+	return &Handler{jobs: jobs}
+}
+
+// Index lists jobs with the requested status, failed jobs by default.
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	pageNum, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || pageNum < 1 {
+		pageNum = 1
+	}
+	h.renderIndex(w, r, http.StatusOK, page.IndexProps{
+		Status: page.StatusFromQuery(r.URL.Query().Get("status")),
+		Page:   pageNum,
+	})
+}
+
+// Retry puts a failed job back in the queue to run now.
+func (h *Handler) Retry(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	_, err = h.jobs.Retry(r.Context(), id, time.Now())
+	if err == nil {
+		http.Redirect(w, r, page.IndexURL, http.StatusSeeOther)
+		return
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("retrying job: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// The job is missing, or isn't dead: most likely someone else
+	// retried it first.
+	if _, err := h.jobs.GetByID(r.Context(), id); errors.Is(err, repository.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		log.Printf("getting job: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.renderIndex(w, r, http.StatusConflict, page.IndexProps{
+		Status: page.StatusFromQuery(""),
+		Page:   1,
+		Error:  "That job is no longer failed; it may already have been retried.",
+	})
+}
+
+func (h *Handler) renderIndex(w http.ResponseWriter, r *http.Request, status int, props page.IndexProps) {
+	jobs, total, err := h.jobs.List(r.Context(), props.Status,
+		repository.Page{Limit: page.PageSize, Offset: (props.Page - 1) * page.PageSize},
+	)
+	if err != nil {
+		log.Printf("listing jobs: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	counts, err := h.jobs.CountByStatus(r.Context())
+	if err != nil {
+		log.Printf("counting jobs: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	props.Jobs = jobs
+	props.Total = total
+	props.Counts = counts
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Index(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package membership
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/membership"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/membership"
+)
+
+// Handler handles the staff membership review queue.
+type Handler struct {
+	users   *repository.UserRepository
+	service *membership.Service
+}
+
+// NewHandler creates a membership Handler.
+func NewHandler(users *repository.UserRepository, service *membership.Service) *Handler {
+	return &Handler{users: users, service: service}
+}
+
+// Queue lists members with the requested status, pending by default.
+func (h *Handler) Queue(w http.ResponseWriter, r *http.Request) {
+	status := model.MembershipStatus(r.URL.Query().Get("status"))
+	if _, ok := tabFor(status); !ok {
+		status = model.MembershipPending
+	}
+	pageNum, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || pageNum < 1 {
+		pageNum = 1
+	}
+
+	h.render(w, r, http.StatusOK, page.QueueProps{Status: status, Page: pageNum})
+}
+
+// Decide applies a staff decision to a member and returns to the queue.
+func (h *Handler) Decide(w http.ResponseWriter, r *http.Request) {
+	memberID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	staff, _ := auth.UserFromContext(r.Context())
+	to := model.MembershipStatus(r.FormValue("status"))
+
+	member, err := h.users.GetByID(r.Context(), memberID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("loading member: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = h.service.Decide(r.Context(), memberID, to, staff, r.FormValue("reason"))
+	switch {
+	case err == nil:
+		http.Redirect(w, r, page.TabURL(member.MembershipStatus), http.StatusSeeOther)
+	case errors.Is(err, membership.ErrReasonRequired),
+		errors.Is(err, membership.ErrInvalidTransition),
+		errors.Is(err, membership.ErrSelfDecision):
+		h.render(w, r, http.StatusUnprocessableEntity, page.QueueProps{
+			Status:   member.MembershipStatus,
+			Page:     1,
+			Error:    decisionMessage(err),
+			ErrorFor: memberID,
+		})
+	case errors.Is(err, membership.ErrNotStaff), errors.Is(err, membership.ErrOutranked):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	default:
+		log.Printf("membership decision: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, status int, props page.QueueProps) {
+	members, total, err := h.users.List(r.Context(),
+		repository.UserFilter{MembershipStatus: props.Status},
+		repository.Page{Limit: page.PageSize, Offset: (props.Page - 1) * page.PageSize},
+	)
+	if err != nil {
+		log.Printf("listing members: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	props.Members = members
+	props.Total = total
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Queue(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+func tabFor(status model.MembershipStatus) (page.Tab, bool) {
+	for _, t := range page.QueueTabs() {
+		if t.Status == status {
+			return t, true
+		}
+	}
+	return page.Tab{}, false
+}
+
+func decisionMessage(err error) string {
+	switch {
+	case errors.Is(err, membership.ErrReasonRequired):
+		return "Please give a reason for this decision."
+	case errors.Is(err, membership.ErrSelfDecision):
+		return "You can't change your own membership."
+	default:
+		return "That change isn't allowed for this member's current status."
+	}
+}
@@ -0,0 +1,147 @@
+package profile
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/profile"
+)
+
+// Handler handles member onboarding and profile editing.
+type Handler struct {
+	users   *repository.UserRepository
+	signups *repository.SignupRepository
+}
+
+// NewHandler creates a profile Handler.
+func NewHandler(users *repository.UserRepository, signups *repository.SignupRepository) *Handler {
+	return &Handler{users: users, signups: signups}
+}
+
+// Onboarding renders the first-login form. Users who already have a
+// complete profile are sent home.
+func (h *Handler) Onboarding(w http.ResponseWriter, r *http.Request) {
+	u, _ := auth.UserFromContext(r.Context())
+	if u.IsAccountComplete() {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	h.render(w, r, http.StatusOK, page.Props{Mode: page.ModeOnboarding, Email: u.Email, Form: page.FormFromUser(u)})
+}
+
+// CompleteOnboarding saves the first-login form and continues to the site.
+func (h *Handler) CompleteOnboarding(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.save(w, r, page.ModeOnboarding); !ok {
+		return
+	}
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// Edit renders the profile form and hunt history for the logged-in user.
+func (h *Handler) Edit(w http.ResponseWriter, r *http.Request) {
+	u, _ := auth.UserFromContext(r.Context())
+	history, err := h.signups.ListHistory(r.Context(), u.ID)
+	if err != nil {
+		log.Printf("listing hunt history: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.render(w, r, http.StatusOK, page.Props{
+		Mode:    page.ModeEdit,
+		Email:   u.Email,
+		Form:    page.FormFromUser(u),
+		History: historyEntries(history),
+	})
+}
+
+func historyEntries(history []repository.HuntHistory) []page.HistoryEntry {
+	entries := make([]page.HistoryEntry, len(history))
+	for i, hh := range history {
+		entries[i] = page.HistoryEntry{
+			Hunt:      hh.Hunt,
+			Entered:   hh.Entered,
+			Withdrawn: hh.Withdrawn,
+			Position:  hh.Position,
+			Decision:  hh.Decision,
+			HasReport: hh.ReportID.Valid,
+		}
+	}
+	return entries
+}
+
+// Update saves profile changes and shows the form again with a
+// confirmation.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	u, ok := h.save(w, r, page.ModeEdit)
+	if !ok {
+		return
+	}
+	if r.Header.Get("HX-Request") != "true" {
+		http.Redirect(w, r, page.ModeEdit.Action(), http.StatusSeeOther)
+		return
+	}
+	h.render(w, r, http.StatusOK, page.Props{Mode: page.ModeEdit, Email: u.Email, Form: page.FormFromUser(u), Saved: true})
+}
+
+// save validates and stores the submitted form. On failure it writes the
+// response itself and returns false.
+func (h *Handler) save(w http.ResponseWriter, r *http.Request, mode page.Mode) (model.User, bool) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return model.User{}, false
+	}
+	u, _ := auth.UserFromContext(r.Context())
+
+	form := page.Form{
+		Name:              r.FormValue("name"),
+		Phone:             r.FormValue("phone"),
+		BranchOfService:   r.FormValue("branch_of_service"),
+		ContactPreference: model.ContactPreference(r.FormValue("contact_preference")),
+	}
+	if errs := form.Validate(); len(errs) > 0 {
+		h.render(w, r, http.StatusUnprocessableEntity, page.Props{Mode: mode, Email: u.Email, Form: form, Errors: errs})
+		return model.User{}, false
+	}
+
+	updated, err := h.users.UpdateProfile(r.Context(), u.ID, repository.ProfileUpdate{
+		Name:              form.Name,
+		Phone:             sql.NullString{String: form.Phone, Valid: form.Phone != ""},
+		BranchOfService:   form.BranchOfService,
+		ContactPreference: form.ContactPreference,
+	})
+	if err != nil {
+		log.Printf("updating profile: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return model.User{}, false
+	}
+	return updated, true
+}
+
+// render writes the full page, or just the form for htmx requests so it
+// can be swapped in place. htmx only swaps 2xx responses, so validation
+// failures are sent as 200 to htmx and 422 otherwise.
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, status int, props page.Props) {
+	component := page.Edit(props)
+	if props.Mode == page.ModeOnboarding {
+		component = page.Onboarding(props)
+	}
+	if r.Header.Get("HX-Request") == "true" {
+		component = page.ProfileForm(props)
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := component.Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
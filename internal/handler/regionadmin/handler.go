@@ -0,0 +1,172 @@
+package regionadmin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/region"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+	page "github.com/brian-abo/tfo-webapp/web/features/regionadmin"
+)
+
+// Handler handles staff editing of regions and their leaders.
+type Handler struct {
+	regions *repository.RegionRepository
+	service *region.Service
+}
+
+// NewHandler creates a regionadmin Handler.
+func NewHandler(regions *repository.RegionRepository, service *region.Service) *Handler {
+	return &Handler{regions: regions, service: service}
+}
+
+// Index lists every region with its states and leader.
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	regions, err := h.regions.List(r.Context())
+	if err != nil {
+		log.Printf("listing regions: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	leaders, err := h.regions.ListLeaders(r.Context())
+	if err != nil {
+		log.Printf("listing regional leaders: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Index(page.IndexProps{Regions: regions, Leaders: leaders}).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+// New renders an empty region form.
+func (h *Handler) New(w http.ResponseWriter, r *http.Request) {
+	regions, err := h.regions.List(r.Context())
+	if err != nil {
+		log.Printf("listing regions: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.renderForm(w, r, http.StatusOK, page.FormProps{Form: page.NewForm(regions)})
+}
+
+// Create adds a region, moving its states out of any other region.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	form := formFromRequest(r)
+	submitted, leader, errs := form.Validate()
+	if len(errs) > 0 {
+		h.renderForm(w, r, http.StatusUnprocessableEntity, page.FormProps{Form: form, Errors: errs})
+		return
+	}
+
+	_, err := h.service.Create(r.Context(), submitted, leader)
+	switch {
+	case err == nil:
+		http.Redirect(w, r, page.IndexURL, http.StatusSeeOther)
+	case errors.Is(err, region.ErrRegionExists):
+		h.renderForm(w, r, http.StatusConflict, page.FormProps{
+			Form:   form,
+			Errors: page.Errors{"id": "A region with this ID already exists"},
+		})
+	default:
+		log.Printf("creating region: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// Edit renders the form for an existing region.
+func (h *Handler) Edit(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	leader, err := h.regions.GetLeader(r.Context(), existing.ID)
+	var current *model.RegionalLeader
+	switch {
+	case err == nil:
+		current = &leader
+	case !errors.Is(err, repository.ErrNotFound):
+		log.Printf("getting regional leader: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.renderForm(w, r, http.StatusOK, page.FormProps{
+		RegionID: existing.ID,
+		Form:     page.FormFromRegion(existing, current),
+	})
+}
+
+// Update saves changes to a region, its states and its leader.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := formFromRequest(r)
+	form.ID = existing.ID
+	submitted, leader, errs := form.Validate()
+	if len(errs) > 0 {
+		h.renderForm(w, r, http.StatusUnprocessableEntity, page.FormProps{RegionID: existing.ID, Form: form, Errors: errs})
+		return
+	}
+
+	_, err := h.service.Update(r.Context(), submitted, leader)
+	switch {
+	case err == nil:
+		http.Redirect(w, r, page.IndexURL, http.StatusSeeOther)
+	case errors.Is(err, repository.ErrNotFound):
+		http.NotFound(w, r)
+	default:
+		log.Printf("updating region: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// load fetches the region named in the path, writing 404 or 500 and
+// returning false if it can't.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request) (model.Region, bool) {
+	existing, err := h.regions.GetByID(r.Context(), r.PathValue("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		http.NotFound(w, r)
+		return model.Region{}, false
+	}
+	if err != nil {
+		log.Printf("getting region: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return model.Region{}, false
+	}
+	return existing, true
+}
+
+func (h *Handler) renderForm(w http.ResponseWriter, r *http.Request, status int, props page.FormProps) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.FormPage(props).Render(r.Context(), w); err != nil {
+		log.Printf("render error: %v", err)
+	}
+}
+
+func formFromRequest(r *http.Request) page.Form {
+	return page.Form{
+		ID:          r.FormValue("id"),
+		Name:        r.FormValue("name"),
+		Color:       r.FormValue("color"),
+		Position:    r.FormValue("position"),
+		States:      r.FormValue("states"),
+		LeaderName:  r.FormValue("leader_name"),
+		LeaderEmail: r.FormValue("leader_email"),
+	}
+}
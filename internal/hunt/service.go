@@ -0,0 +1,184 @@
+// Package hunt implements the hunt lifecycle: moving hunts between
+// statuses and running the side effects each change requires.
+package hunt
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/jobs"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+var (
+	// ErrInvalidTransition is returned when the hunt's current status
+	// cannot move to the requested status.
+	ErrInvalidTransition = errors.New("invalid hunt transition")
+	// ErrReasonRequired is returned when a hunt is cancelled without a
+	// reason to pass on to its entrants.
+	ErrReasonRequired = errors.New("a reason is required")
+)
+
+// Drawer runs the lottery for a hunt that has just closed. It is called
+// inside the transaction that closes the hunt, so a failed draw leaves
+// the hunt open.
+type Drawer interface {
+	Draw(ctx context.Context, tx *sql.Tx, hunt model.Hunt) error
+}
+
+// Notifier tells entrants about changes to a hunt they signed up for.
+type Notifier interface {
+	HuntCancelled(ctx context.Context, hunt model.Hunt, entrants []model.User, reason string) error
+}
+
+// JobHuntCancelled is the kind of job that tells one entrant their hunt
+// was cancelled.
+const JobHuntCancelled = "hunt.cancelled"
+
+type huntCancelledJob struct {
+	HuntID uuid.UUID `json:"hunt_id"`
+	UserID uuid.UUID `json:"user_id"`
+	Reason string    `json:"reason"`
+}
+
+// Service moves hunts through their lifecycle.
+type Service struct {
+	db       *sql.DB
+	drawer   Drawer
+	notifier Notifier
+}
+
+// NewService creates a hunt Service. A nil drawer closes hunts without
+// drawing a lottery.
+func NewService(db *sql.DB, drawer Drawer, notifier Notifier) *Service {
+	return &Service{db: db, drawer: drawer, notifier: notifier}
+}
+
+// Open opens a draft hunt for signups.
+func (s *Service) Open(ctx context.Context, huntID uuid.UUID, actorID uuid.NullUUID) (model.HuntStatusChange, error) {
+	return s.Transition(ctx, huntID, model.HuntStatusOpen, actorID, "")
+}
+
+// Close stops signups on an open hunt and draws its lottery.
+func (s *Service) Close(ctx context.Context, huntID uuid.UUID, actorID uuid.NullUUID) (model.HuntStatusChange, error) {
+	return s.Transition(ctx, huntID, model.HuntStatusClosed, actorID, "")
+}
+
+// Complete marks a closed hunt as having taken place.
+func (s *Service) Complete(ctx context.Context, huntID uuid.UUID, actorID uuid.NullUUID) (model.HuntStatusChange, error) {
+	return s.Transition(ctx, huntID, model.HuntStatusCompleted, actorID, "")
+}
+
+// Cancel cancels a hunt that hasn't completed and notifies its entrants.
+func (s *Service) Cancel(ctx context.Context, huntID uuid.UUID, actorID uuid.NullUUID, reason string) (model.HuntStatusChange, error) {
+	return s.Transition(ctx, huntID, model.HuntStatusCancelled, actorID, reason)
+}
+
+// Transition moves a hunt to status to and records the change. actorID
+// is the staff member responsible, or null for automatic changes.
+//
+// The hunt row is locked for the duration, which also blocks concurrent
+// signups: once a close commits, signups see a closed hunt and are
+// refused. Closing draws the lottery in the same transaction.
+// Cancelling expires the hunt's unanswered selections, so nobody can
+// accept a place on it, and enqueues a job per entrant in the same
+// transaction, so each entrant's notification is retried on its own
+// rather than undoing the change.
+func (s *Service) Transition(ctx context.Context, huntID uuid.UUID, to model.HuntStatus, actorID uuid.NullUUID, reason string) (model.HuntStatusChange, error) {
+	reason = strings.TrimSpace(reason)
+
+	var change model.HuntStatusChange
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		hunts := repository.NewHuntRepository(tx)
+
+		hunt, err := hunts.GetByIDForUpdate(ctx, huntID)
+		if err != nil {
+			return err
+		}
+		if err := validateTransition(hunt, to, reason); err != nil {
+			return err
+		}
+		if err := hunts.SetStatus(ctx, hunt.ID, to); err != nil {
+			return err
+		}
+		change, err = repository.NewHuntStatusChangeRepository(tx).Insert(ctx, model.HuntStatusChange{
+			HuntID:      hunt.ID,
+			FromStatus:  hunt.Status,
+			ToStatus:    to,
+			Reason:      reason,
+			ChangedByID: actorID,
+		})
+		if err != nil {
+			return err
+		}
+		hunt.Status = to
+
+		switch to {
+		case model.HuntStatusClosed:
+			if s.drawer != nil {
+				if err := s.drawer.Draw(ctx, tx, hunt); err != nil {
+					return fmt.Errorf("drawing lottery: %w", err)
+				}
+			}
+		case model.HuntStatusCancelled:
+			if _, err := repository.NewConfirmationRepository(tx).ExpirePending(ctx, hunt.ID); err != nil {
+				return err
+			}
+			entrants, err := repository.NewUserRepository(tx).ListEntrants(ctx, hunt.ID)
+			if err != nil {
+				return err
+			}
+			for _, u := range entrants {
+				err := jobs.Enqueue(ctx, tx, JobHuntCancelled, huntCancelledJob{HuntID: hunt.ID, UserID: u.ID, Reason: reason})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.HuntStatusChange{}, fmt.Errorf("changing hunt status: %w", err)
+	}
+	return change, nil
+}
+
+// RegisterJobs registers the handlers for the jobs the Service enqueues.
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	w.Register(JobHuntCancelled, jobs.Handle(s.huntCancelled))
+}
+
+func (s *Service) huntCancelled(ctx context.Context, job huntCancelledJob) error {
+	hunt, err := repository.NewHuntRepository(s.db).GetByID(ctx, job.HuntID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	entrant, err := repository.NewUserRepository(s.db).GetByID(ctx, job.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	return s.notifier.HuntCancelled(ctx, hunt, []model.User{entrant}, job.Reason)
+}
+
+// validateTransition checks that hunt may move to status to.
+func validateTransition(hunt model.Hunt, to model.HuntStatus, reason string) error {
+	if !hunt.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, hunt.Status, to)
+	}
+	if to == model.HuntStatusCancelled && reason == "" {
+		return ErrReasonRequired
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package hunt
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestValidateTransition(t *testing.T) {
+	t.Run("allows the next lifecycle step", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateTransition(model.Hunt{Status: model.HuntStatusOpen}, model.HuntStatusClosed, "")
+
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("rejects transitions the state machine forbids", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateTransition(model.Hunt{Status: model.HuntStatusDraft}, model.HuntStatusCompleted, "")
+
+		g.Expect(err).To(MatchError(ErrInvalidTransition))
+	})
+
+	t.Run("requires a reason to cancel", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateTransition(model.Hunt{Status: model.HuntStatusOpen}, model.HuntStatusCancelled, "")
+
+		g.Expect(err).To(MatchError(ErrReasonRequired))
+	})
+
+	t.Run("cannot cancel a completed hunt", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateTransition(model.Hunt{Status: model.HuntStatusCompleted}, model.HuntStatusCancelled, "weather")
+
+		g.Expect(err).To(MatchError(ErrInvalidTransition))
+	})
+}
@@ -0,0 +1,83 @@
+// Package jobs runs background work, such as sending email, from a queue
+// in Postgres. Work is enqueued in the same transaction as the change it
+// follows from, so it happens if and only if the change commits, and
+// runs later on a Worker with retries, backoff and dead-lettering.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// Handler runs one job of a kind, given its payload. A returned error
+// fails the attempt; wrap it with Permanent if retrying can't help.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Handle adapts fn to a Handler that decodes the payload into T. Payloads
+// that don't decode fail permanently.
+func Handle[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(fmt.Errorf("decoding payload: %w", err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+// Enqueue adds a job of kind with payload encoded as JSON. Pass the
+// transaction making the change the job follows from.
+func Enqueue(ctx context.Context, db repository.DBTX, kind string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s job: %w", kind, err)
+	}
+	if _, err := repository.NewJobRepository(db).Enqueue(ctx, model.Job{Kind: kind, Payload: raw}); err != nil {
+		return fmt.Errorf("enqueuing %s job: %w", kind, err)
+	}
+	return nil
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one retrying won't fix, such as a job for a
+// record that no longer exists, so the job is dead-lettered straight
+// away.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent returns true if err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Backoff returns how long to wait before retrying a job that has failed
+// attempt times: 30s, doubling each attempt up to an hour.
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
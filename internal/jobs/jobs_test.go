@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("doubles from thirty seconds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Backoff(1)).To(Equal(30 * time.Second))
+		g.Expect(Backoff(2)).To(Equal(time.Minute))
+		g.Expect(Backoff(4)).To(Equal(4 * time.Minute))
+	})
+
+	t.Run("caps at an hour", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Backoff(8)).To(Equal(time.Hour))
+		g.Expect(Backoff(1000)).To(Equal(time.Hour))
+	})
+}
+
+func TestPermanent(t *testing.T) {
+	t.Run("marks wrapped errors", func(t *testing.T) {
+		g := NewWithT(t)
+		cause := errors.New("gone")
+
+		err := fmt.Errorf("sending: %w", Permanent(cause))
+
+		g.Expect(IsPermanent(err)).To(BeTrue())
+		g.Expect(err).To(MatchError(cause))
+	})
+
+	t.Run("leaves other errors retryable", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(IsPermanent(errors.New("timeout"))).To(BeFalse())
+		g.Expect(Permanent(nil)).To(BeNil())
+	})
+}
+
+func TestHandle(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("decodes the payload", func(t *testing.T) {
+		g := NewWithT(t)
+		var got payload
+		h := Handle(func(_ context.Context, p payload) error {
+			got = p
+			return nil
+		})
+
+		g.Expect(h(t.Context(), json.RawMessage(`{"name": "elk"}`))).To(Succeed())
+		g.Expect(got.Name).To(Equal("elk"))
+	})
+
+	t.Run("fails permanently on a bad payload", func(t *testing.T) {
+		g := NewWithT(t)
+		h := Handle(func(context.Context, payload) error { return nil })
+
+		err := h(t.Context(), json.RawMessage(`[]`))
+
+		g.Expect(IsPermanent(err)).To(BeTrue())
+	})
+}
+
+func TestWorkerRun(t *testing.T) {
+	w := NewWorker(nil)
+	w.Register("ok", func(context.Context, json.RawMessage) error { return nil })
+	w.Register("panics", func(context.Context, json.RawMessage) error { panic("boom") })
+
+	t.Run("runs the registered handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(w.run(t.Context(), model.Job{Kind: "ok", Attempts: 1, MaxAttempts: 3})).To(Succeed())
+	})
+
+	t.Run("fails permanently without a handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := w.run(t.Context(), model.Job{Kind: "unknown", Attempts: 1, MaxAttempts: 3})
+
+		g.Expect(IsPermanent(err)).To(BeTrue())
+	})
+
+	t.Run("fails permanently once attempts are used up", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := w.run(t.Context(), model.Job{Kind: "ok", Attempts: 4, MaxAttempts: 3})
+
+		g.Expect(IsPermanent(err)).To(BeTrue())
+	})
+
+	t.Run("recovers from panics", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := w.run(t.Context(), model.Job{Kind: "panics", Attempts: 1, MaxAttempts: 3})
+
+		g.Expect(err).To(MatchError("panic: boom"))
+		g.Expect(IsPermanent(err)).To(BeFalse())
+	})
+
+	t.Run("rejects duplicate handlers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(func() { w.Register("ok", nil) }).To(Panic())
+	})
+}
@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// Worker claims due jobs and runs their handlers. Any number of workers,
+// in one process or many, can share the queue.
+type Worker struct {
+	db       *sql.DB
+	handlers map[string]Handler
+
+	// Concurrency is how many jobs run at once.
+	Concurrency int
+	// PollInterval is how long to wait after finding the queue empty.
+	PollInterval time.Duration
+	// Timeout bounds each attempt. It must be shorter than Lease, or
+	// another worker may claim a job that is still running.
+	Timeout time.Duration
+	// Lease is how long a claimed job is reserved; a job whose worker
+	// dies is claimed again once it expires.
+	Lease time.Duration
+
+	now func() time.Time
+}
+
+// NewWorker creates a Worker with no handlers and default settings.
+func NewWorker(db *sql.DB) *Worker {
+	return &Worker{
+		db:           db,
+		handlers:     map[string]Handler{},
+		Concurrency:  4,
+		PollInterval: 2 * time.Second,
+		Timeout:      time.Minute,
+		Lease:        5 * time.Minute,
+		now:          time.Now,
+	}
+}
+
+// Register sets the handler for jobs of kind. It must be called before
+// Run.
+func (w *Worker) Register(kind string, h Handler) {
+	if _, ok := w.handlers[kind]; ok {
+		panic("jobs: handler already registered for " + kind)
+	}
+	w.handlers[kind] = h
+}
+
+// Run works the queue until ctx is cancelled, then waits for running
+// jobs to finish.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(w.Concurrency, 1) {
+		wg.Go(func() { w.loop(ctx) })
+	}
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		ran, err := w.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("jobs: %v", err)
+		}
+		if ran && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+// RunOnce claims and runs the next due job, reporting whether there was
+// one. Handler failures are recorded against the job rather than
+// returned; the error is for failures to reach the queue.
+func (w *Worker) RunOnce(ctx context.Context) (bool, error) {
+	jobs := repository.NewJobRepository(w.db)
+	job, err := jobs.Claim(ctx, w.now(), w.Lease)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	runErr := w.run(ctx, job)
+
+	// Record the outcome even if ctx was cancelled while the job ran.
+	ctx = context.WithoutCancel(ctx)
+	switch {
+	case runErr == nil:
+		err = jobs.Complete(ctx, job)
+	case IsPermanent(runErr) || !job.HasAttemptsLeft():
+		log.Printf("jobs: %s job %s failed on attempt %d, giving up: %v", job.Kind, job.ID, job.Attempts, runErr)
+		err = jobs.Bury(ctx, job, runErr.Error())
+	default:
+		retryAt := w.now().Add(Backoff(job.Attempts))
+		log.Printf("jobs: %s job %s failed on attempt %d, retrying at %s: %v",
+			job.Kind, job.ID, job.Attempts, retryAt.Format(time.RFC3339), runErr)
+		err = jobs.Reschedule(ctx, job, retryAt, runErr.Error())
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		// The lease expired and another worker claimed the job; its
+		// outcome is the one that counts.
+		log.Printf("jobs: lost claim on %s job %s", job.Kind, job.ID)
+		return true, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("recording %s job %s: %w", job.Kind, job.ID, err)
+	}
+	return true, nil
+}
+
+// run calls the job's handler, turning panics into errors.
+func (w *Worker) run(ctx context.Context, job model.Job) (err error) {
+	if job.Attempts > job.MaxAttempts {
+		// Workers died mid-attempt until the job ran out of attempts.
+		return Permanent(errors.New("no attempts left"))
+	}
+	h, ok := w.handlers[job.Kind]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for %q jobs", job.Kind))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return h(ctx, job.Payload)
+}
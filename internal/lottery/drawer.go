@@ -0,0 +1,98 @@
+package lottery
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/brian-abo/tfo-webapp/internal/confirmation"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// ErrAlreadyDrawn is returned when a hunt's lottery has already been drawn.
+var ErrAlreadyDrawn = repository.ErrAlreadyDrawn
+
+// Drawer draws hunt lotteries and records the results. It implements
+// hunt.Drawer.
+type Drawer struct{}
+
+// NewDrawer creates a Drawer.
+func NewDrawer() *Drawer {
+	return &Drawer{}
+}
+
+// Draw orders the hunt's active entries with the hunt's algorithm and a
+// fresh random seed, records every entry's position in tx and offers the
+// members in primary positions their place, notifying them once tx
+// commits. Entrants suspended or deleted since signing up aren't drawn.
+// Returns ErrAlreadyDrawn if the hunt already has results; callers hold
+// the hunt's row lock, and the unique position constraint catches any
+// draw that slips past.
+func (d *Drawer) Draw(ctx context.Context, tx *sql.Tx, hunt model.Hunt) error {
+	algorithm, err := Lookup(hunt.LotteryAlgorithm)
+	if err != nil {
+		return fmt.Errorf("drawing lottery: %w", err)
+	}
+	results := repository.NewLotteryResultRepository(tx)
+	n, err := results.CountByHunt(ctx, hunt.ID)
+	if err != nil {
+		return fmt.Errorf("drawing lottery: %w", err)
+	}
+	if n > 0 {
+		return fmt.Errorf("drawing lottery: %w", ErrAlreadyDrawn)
+	}
+
+	entries, err := repository.NewSignupRepository(tx).ListActiveByHunt(ctx, hunt.ID)
+	if err != nil {
+		return fmt.Errorf("drawing lottery: %w", err)
+	}
+	seed, err := newSeed()
+	if err != nil {
+		return fmt.Errorf("drawing lottery: %w", err)
+	}
+
+	ordered, err := algorithm.Order(Input{Entries: entries, Seed: seed, Weights: hunt.LotteryWeights})
+	if err != nil {
+		return fmt.Errorf("drawing lottery: %w", err)
+	}
+
+	drawnAt := time.Now()
+	for i, entry := range ordered {
+		res, err := results.Insert(ctx, model.LotteryResult{
+			HuntID:           hunt.ID,
+			SignupID:         entry.ID,
+			Position:         i + 1,
+			AuditSeed:        seed,
+			AlgorithmVersion: algorithm.Version(),
+			DrawnAt:          drawnAt,
+		})
+		if err != nil {
+			return fmt.Errorf("drawing lottery: %w", err)
+		}
+		if !res.IsPrimary(hunt.PrimaryCapacity) {
+			continue
+		}
+		if _, err := confirmation.Offer(ctx, tx, model.Confirmation{
+			LotteryResultID: res.ID,
+			HuntID:          hunt.ID,
+			UserID:          entry.UserID,
+			Deadline:        hunt.ConfirmationDeadline(drawnAt),
+		}, false); err != nil {
+			return fmt.Errorf("drawing lottery: %w", err)
+		}
+	}
+	return nil
+}
+
+// newSeed returns a random non-negative audit seed.
+func newSeed() (int64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, fmt.Errorf("generating seed: %w", err)
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) >> 1), nil
+}
@@ -0,0 +1,86 @@
+package lottery
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// testDB returns a connection to the test database, skipping the test if
+// DATABASE_URL is not set.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL not set, skipping integration test")
+	}
+
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+
+	if err := db.PingContext(t.Context()); err != nil {
+		t.Fatalf("pinging test database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Logf("closing test database: %v", err)
+		}
+	})
+	return db
+}
+
+func TestDrawer_Draw(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		users := repository.NewUserRepository(tx)
+		signups := repository.NewSignupRepository(tx)
+		date := time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)
+		hunt, err := repository.NewHuntRepository(tx).Create(t.Context(), model.Hunt{
+			Title:             "Draw Hunt",
+			Description:       "A test hunt",
+			Location:          "Somewhere, TX",
+			State:             "TX",
+			HuntDate:          date,
+			SignupWindowStart: date.AddDate(0, -2, 0),
+			SignupWindowEnd:   date.AddDate(0, -1, 0),
+			PrimaryCapacity:   2,
+			AlternateCapacity: 1,
+			Status:            model.HuntStatusClosed,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var entrants []model.User
+		for _, email := range []string{"active@draw.test", "suspended@draw.test"} {
+			u, err := users.Create(t.Context(), model.User{Email: email, Name: email, BranchOfService: "Army"})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = signups.Create(t.Context(), u.ID, hunt.ID, model.EligibilitySnapshot{})
+			g.Expect(err).ToNot(HaveOccurred())
+			entrants = append(entrants, u)
+		}
+		suspended := entrants[1]
+		g.Expect(users.ChangeMembershipStatus(t.Context(), suspended.ID, model.MembershipSuspended)).To(Succeed())
+
+		g.Expect(NewDrawer().Draw(t.Context(), tx, hunt)).To(Succeed())
+
+		drawn, err := signups.ListDrawnByHunt(t.Context(), hunt.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(drawn).To(ConsistOf(HaveField("UserID", entrants[0].ID)))
+
+		offered, err := repository.NewConfirmationRepository(tx).ListByHunt(t.Context(), hunt.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(offered).To(ConsistOf(HaveField("UserID", entrants[0].ID)))
+	})
+}
@@ -0,0 +1,85 @@
+// Package lottery draws hunt lotteries. A draw is a deterministic function
+// of the hunt's entries, an audit seed and an algorithm version, so any
+// recorded draw can be replayed and checked later.
+package lottery
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// ErrUnknownAlgorithm is returned when no algorithm is registered for a
+// version.
+var ErrUnknownAlgorithm = errors.New("unknown lottery algorithm")
+
+// ErrTooManyTickets is returned when a draw's tickets add up to more than
+// an algorithm can count.
+var ErrTooManyTickets = errors.New("too many lottery tickets")
+
+// Input is everything an algorithm may use to order a draw.
+type Input struct {
+	Entries []model.Signup
+	Seed    int64
+	// Weights is the hunt's ticket table; uniform algorithms ignore it.
+	Weights model.LotteryWeights
+}
+
+// Algorithm orders a hunt's entries; position 1 is the first selected.
+// Implementations must be deterministic: the same entries and seed give
+// the same order whatever order the entries are passed in. Once an
+// algorithm has been used for a draw its behaviour must never change;
+// register a new version instead. Order returns an error only when the
+// input can't be drawn at all.
+type Algorithm interface {
+	Version() string
+	Order(in Input) ([]model.Signup, error)
+}
+
+var registry = map[string]Algorithm{}
+
+// Register makes an algorithm available by its version. It panics if the
+// version is already registered.
+func Register(a Algorithm) {
+	if _, dup := registry[a.Version()]; dup {
+		panic("lottery: algorithm registered twice: " + a.Version())
+	}
+	registry[a.Version()] = a
+}
+
+// Lookup returns the algorithm registered for version.
+func Lookup(version string) (Algorithm, error) {
+	a, ok := registry[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, version)
+	}
+	return a, nil
+}
+
+// Versions returns the registered algorithm versions, sorted.
+func Versions() []string {
+	versions := make([]string, 0, len(registry))
+	for v := range registry {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+func init() {
+	Register(Uniform{})
+	Register(Weighted{})
+}
+
+// canonical returns a copy of entries sorted by signup ID, so algorithms
+// don't depend on the order the database returned them in.
+func canonical(entries []model.Signup) []model.Signup {
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b model.Signup) int {
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return sorted
+}
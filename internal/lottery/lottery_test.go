@@ -0,0 +1,120 @@
+package lottery
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// testEntries returns n signups with IDs ...0001, ...0002 and so on.
+func testEntries(n int) []model.Signup {
+	entries := make([]model.Signup, n)
+	for i := range entries {
+		entries[i].ID = uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1))
+	}
+	return entries
+}
+
+// entryNumbers returns the number each testEntries signup was created with.
+func entryNumbers(entries []model.Signup) []int {
+	out := make([]int, len(entries))
+	for i, e := range entries {
+		out[i] = int(e.ID[15])
+	}
+	return out
+}
+
+// order runs a over in, failing the test if it returns an error.
+func order(t testing.TB, a Algorithm, in Input) []model.Signup {
+	t.Helper()
+	ordered, err := a.Order(in)
+	if err != nil {
+		t.Fatalf("ordering entries: %v", err)
+	}
+	return ordered
+}
+
+func TestLookup(t *testing.T) {
+	t.Run("finds registered algorithms", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a, err := Lookup(UniformV1)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(a.Version()).To(Equal(UniformV1))
+		g.Expect(Versions()).To(ContainElement(UniformV1))
+	})
+
+	t.Run("finds the default algorithm", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a, err := Lookup(model.DefaultLotteryAlgorithm)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(a).To(Equal(Uniform{}))
+	})
+
+	t.Run("rejects unknown versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Lookup("nope-v0")
+
+		g.Expect(err).To(MatchError(ErrUnknownAlgorithm))
+	})
+}
+
+func TestUniform_Order(t *testing.T) {
+	t.Run("is a permutation of the entries", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(20)
+
+		got := order(t, Uniform{}, Input{Entries: entries, Seed: 7})
+
+		g.Expect(got).To(ConsistOf(entries))
+	})
+
+	t.Run("is reproducible regardless of input order", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(20)
+		reversed := slices.Clone(entries)
+		slices.Reverse(reversed)
+
+		first := order(t, Uniform{}, Input{Entries: entries, Seed: 7})
+		second := order(t, Uniform{}, Input{Entries: reversed, Seed: 7})
+
+		g.Expect(second).To(Equal(first))
+	})
+
+	t.Run("depends on the seed", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(20)
+
+		g.Expect(order(t, Uniform{}, Input{Entries: entries, Seed: 1})).
+			ToNot(Equal(order(t, Uniform{}, Input{Entries: entries, Seed: 2})))
+	})
+
+	t.Run("does not modify the input", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(5)
+		before := slices.Clone(entries)
+
+		order(t, Uniform{}, Input{Entries: entries, Seed: 3})
+
+		g.Expect(entries).To(Equal(before))
+	})
+
+	// Recorded draws are replayed with the same version, so its output
+	// for a given seed must never change.
+	t.Run("matches the recorded ordering", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := order(t, Uniform{}, Input{Entries: testEntries(8), Seed: 20261018})
+
+		g.Expect(entryNumbers(got)).To(Equal([]int{6, 1, 7, 5, 3, 4, 2, 8}))
+	})
+}
@@ -0,0 +1,30 @@
+package lottery
+
+import "math/rand/v2"
+
+// pcgStream is the fixed PCG stream every draw uses; the audit seed picks
+// the state.
+const pcgStream = 0x7466_6f2d_6c6f_7474 // "tfo-lott"
+
+// rng wraps PCG, whose output is fixed by its specification. Bounded
+// values are derived here rather than with math/rand helpers, whose
+// algorithms may change between Go releases: a replay must reproduce a
+// draw exactly, years later.
+type rng struct {
+	src *rand.PCG
+}
+
+func newRNG(seed int64) rng {
+	return rng{src: rand.NewPCG(uint64(seed), pcgStream)}
+}
+
+// below returns a uniform value in [0, n) by rejection sampling. n must
+// be positive.
+func (r rng) below(n uint64) uint64 {
+	threshold := -n % n // 2^64 mod n
+	for {
+		if v := r.src.Uint64(); v >= threshold {
+			return v % n
+		}
+	}
+}
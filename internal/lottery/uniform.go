@@ -0,0 +1,26 @@
+package lottery
+
+import "github.com/brian-abo/tfo-webapp/internal/model"
+
+// UniformV1 is the version of the Uniform algorithm.
+const UniformV1 = "uniform-v1"
+
+// Uniform gives every entry an equal chance at each position: a
+// Fisher-Yates shuffle of the entries in signup ID order.
+type Uniform struct{}
+
+// Version implements Algorithm.
+func (Uniform) Version() string {
+	return UniformV1
+}
+
+// Order implements Algorithm.
+func (Uniform) Order(in Input) ([]model.Signup, error) {
+	ordered := canonical(in.Entries)
+	r := newRNG(in.Seed)
+	for i := len(ordered) - 1; i > 0; i-- {
+		j := int(r.below(uint64(i + 1)))
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	}
+	return ordered, nil
+}
@@ -0,0 +1,153 @@
+package lottery
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+var (
+	// ErrNotDrawn is returned when verifying a hunt with no lottery results.
+	ErrNotDrawn = errors.New("lottery not drawn")
+	// ErrInconsistentDraw is returned when a hunt's results don't share one
+	// seed and algorithm version, so they can't come from a single draw.
+	ErrInconsistentDraw = errors.New("results are not from a single draw")
+)
+
+// Check compares one recorded position with the replay.
+type Check struct {
+	Position int
+	Recorded uuid.UUID
+	Replayed uuid.UUID
+}
+
+// Match returns true if the replay put the same signup at this position.
+func (c Check) Match() bool {
+	return c.Recorded == c.Replayed
+}
+
+// Verification is the outcome of replaying a recorded draw.
+type Verification struct {
+	AlgorithmVersion string
+	AuditSeed        int64
+	DrawnAt          time.Time
+	// Weights is the ticket table the draw was replayed with.
+	Weights model.LotteryWeights
+	// InputHash identifies the set of signups that entered the draw; see
+	// InputHash.
+	InputHash string
+	Checks    []Check
+}
+
+// Match returns true if every recorded position matched the replay.
+func (v Verification) Match() bool {
+	for _, c := range v.Checks {
+		if !c.Match() {
+			return false
+		}
+	}
+	return len(v.Checks) > 0
+}
+
+// Mismatches returns the number of positions that differ from the replay.
+func (v Verification) Mismatches() int {
+	n := 0
+	for _, c := range v.Checks {
+		if !c.Match() {
+			n++
+		}
+	}
+	return n
+}
+
+// InputHash returns the hex SHA-256 of the entries' signup IDs in
+// ascending order, each written in canonical UUID form and followed by a
+// newline. Anyone with the published entry IDs can recompute it.
+func InputHash(entries []model.Signup) string {
+	h := sha256.New()
+	for _, e := range canonical(entries) {
+		h.Write([]byte(e.ID.String() + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Replay re-runs a recorded draw over its entries and compares each
+// recorded position with the replayed one. results must be in position
+// order and weights must be the hunt's ticket table, which
+// HuntRepository.Update refuses to change once signups close.
+func Replay(results []model.LotteryResult, entries []model.Signup, weights model.LotteryWeights) (Verification, error) {
+	if len(results) == 0 {
+		return Verification{}, ErrNotDrawn
+	}
+	first := results[0]
+	for _, r := range results {
+		if r.AuditSeed != first.AuditSeed || r.AlgorithmVersion != first.AlgorithmVersion {
+			return Verification{}, ErrInconsistentDraw
+		}
+	}
+	algorithm, err := Lookup(first.AlgorithmVersion)
+	if err != nil {
+		return Verification{}, err
+	}
+
+	replayed, err := algorithm.Order(Input{Entries: entries, Seed: first.AuditSeed, Weights: weights})
+	if err != nil {
+		return Verification{}, err
+	}
+	v := Verification{
+		AlgorithmVersion: first.AlgorithmVersion,
+		AuditSeed:        first.AuditSeed,
+		DrawnAt:          first.DrawnAt,
+		Weights:          weights,
+		InputHash:        InputHash(entries),
+	}
+	for i, r := range results {
+		c := Check{Position: r.Position, Recorded: r.SignupID}
+		if i < len(replayed) && r.Position == i+1 {
+			c.Replayed = replayed[i].ID
+		}
+		v.Checks = append(v.Checks, c)
+	}
+	return v, nil
+}
+
+// Verifier replays hunts' recorded draws from the database.
+type Verifier struct {
+	db *sql.DB
+}
+
+// NewVerifier creates a Verifier.
+func NewVerifier(db *sql.DB) *Verifier {
+	return &Verifier{db: db}
+}
+
+// Verify replays a hunt's draw from its recorded results and the signups
+// that were entered in it. Returns ErrNotDrawn if the hunt has no results.
+func (v *Verifier) Verify(ctx context.Context, huntID uuid.UUID) (Verification, error) {
+	hunt, err := repository.NewHuntRepository(v.db).GetByID(ctx, huntID)
+	if err != nil {
+		return Verification{}, fmt.Errorf("verifying lottery: %w", err)
+	}
+	results, err := repository.NewLotteryResultRepository(v.db).ListByHunt(ctx, huntID)
+	if err != nil {
+		return Verification{}, fmt.Errorf("verifying lottery: %w", err)
+	}
+	entries, err := repository.NewSignupRepository(v.db).ListDrawnByHunt(ctx, huntID)
+	if err != nil {
+		return Verification{}, fmt.Errorf("verifying lottery: %w", err)
+	}
+	verification, err := Replay(results, entries, hunt.LotteryWeights)
+	if err != nil {
+		return Verification{}, fmt.Errorf("verifying lottery: %w", err)
+	}
+	return verification, nil
+}
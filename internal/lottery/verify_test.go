@@ -0,0 +1,94 @@
+package lottery
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// recordDraw returns the results Drawer would record for entries.
+func recordDraw(t testing.TB, entries []model.Signup, seed int64) []model.LotteryResult {
+	var results []model.LotteryResult
+	for i, e := range order(t, Uniform{}, Input{Entries: entries, Seed: seed}) {
+		results = append(results, model.LotteryResult{
+			SignupID:         e.ID,
+			Position:         i + 1,
+			AuditSeed:        seed,
+			AlgorithmVersion: UniformV1,
+		})
+	}
+	return results
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("matches an untouched draw", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(10)
+
+		v, err := Replay(recordDraw(t, entries, 99), entries, model.LotteryWeights{})
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v.Match()).To(BeTrue())
+		g.Expect(v.Checks).To(HaveLen(10))
+		g.Expect(v.InputHash).To(Equal(InputHash(entries)))
+	})
+
+	t.Run("flags swapped positions", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(10)
+		results := recordDraw(t, entries, 99)
+		results[0].SignupID, results[1].SignupID = results[1].SignupID, results[0].SignupID
+
+		v, err := Replay(results, entries, model.LotteryWeights{})
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v.Match()).To(BeFalse())
+		g.Expect(v.Mismatches()).To(Equal(2))
+	})
+
+	t.Run("rejects results from different draws", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(4)
+		results := recordDraw(t, entries, 99)
+		results[2].AuditSeed = 100
+
+		_, err := Replay(results, entries, model.LotteryWeights{})
+
+		g.Expect(err).To(MatchError(ErrInconsistentDraw))
+	})
+
+	t.Run("returns ErrNotDrawn without results", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Replay(nil, nil, model.LotteryWeights{})
+
+		g.Expect(err).To(MatchError(ErrNotDrawn))
+	})
+
+	t.Run("rejects unknown algorithms", func(t *testing.T) {
+		g := NewWithT(t)
+		entries := testEntries(2)
+		results := recordDraw(t, entries, 1)
+		for i := range results {
+			results[i].AlgorithmVersion = "nope-v0"
+		}
+
+		_, err := Replay(results, entries, model.LotteryWeights{})
+
+		g.Expect(err).To(MatchError(ErrUnknownAlgorithm))
+	})
+}
+
+func TestInputHash(t *testing.T) {
+	g := NewWithT(t)
+	entries := testEntries(3)
+	reversed := slices.Clone(entries)
+	slices.Reverse(reversed)
+
+	g.Expect(InputHash(reversed)).To(Equal(InputHash(entries)))
+	g.Expect(InputHash(entries)).To(HaveLen(64))
+	g.Expect(InputHash(entries[:2])).ToNot(Equal(InputHash(entries)))
+}
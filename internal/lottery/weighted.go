@@ -0,0 +1,68 @@
+package lottery
+
+import "github.com/brian-abo/tfo-webapp/internal/model"
+
+// WeightedV1 is the version of the Weighted algorithm.
+const WeightedV1 = "weighted-v1"
+
+// Weighted favours members who have waited longest and those who have
+// never attended. Each entry holds tickets from the hunt's ticket table
+// and positions are filled one at a time by drawing a ticket from those
+// still in the draw. Tickets are whole numbers so draws are exact
+// integer arithmetic and replay identically everywhere.
+type Weighted struct{}
+
+// Version implements Algorithm.
+func (Weighted) Version() string {
+	return WeightedV1
+}
+
+// Order implements Algorithm. It returns ErrTooManyTickets if the
+// entries' tickets don't fit in a uint64.
+func (Weighted) Order(in Input) ([]model.Signup, error) {
+	remaining := canonical(in.Entries)
+	tickets := make([]uint64, len(remaining))
+	var total uint64
+	for i, e := range remaining {
+		tickets[i] = uint64(Tickets(in.Weights, e))
+		if total+tickets[i] < total {
+			return nil, ErrTooManyTickets
+		}
+		total += tickets[i]
+	}
+
+	r := newRNG(in.Seed)
+	ordered := make([]model.Signup, 0, len(remaining))
+	for len(remaining) > 0 {
+		pick := r.below(total)
+		i := 0
+		for pick >= tickets[i] {
+			pick -= tickets[i]
+			i++
+		}
+		ordered = append(ordered, remaining[i])
+		total -= tickets[i]
+		remaining = append(remaining[:i], remaining[i+1:]...)
+		tickets = append(tickets[:i], tickets[i+1:]...)
+	}
+	return ordered, nil
+}
+
+// Tickets returns how many tickets an entry holds under weights. Entries
+// without an eligibility snapshot get the base tickets for no missed
+// draws and no first-timer bonus. Every entry holds at least one ticket.
+func Tickets(weights model.LotteryWeights, entry model.Signup) int {
+	snapshot, err := entry.Eligibility()
+	if err != nil {
+		snapshot = model.EligibilitySnapshot{PriorHunts: 1}
+	}
+
+	n := 1
+	if table := weights.MissedDrawTickets; len(table) > 0 {
+		n = table[min(snapshot.MissedDraws, len(table)-1)]
+	}
+	if snapshot.PriorHunts == 0 {
+		n += weights.FirstTimerBonus
+	}
+	return max(n, 1)
+}
@@ -0,0 +1,106 @@
+package lottery
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// withSnapshot returns entry with an eligibility snapshot recording prior
+// hunts attended and missed draws.
+func withSnapshot(entry model.Signup, priorHunts, missedDraws int) model.Signup {
+	data, _ := json.Marshal(model.EligibilitySnapshot{PriorHunts: priorHunts, MissedDraws: missedDraws})
+	entry.EligibilitySnapshot = sql.NullString{String: string(data), Valid: true}
+	return entry
+}
+
+func TestTickets(t *testing.T) {
+	weights := model.LotteryWeights{MissedDrawTickets: []int{1, 2, 4}, FirstTimerBonus: 3}
+	entry := testEntries(1)[0]
+
+	t.Run("looks up missed draws in the table", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Tickets(weights, withSnapshot(entry, 1, 0))).To(Equal(1))
+		g.Expect(Tickets(weights, withSnapshot(entry, 1, 1))).To(Equal(2))
+		g.Expect(Tickets(weights, withSnapshot(entry, 1, 9))).To(Equal(4))
+	})
+
+	t.Run("adds the first-timer bonus", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Tickets(weights, withSnapshot(entry, 0, 1))).To(Equal(5))
+	})
+
+	t.Run("gives one ticket with an empty table", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Tickets(model.LotteryWeights{}, withSnapshot(entry, 1, 5))).To(Equal(1))
+	})
+
+	t.Run("gives base tickets without a snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Tickets(weights, entry)).To(Equal(1))
+	})
+}
+
+func TestWeighted_Order(t *testing.T) {
+	weights := model.LotteryWeights{MissedDrawTickets: []int{1, 2, 4}, FirstTimerBonus: 3}
+	entries := testEntries(8)
+	for i := range entries {
+		entries[i] = withSnapshot(entries[i], i%2, i%4)
+	}
+
+	t.Run("is a reproducible permutation regardless of input order", func(t *testing.T) {
+		g := NewWithT(t)
+		reversed := slices.Clone(entries)
+		slices.Reverse(reversed)
+
+		first := order(t, Weighted{}, Input{Entries: entries, Seed: 11, Weights: weights})
+		second := order(t, Weighted{}, Input{Entries: reversed, Seed: 11, Weights: weights})
+
+		g.Expect(first).To(ConsistOf(entries))
+		g.Expect(second).To(Equal(first))
+	})
+
+	t.Run("favours entries with more tickets", func(t *testing.T) {
+		g := NewWithT(t)
+		pair := []model.Signup{withSnapshot(entries[0], 1, 0), withSnapshot(entries[1], 1, 2)}
+
+		firsts := 0
+		for seed := range int64(1000) {
+			if order(t, Weighted{}, Input{Entries: pair, Seed: seed, Weights: weights})[0].ID == pair[1].ID {
+				firsts++
+			}
+		}
+
+		// Four tickets to one: expect about 800 of 1000.
+		g.Expect(firsts).To(BeNumerically("~", 800, 60))
+	})
+
+	t.Run("refuses tickets that overflow the total", func(t *testing.T) {
+		g := NewWithT(t)
+		huge := model.LotteryWeights{MissedDrawTickets: []int{math.MaxInt}}
+
+		_, err := Weighted{}.Order(Input{Entries: entries, Seed: 1, Weights: huge})
+
+		g.Expect(err).To(MatchError(ErrTooManyTickets))
+	})
+
+	// Recorded draws are replayed with the same version, so its output
+	// for a given seed must never change.
+	t.Run("matches the recorded ordering", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := order(t, Weighted{}, Input{Entries: entries, Seed: 20261018, Weights: weights})
+
+		g.Expect(entryNumbers(got)).To(Equal([]int{2, 1, 5, 3, 7, 6, 8, 4}))
+	})
+}
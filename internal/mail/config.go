@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+)
+
+// Transports a Sender can be built for.
+const (
+	TransportSMTP   = "smtp"
+	TransportFile   = "file"
+	TransportStdout = "stdout"
+)
+
+// Config selects and configures the mail transport.
+type Config struct {
+	Transport string
+	// From is the sender address, optionally with a display name.
+	From string
+	// Dir is where the file transport writes messages.
+	Dir          string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// NewSender builds the Sender cfg describes.
+func NewSender(cfg Config) (Sender, error) {
+	switch cfg.Transport {
+	case TransportSMTP:
+		return NewSMTPSender(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+	case TransportFile:
+		return NewFileSender(cfg.Dir, cfg.From), nil
+	case TransportStdout:
+		return NewStdoutSender(os.Stdout, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", cfg.Transport)
+	}
+}
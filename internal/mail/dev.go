@@ -0,0 +1,85 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSender is a development Sender. Given a directory it writes each
+// message there as an .eml file that mail clients can open; otherwise it
+// prints each message's headers and plain text to a writer.
+type FileSender struct {
+	dir  string
+	out  io.Writer
+	from string
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewFileSender creates a FileSender writing .eml files into dir, which is
+// created if needed.
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{dir: dir, from: from, now: time.Now}
+}
+
+// NewStdoutSender creates a FileSender printing messages to w.
+func NewStdoutSender(w io.Writer, from string) *FileSender {
+	return &FileSender{out: w, from: from, now: time.Now}
+}
+
+// Send writes m out.
+func (s *FileSender) Send(_ context.Context, m Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	data, err := m.encode(s.from, now)
+	if err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	if s.dir == "" {
+		_, err := fmt.Fprintf(s.out, "--- email from %s to %s\nSubject: %s\n\n%s\n---\n",
+			s.from, strings.Join(m.To, ", "), m.Subject, strings.TrimSpace(m.Text))
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	name := now.UTC().Format("20060102T150405.000000000") + "-" + slug(m.Subject) + ".eml"
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	return nil
+}
+
+// slug reduces a subject to a short, filename-safe form.
+func slug(subject string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(subject) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 40 {
+			break
+		}
+	}
+	s := strings.Trim(b.String(), "-")
+	if s == "" {
+		return "message"
+	}
+	return s
+}
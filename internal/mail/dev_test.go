@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFileSender(t *testing.T) {
+	m := Message{To: []string{"jane@example.com"}, Subject: "Hunt cancelled", HTML: "<p>Sorry</p>", Text: "Sorry"}
+
+	t.Run("writes each message as an .eml file", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := filepath.Join(t.TempDir(), "mail")
+		s := NewFileSender(dir, "noreply@example.org")
+		s.now = func() time.Time { return time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC) }
+
+		g.Expect(s.Send(context.Background(), m)).To(Succeed())
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(files).To(HaveLen(1))
+		g.Expect(filepath.Base(files[0])).To(Equal("20300102T150405.000000000-hunt-cancelled.eml"))
+
+		data, err := os.ReadFile(files[0])
+		g.Expect(err).ToNot(HaveOccurred())
+		header, bodies := parts(g, data)
+		g.Expect(header.Get("Subject")).To(Equal("Hunt cancelled"))
+		g.Expect(bodies).To(HaveKeyWithValue("text/html; charset=UTF-8", "<p>Sorry</p>"))
+	})
+
+	t.Run("prints the plain text to a writer", func(t *testing.T) {
+		g := NewWithT(t)
+		var out bytes.Buffer
+		s := NewStdoutSender(&out, "noreply@example.org")
+
+		g.Expect(s.Send(context.Background(), m)).To(Succeed())
+
+		g.Expect(out.String()).To(ContainSubstring("to jane@example.com"))
+		g.Expect(out.String()).To(ContainSubstring("Subject: Hunt cancelled\n\nSorry\n"))
+		g.Expect(out.String()).ToNot(ContainSubstring("<p>"))
+	})
+
+	t.Run("rejects invalid messages", func(t *testing.T) {
+		g := NewWithT(t)
+		var out bytes.Buffer
+		s := NewStdoutSender(&out, "noreply@example.org")
+
+		g.Expect(s.Send(context.Background(), Message{})).To(MatchError(ErrNoRecipients))
+		g.Expect(out.Len()).To(BeZero())
+	})
+}
@@ -0,0 +1,152 @@
+// Package mail sends email. Messages carry templ-rendered HTML with a
+// plain-text alternative and are delivered by a Sender: SMTP in
+// production, files or stdout in development and a Recorder in tests.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// ErrNoRecipients is returned when sending a message without recipients.
+var ErrNoRecipients = errors.New("message has no recipients")
+
+// Message is an email ready to send.
+type Message struct {
+	To []string
+	// ReplyTo is where replies go instead of the sender, if set.
+	ReplyTo string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Sender delivers messages.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// Compose builds a message by rendering html. text is the plain-text
+// alternative for clients that don't show HTML.
+func Compose(ctx context.Context, to []string, subject string, html templ.Component, text string) (Message, error) {
+	var b strings.Builder
+	if err := html.Render(ctx, &b); err != nil {
+		return Message{}, fmt.Errorf("rendering email: %w", err)
+	}
+	return Message{To: to, Subject: subject, HTML: b.String(), Text: text}, nil
+}
+
+// recipients parses the message's To addresses, so they can't smuggle
+// extra headers or recipients.
+func (m Message) recipients() ([]*mail.Address, error) {
+	if len(m.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+	to := make([]*mail.Address, len(m.To))
+	for i, addr := range m.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+		to[i] = a
+	}
+	return to, nil
+}
+
+// encode formats the message as a MIME multipart/alternative email from
+// from, sent at date.
+func (m Message) encode(from string, date time.Time) ([]byte, error) {
+	to, err := m.recipients()
+	if err != nil {
+		return nil, err
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	var replyTo *mail.Address
+	if m.ReplyTo != "" {
+		if replyTo, err = mail.ParseAddress(m.ReplyTo); err != nil {
+			return nil, fmt.Errorf("invalid reply-to %q: %w", m.ReplyTo, err)
+		}
+	}
+	id, err := messageID(sender.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, alt := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alt.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(alt.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&msg, "%s: %s\r\n", k, v) }
+	header("From", sender.String())
+	header("To", formatList(to))
+	if replyTo != nil {
+		header("Reply-To", replyTo.String())
+	}
+	header("Subject", mime.QEncoding.Encode("UTF-8", m.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", id)
+	header("MIME-Version", "1.0")
+	header("Content-Type", `multipart/alternative; boundary="`+parts.Boundary()+`"`)
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func formatList(addrs []*mail.Address) string {
+	out := make([]string, len(addrs))
+	for i, a := range addrs {
+		out[i] = a.String()
+	}
+	return strings.Join(out, ", ")
+}
+
+// messageID returns a unique Message-ID in the sender's domain.
+func messageID(from string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating message ID: %w", err)
+	}
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = from[i+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">", nil
+}
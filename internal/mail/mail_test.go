@@ -0,0 +1,208 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	. "github.com/onsi/gomega"
+)
+
+// parts parses an encoded message, returning its headers and the bodies
+// of its parts keyed by content type.
+func parts(g *WithT, data []byte) (mail.Header, map[string]string) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	g.Expect(err).ToNot(HaveOccurred())
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mediaType).To(Equal("multipart/alternative"))
+
+	bodies := map[string]string{}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		b, err := io.ReadAll(p)
+		g.Expect(err).ToNot(HaveOccurred())
+		bodies[p.Header.Get("Content-Type")] = string(b)
+	}
+	return msg.Header, bodies
+}
+
+func TestCompose(t *testing.T) {
+	t.Run("renders the HTML component", func(t *testing.T) {
+		g := NewWithT(t)
+		html := templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, "<p>Hi</p>")
+			return err
+		})
+
+		m, err := Compose(context.Background(), []string{"jane@example.com"}, "Hello", html, "Hi")
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(m).To(Equal(Message{To: []string{"jane@example.com"}, Subject: "Hello", HTML: "<p>Hi</p>", Text: "Hi"}))
+	})
+
+	t.Run("returns render errors", func(t *testing.T) {
+		g := NewWithT(t)
+		html := templ.ComponentFunc(func(context.Context, io.Writer) error { return errors.New("boom") })
+
+		_, err := Compose(context.Background(), []string{"jane@example.com"}, "Hello", html, "Hi")
+
+		g.Expect(err).To(MatchError(ContainSubstring("boom")))
+	})
+}
+
+func TestMessageEncode(t *testing.T) {
+	date := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	t.Run("encodes plain-text and HTML alternatives", func(t *testing.T) {
+		g := NewWithT(t)
+		m := Message{
+			To:      []string{"Jane Doe <jane@example.com>", "joe@example.com"},
+			ReplyTo: "visitor@example.com",
+			Subject: "Lottery results",
+			HTML:    "<p>You're in!</p>",
+			Text:    "You're in!",
+		}
+
+		data, err := m.encode("The Fallen Outdoors <noreply@example.org>", date)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		header, bodies := parts(g, data)
+		g.Expect(header.Get("From")).To(Equal(`"The Fallen Outdoors" <noreply@example.org>`))
+		g.Expect(header.Get("To")).To(Equal(`"Jane Doe" <jane@example.com>, <joe@example.com>`))
+		g.Expect(header.Get("Reply-To")).To(Equal("<visitor@example.com>"))
+		g.Expect(header.Get("Subject")).To(Equal("Lottery results"))
+		g.Expect(header.Get("Date")).To(Equal("Wed, 02 Jan 2030 15:04:05 +0000"))
+		g.Expect(header.Get("Message-ID")).To(HaveSuffix("@example.org>"))
+		g.Expect(bodies).To(HaveKeyWithValue("text/plain; charset=UTF-8", "You're in!"))
+		g.Expect(bodies).To(HaveKeyWithValue("text/html; charset=UTF-8", "<p>You're in!</p>"))
+	})
+
+	t.Run("encodes non-ASCII subjects", func(t *testing.T) {
+		g := NewWithT(t)
+		m := Message{To: []string{"jane@example.com"}, Subject: "Café hunt"}
+
+		data, err := m.encode("noreply@example.org", date)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		header, _ := parts(g, data)
+		subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(subject).To(Equal("Café hunt"))
+	})
+
+	t.Run("keeps line breaks in the subject out of the headers", func(t *testing.T) {
+		g := NewWithT(t)
+		m := Message{To: []string{"jane@example.com"}, Subject: "Hi\r\nBcc: attacker@example.com"}
+
+		data, err := m.encode("noreply@example.org", date)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		header, _ := parts(g, data)
+		g.Expect(header.Get("Bcc")).To(BeEmpty())
+	})
+
+	t.Run("rejects invalid addresses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Message{To: []string{"jane@example.com\r\nBcc: attacker@example.com"}}.encode("noreply@example.org", date)
+		g.Expect(err).To(MatchError(ContainSubstring("invalid recipient")))
+
+		_, err = Message{To: []string{"jane@example.com"}, ReplyTo: "nope"}.encode("noreply@example.org", date)
+		g.Expect(err).To(MatchError(ContainSubstring("invalid reply-to")))
+
+		_, err = Message{To: []string{"jane@example.com"}}.encode("", date)
+		g.Expect(err).To(MatchError(ContainSubstring("invalid sender")))
+	})
+
+	t.Run("requires a recipient", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := Message{Subject: "Hi"}.encode("noreply@example.org", date)
+
+		g.Expect(err).To(MatchError(ErrNoRecipients))
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("records sent messages in order", func(t *testing.T) {
+		g := NewWithT(t)
+		r := &Recorder{}
+
+		g.Expect(r.Send(context.Background(), Message{To: []string{"a@example.com"}, Subject: "One"})).To(Succeed())
+		g.Expect(r.Send(context.Background(), Message{To: []string{"b@example.com"}, Subject: "Two"})).To(Succeed())
+
+		sent := r.Sent()
+		g.Expect(sent).To(HaveLen(2))
+		g.Expect(sent[0].Subject).To(Equal("One"))
+		g.Expect(sent[1].Subject).To(Equal("Two"))
+	})
+
+	t.Run("fails with Err without recording", func(t *testing.T) {
+		g := NewWithT(t)
+		r := &Recorder{Err: errors.New("relay down")}
+
+		err := r.Send(context.Background(), Message{To: []string{"a@example.com"}})
+
+		g.Expect(err).To(MatchError("relay down"))
+		g.Expect(r.Sent()).To(BeEmpty())
+	})
+
+	t.Run("rejects messages a real sender would", func(t *testing.T) {
+		g := NewWithT(t)
+		r := &Recorder{}
+
+		g.Expect(r.Send(context.Background(), Message{})).To(MatchError(ErrNoRecipients))
+	})
+}
+
+func TestNewSender(t *testing.T) {
+	t.Run("builds each transport", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s, err := NewSender(Config{Transport: TransportSMTP, SMTPAddr: "localhost:587", From: "noreply@example.org"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(s).To(BeAssignableToTypeOf(&SMTPSender{}))
+
+		s, err = NewSender(Config{Transport: TransportFile, Dir: t.TempDir(), From: "noreply@example.org"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(s).To(BeAssignableToTypeOf(&FileSender{}))
+
+		s, err = NewSender(Config{Transport: TransportStdout, From: "noreply@example.org"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(s).To(BeAssignableToTypeOf(&FileSender{}))
+	})
+
+	t.Run("rejects unknown transports and bad SMTP settings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := NewSender(Config{Transport: "pigeon"})
+		g.Expect(err).To(MatchError(ContainSubstring("unknown mail transport")))
+
+		_, err = NewSender(Config{Transport: TransportSMTP, SMTPAddr: "localhost", From: "noreply@example.org"})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid SMTP address")))
+	})
+}
+
+func TestSlug(t *testing.T) {
+	t.Run("reduces subjects to filename-safe words", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(slug("Iowa Whitetail has been cancelled!")).To(Equal("iowa-whitetail-has-been-cancelled"))
+		g.Expect(slug("  ")).To(Equal("message"))
+		g.Expect(len(slug(strings.Repeat("word ", 20)))).To(BeNumerically("<=", 40))
+	})
+}
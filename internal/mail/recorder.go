@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Recorder is a Sender for tests. It keeps messages instead of sending
+// them, or fails with Err when set.
+type Recorder struct {
+	// Err, if set, is returned by Send and the message is not recorded.
+	Err error
+
+	mu   sync.Mutex
+	sent []Message
+}
+
+// Send records m.
+func (r *Recorder) Send(_ context.Context, m Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	if _, err := m.recipients(); err != nil {
+		return err
+	}
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+// Sent returns the messages recorded so far, oldest first.
+func (r *Recorder) Sent() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Message(nil), r.sent...)
+}
@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// smtpSession is what a fakeSMTP server received in one session.
+type smtpSession struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP serves a single SMTP session without TLS or auth, reporting
+// what it received on the returned channel.
+func fakeSMTP(t *testing.T) (string, <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var s smtpSession
+		tp.PrintfLine("220 localhost ready")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 localhost")
+			case "MAIL":
+				s.from = strings.TrimSuffix(strings.TrimPrefix(line, "MAIL FROM:<"), ">")
+				tp.PrintfLine("250 OK")
+			case "RCPT":
+				s.to = append(s.to, strings.TrimSuffix(strings.TrimPrefix(line, "RCPT TO:<"), ">"))
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 Go ahead")
+				lines, err := tp.ReadDotLines()
+				if err != nil {
+					return
+				}
+				s.data = strings.Join(lines, "\r\n")
+				tp.PrintfLine("250 Queued")
+			case "QUIT":
+				tp.PrintfLine("221 Bye")
+				sessions <- s
+				return
+			default:
+				tp.PrintfLine("502 Not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), sessions
+}
+
+func TestSMTPSender(t *testing.T) {
+	t.Run("delivers to each recipient with bare envelope addresses", func(t *testing.T) {
+		g := NewWithT(t)
+		addr, sessions := fakeSMTP(t)
+		s, err := NewSMTPSender(addr, "", "", "The Fallen Outdoors <noreply@example.org>")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = s.Send(context.Background(), Message{
+			To:      []string{"Jane Doe <jane@example.com>", "joe@example.com"},
+			Subject: "Lottery results",
+			HTML:    "<p>You're in!</p>",
+			Text:    "You're in!",
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var got smtpSession
+		g.Eventually(sessions).Should(Receive(&got))
+		g.Expect(got.from).To(Equal("noreply@example.org"))
+		g.Expect(got.to).To(Equal([]string{"jane@example.com", "joe@example.com"}))
+
+		header, bodies := parts(g, []byte(got.data+"\r\n"))
+		g.Expect(header.Get("Subject")).To(Equal("Lottery results"))
+		g.Expect(bodies).To(HaveKeyWithValue("text/plain; charset=UTF-8", "You're in!"))
+	})
+
+	t.Run("rejects invalid messages before connecting", func(t *testing.T) {
+		g := NewWithT(t)
+		s, err := NewSMTPSender("127.0.0.1:1", "", "", "noreply@example.org")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(s.Send(context.Background(), Message{})).To(MatchError(ErrNoRecipients))
+	})
+
+	t.Run("gives up when the context is done", func(t *testing.T) {
+		g := NewWithT(t)
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		g.Expect(err).ToNot(HaveOccurred())
+		defer ln.Close()
+		// Accept but never greet, so the client waits for the banner.
+		go func() {
+			conn, err := ln.Accept()
+			if err == nil {
+				defer conn.Close()
+				bufio.NewReader(conn).ReadString('\n')
+			}
+		}()
+		s, err := NewSMTPSender(ln.Addr().String(), "", "", "noreply@example.org")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err = s.Send(ctx, Message{To: []string{"jane@example.com"}})
+
+		g.Expect(err).To(HaveOccurred())
+	})
+}
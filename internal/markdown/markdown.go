@@ -0,0 +1,170 @@
+// Package markdown renders the small subset of Markdown staff use in
+// after-action reports to HTML that is safe to embed in a page.
+//
+// Safety comes from escaping first: every character of the source is HTML
+// escaped before any formatting is recognised, so raw HTML in the source
+// is shown as text, never interpreted. Links are only kept for http, https
+// and mailto URLs.
+//
+// Supported: paragraphs, # headings, - and 1. lists, > quotes, **bold**,
+// *italic* or _italic_, `code` and [links](https://example.com).
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletRe  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRe = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	quoteRe   = regexp.MustCompile(`^\s*>\s?(.*)$`)
+
+	codeRe        = regexp.MustCompile("`([^`]+)`")
+	linkRe        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strongRe      = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	starEmRe      = regexp.MustCompile(`\*([^*]+)\*`)
+	underscoreRe  = regexp.MustCompile(`(^|\W)_([^_]+)_(\W|$)`)
+	placeholderRe = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// Render converts src to HTML.
+func Render(src string) string {
+	r := &renderer{}
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		r.line(line)
+	}
+	r.flush()
+	return r.out.String()
+}
+
+// renderer accumulates the block being built: a paragraph or quote's lines
+// or an open list.
+type renderer struct {
+	out   strings.Builder
+	para  []string
+	quote []string
+	list  string // "ul", "ol" or "" when no list is open
+}
+
+func (r *renderer) line(line string) {
+	if strings.TrimSpace(line) == "" {
+		r.flush()
+		return
+	}
+	if m := headingRe.FindStringSubmatch(line); m != nil {
+		r.flush()
+		// Reports sit under the page's own h1, so # starts at h2.
+		level := strconv.Itoa(min(len(m[1])+1, 6))
+		r.out.WriteString("<h" + level + ">" + inline(m[2]) + "</h" + level + ">\n")
+		return
+	}
+	if m := bulletRe.FindStringSubmatch(line); m != nil {
+		r.item("ul", m[1])
+		return
+	}
+	if m := orderedRe.FindStringSubmatch(line); m != nil {
+		r.item("ol", m[1])
+		return
+	}
+	if m := quoteRe.FindStringSubmatch(line); m != nil {
+		if r.quote == nil {
+			r.flush()
+		}
+		r.quote = append(r.quote, m[1])
+		return
+	}
+	if r.list != "" || r.quote != nil {
+		r.flush()
+	}
+	r.para = append(r.para, strings.TrimSpace(line))
+}
+
+// item adds a list item, opening a list of kind if one isn't open.
+func (r *renderer) item(kind, text string) {
+	if r.list != kind {
+		r.flush()
+		r.list = kind
+		r.out.WriteString("<" + kind + ">\n")
+	}
+	r.out.WriteString("<li>" + inline(text) + "</li>\n")
+}
+
+// flush closes whatever block is open.
+func (r *renderer) flush() {
+	if len(r.para) > 0 {
+		r.out.WriteString("<p>" + inline(strings.Join(r.para, " ")) + "</p>\n")
+		r.para = nil
+	}
+	if r.quote != nil {
+		r.out.WriteString("<blockquote><p>" + inline(strings.Join(r.quote, " ")) + "</p></blockquote>\n")
+		r.quote = nil
+	}
+	if r.list != "" {
+		r.out.WriteString("</" + r.list + ">\n")
+		r.list = ""
+	}
+}
+
+// inline escapes text and applies inline formatting. Code spans and links
+// are swapped for placeholders while emphasis is applied so their contents
+// and URLs aren't reformatted.
+func inline(text string) string {
+	var held []string
+	hold := func(s string) string {
+		held = append(held, s)
+		return "\x00" + strconv.Itoa(len(held)-1) + "\x00"
+	}
+
+	s := html.EscapeString(strings.ReplaceAll(text, "\x00", ""))
+	s = codeRe.ReplaceAllStringFunc(s, func(m string) string {
+		return hold("<code>" + m[1:len(m)-1] + "</code>")
+	})
+	s = linkRe.ReplaceAllStringFunc(s, func(m string) string {
+		parts := linkRe.FindStringSubmatch(m)
+		label := emphasis(parts[1])
+		href, ok := safeURL(parts[2])
+		if !ok {
+			return hold(label)
+		}
+		return hold(`<a href="` + href + `" rel="nofollow noopener">` + label + `</a>`)
+	})
+	s = emphasis(s)
+
+	// Links may hold code spans, so restore until nothing is left.
+	for range len(held) + 1 {
+		if !strings.Contains(s, "\x00") {
+			break
+		}
+		s = placeholderRe.ReplaceAllStringFunc(s, func(m string) string {
+			i, _ := strconv.Atoi(strings.Trim(m, "\x00"))
+			return held[i]
+		})
+	}
+	return s
+}
+
+func emphasis(s string) string {
+	s = strongRe.ReplaceAllString(s, "<strong>$1</strong>")
+	s = starEmRe.ReplaceAllString(s, "<em>$1</em>")
+	return underscoreRe.ReplaceAllString(s, "$1<em>$2</em>$3")
+}
+
+// safeURL takes an HTML-escaped link target and returns it escaped for an
+// attribute, or false unless it is an http, https or mailto URL.
+func safeURL(escaped string) (string, bool) {
+	raw := html.UnescapeString(escaped)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return html.EscapeString(u.String()), true
+	}
+	return "", false
+}
@@ -0,0 +1,74 @@
+package markdown
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRender(t *testing.T) {
+	t.Run("renders paragraphs and headings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := Render("# Day one\n\nCold morning,\nclear skies.\n\nSecond paragraph.")
+
+		g.Expect(out).To(Equal("<h2>Day one</h2>\n<p>Cold morning, clear skies.</p>\n<p>Second paragraph.</p>\n"))
+	})
+
+	t.Run("renders lists and quotes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := Render("- one\n- two\n\n1. first\n2) second\n> well said")
+
+		g.Expect(out).To(Equal("<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n" +
+			"<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n" +
+			"<blockquote><p>well said</p></blockquote>\n"))
+	})
+
+	t.Run("renders inline formatting", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := Render("**Two** bucks, *one* doe, _no_ misses and `8x` glass.")
+
+		g.Expect(out).To(Equal("<p><strong>Two</strong> bucks, <em>one</em> doe, <em>no</em> misses and <code>8x</code> glass.</p>\n"))
+	})
+
+	t.Run("leaves underscores inside words alone", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Render("snake_case_name")).To(Equal("<p>snake_case_name</p>\n"))
+	})
+
+	t.Run("renders safe links without reformatting the url", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := Render("See [the _ranch_](https://example.com/a_b_c?x=1&y=2).")
+
+		g.Expect(out).To(Equal(`<p>See <a href="https://example.com/a_b_c?x=1&amp;y=2" rel="nofollow noopener">the <em>ranch</em></a>.</p>` + "\n"))
+	})
+
+	t.Run("escapes raw html", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := Render(`<script>alert("x")</script> <img src=x onerror=alert(1)>`)
+
+		g.Expect(out).ToNot(ContainSubstring("<script"))
+		g.Expect(out).ToNot(ContainSubstring("<img"))
+		g.Expect(out).To(ContainSubstring("&lt;script&gt;"))
+	})
+
+	t.Run("drops unsafe link targets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := Render(`[click](javascript:alert(1)) [data](data:text/html;base64,xx)`)
+
+		g.Expect(out).ToNot(ContainSubstring("href"))
+		g.Expect(out).To(ContainSubstring("click"))
+	})
+
+	t.Run("ignores smuggled placeholders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Render("a \x000\x00 b")).To(Equal("<p>a 0 b</p>\n"))
+	})
+}
@@ -0,0 +1,150 @@
+// Package membership implements the staff workflow for approving, rejecting
+// and suspending members.
+package membership
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/jobs"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+var (
+	// ErrReasonRequired is returned when a decision has no reason.
+	ErrReasonRequired = errors.New("a reason is required")
+	// ErrInvalidTransition is returned when the member's current status
+	// cannot move to the requested status.
+	ErrInvalidTransition = errors.New("invalid membership transition")
+	// ErrNotStaff is returned when the deciding user is not staff.
+	ErrNotStaff = errors.New("only staff can make membership decisions")
+	// ErrSelfDecision is returned when staff try to change their own status.
+	ErrSelfDecision = errors.New("staff cannot decide on their own membership")
+	// ErrOutranked is returned when staff try to decide on a member whose
+	// role is the same as or above their own.
+	ErrOutranked = errors.New("staff cannot decide on members of their own rank or above")
+)
+
+// Notifier tells a member about a decision on their membership.
+type Notifier interface {
+	MembershipDecided(ctx context.Context, member model.User, d model.MembershipDecision) error
+}
+
+// JobMembershipDecided is the kind of job that tells a member about a
+// decision on their membership.
+const JobMembershipDecided = "membership.decided"
+
+type membershipDecidedJob struct {
+	Decision model.MembershipDecision `json:"decision"`
+}
+
+// Service applies membership decisions.
+type Service struct {
+	db       *sql.DB
+	notifier Notifier
+}
+
+// NewService creates a membership Service.
+func NewService(db *sql.DB, notifier Notifier) *Service {
+	return &Service{db: db, notifier: notifier}
+}
+
+// Approve activates a pending member.
+func (s *Service) Approve(ctx context.Context, memberID uuid.UUID, staff model.User, reason string) (model.MembershipDecision, error) {
+	return s.Decide(ctx, memberID, model.MembershipActive, staff, reason)
+}
+
+// Reject declines a pending member.
+func (s *Service) Reject(ctx context.Context, memberID uuid.UUID, staff model.User, reason string) (model.MembershipDecision, error) {
+	return s.Decide(ctx, memberID, model.MembershipInactive, staff, reason)
+}
+
+// Suspend suspends an active member.
+func (s *Service) Suspend(ctx context.Context, memberID uuid.UUID, staff model.User, reason string) (model.MembershipDecision, error) {
+	return s.Decide(ctx, memberID, model.MembershipSuspended, staff, reason)
+}
+
+// Decide moves a member to status to, recording who decided and why. The
+// status change, its audit record and a job to notify the member are
+// written in one transaction, so a failed notification is retried rather
+// than undoing the decision.
+func (s *Service) Decide(ctx context.Context, memberID uuid.UUID, to model.MembershipStatus, staff model.User, reason string) (model.MembershipDecision, error) {
+	reason = strings.TrimSpace(reason)
+
+	var (
+		member   model.User
+		decision model.MembershipDecision
+	)
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		users := repository.NewUserRepository(tx)
+
+		var err error
+		member, err = users.GetByIDForUpdate(ctx, memberID)
+		if err != nil {
+			return err
+		}
+		if err := validateDecision(member, to, staff, reason); err != nil {
+			return err
+		}
+		if err := users.ChangeMembershipStatus(ctx, member.ID, to); err != nil {
+			return err
+		}
+		decision, err = repository.NewMembershipDecisionRepository(tx).Insert(ctx, model.MembershipDecision{
+			UserID:      member.ID,
+			FromStatus:  member.MembershipStatus,
+			ToStatus:    to,
+			Reason:      reason,
+			DecidedByID: staff.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return jobs.Enqueue(ctx, tx, JobMembershipDecided, membershipDecidedJob{Decision: decision})
+	})
+	if err != nil {
+		return model.MembershipDecision{}, fmt.Errorf("deciding membership: %w", err)
+	}
+	return decision, nil
+}
+
+// RegisterJobs registers the handlers for the jobs the Service enqueues.
+func (s *Service) RegisterJobs(w *jobs.Worker) {
+	w.Register(JobMembershipDecided, jobs.Handle(s.membershipDecided))
+}
+
+func (s *Service) membershipDecided(ctx context.Context, job membershipDecidedJob) error {
+	member, err := repository.NewUserRepository(s.db).GetByID(ctx, job.Decision.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	return s.notifier.MembershipDecided(ctx, member, job.Decision)
+}
+
+// validateDecision checks that staff may move member to status to.
+func validateDecision(member model.User, to model.MembershipStatus, staff model.User, reason string) error {
+	if !staff.Role.AtLeast(model.RoleStaff) || !staff.IsActive() {
+		return ErrNotStaff
+	}
+	if staff.ID == member.ID {
+		return ErrSelfDecision
+	}
+	if member.Role.AtLeast(staff.Role) {
+		return ErrOutranked
+	}
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	if !member.MembershipStatus.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, member.MembershipStatus, to)
+	}
+	return nil
+}
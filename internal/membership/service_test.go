@@ -0,0 +1,66 @@
+package membership
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestValidateDecision(t *testing.T) {
+	staff := model.User{ID: uuid.New(), Role: model.RoleStaff, MembershipStatus: model.MembershipActive}
+	pending := model.User{ID: uuid.New(), Role: model.RoleMember, MembershipStatus: model.MembershipPending}
+
+	t.Run("allows staff to approve a pending member", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateDecision(pending, model.MembershipActive, staff, "Verified DD-214")
+
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("requires a reason", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateDecision(pending, model.MembershipActive, staff, "")
+
+		g.Expect(err).To(MatchError(ErrReasonRequired))
+	})
+
+	t.Run("rejects non-staff deciders", func(t *testing.T) {
+		g := NewWithT(t)
+		member := model.User{ID: uuid.New(), Role: model.RoleMember, MembershipStatus: model.MembershipActive}
+
+		err := validateDecision(pending, model.MembershipActive, member, "reason")
+
+		g.Expect(err).To(MatchError(ErrNotStaff))
+	})
+
+	t.Run("rejects decisions on oneself", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateDecision(staff, model.MembershipSuspended, staff, "reason")
+
+		g.Expect(err).To(MatchError(ErrSelfDecision))
+	})
+
+	t.Run("rejects decisions on members of the same rank or above", func(t *testing.T) {
+		g := NewWithT(t)
+		admin := model.User{ID: uuid.New(), Role: model.RoleAdmin, MembershipStatus: model.MembershipActive}
+		otherStaff := model.User{ID: uuid.New(), Role: model.RoleStaff, MembershipStatus: model.MembershipActive}
+
+		g.Expect(validateDecision(otherStaff, model.MembershipSuspended, staff, "reason")).To(MatchError(ErrOutranked))
+		g.Expect(validateDecision(admin, model.MembershipSuspended, staff, "reason")).To(MatchError(ErrOutranked))
+		g.Expect(validateDecision(otherStaff, model.MembershipSuspended, admin, "reason")).To(Succeed())
+	})
+
+	t.Run("rejects transitions the state machine forbids", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := validateDecision(pending, model.MembershipSuspended, staff, "reason")
+
+		g.Expect(err).To(MatchError(ErrInvalidTransition))
+	})
+}
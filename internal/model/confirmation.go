@@ -0,0 +1,54 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConfirmationDecision is a selected member's answer to their selection.
+type ConfirmationDecision string
+
+const (
+	ConfirmationPending  ConfirmationDecision = "pending"
+	ConfirmationAccepted ConfirmationDecision = "accepted"
+	ConfirmationDeclined ConfirmationDecision = "declined"
+	// ConfirmationExpired marks selections nobody answered by the deadline.
+	ConfirmationExpired ConfirmationDecision = "expired"
+)
+
+// Confirmation asks a selected member to accept or decline their place on
+// a hunt before Deadline. A selection isn't final until it's accepted.
+type Confirmation struct {
+	ID              uuid.UUID
+	LotteryResultID uuid.UUID
+	HuntID          uuid.UUID
+	UserID          uuid.UUID
+	Deadline        time.Time
+	Decision        ConfirmationDecision
+	Reason          string
+	RespondedAt     sql.NullTime
+	CreatedAt       time.Time
+}
+
+// IsPending returns true if the member hasn't answered yet.
+func (c *Confirmation) IsPending() bool {
+	return c.Decision == ConfirmationPending
+}
+
+// CanRespond returns true if the member may still accept or decline.
+func (c *Confirmation) CanRespond(now time.Time) bool {
+	return c.IsPending() && now.Before(c.Deadline)
+}
+
+// HoldsPlace returns true if the confirmation still occupies one of the
+// hunt's primary places: it's awaiting an answer or was accepted.
+func (c *Confirmation) HoldsPlace() bool {
+	return c.Decision == ConfirmationPending || c.Decision == ConfirmationAccepted
+}
+
+// IsFinal returns true if the member accepted their place.
+func (c *Confirmation) IsFinal() bool {
+	return c.Decision == ConfirmationAccepted
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestConfirmation_CanRespond(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("returns true while pending before the deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := &Confirmation{Decision: ConfirmationPending, Deadline: deadline}
+
+		g.Expect(c.CanRespond(deadline.Add(-time.Minute))).To(BeTrue())
+	})
+
+	t.Run("returns false at the deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := &Confirmation{Decision: ConfirmationPending, Deadline: deadline}
+
+		g.Expect(c.CanRespond(deadline)).To(BeFalse())
+	})
+
+	t.Run("returns false once answered", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := &Confirmation{Decision: ConfirmationDeclined, Deadline: deadline}
+
+		g.Expect(c.CanRespond(deadline.Add(-time.Hour))).To(BeFalse())
+	})
+}
+
+func TestConfirmation_IsFinal(t *testing.T) {
+	t.Run("returns true only when accepted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect((&Confirmation{Decision: ConfirmationAccepted}).IsFinal()).To(BeTrue())
+		g.Expect((&Confirmation{Decision: ConfirmationPending}).IsFinal()).To(BeFalse())
+		g.Expect((&Confirmation{Decision: ConfirmationExpired}).IsFinal()).To(BeFalse())
+	})
+}
+
+func TestConfirmation_HoldsPlace(t *testing.T) {
+	t.Run("pending and accepted confirmations hold a place", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect((&Confirmation{Decision: ConfirmationPending}).HoldsPlace()).To(BeTrue())
+		g.Expect((&Confirmation{Decision: ConfirmationAccepted}).HoldsPlace()).To(BeTrue())
+	})
+
+	t.Run("declined and expired confirmations free their place", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect((&Confirmation{Decision: ConfirmationDeclined}).HoldsPlace()).To(BeFalse())
+		g.Expect((&Confirmation{Decision: ConfirmationExpired}).HoldsPlace()).To(BeFalse())
+	})
+}
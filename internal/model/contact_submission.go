@@ -1,16 +1,63 @@
 package model
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ContactStatus is where a contact submission is in staff triage.
+type ContactStatus string
+
+const (
+	ContactNew        ContactStatus = "new"
+	ContactInProgress ContactStatus = "in_progress"
+	ContactResolved   ContactStatus = "resolved"
+	ContactSpam       ContactStatus = "spam"
+)
+
+// ContactStatuses returns every triage status in workflow order.
+func ContactStatuses() []ContactStatus {
+	return []ContactStatus{ContactNew, ContactInProgress, ContactResolved, ContactSpam}
+}
+
+// IsValid returns true if s is one of ContactStatuses.
+func (s ContactStatus) IsValid() bool {
+	for _, status := range ContactStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen returns true if the submission still needs attention.
+func (s ContactStatus) IsOpen() bool {
+	return s == ContactNew || s == ContactInProgress
+}
+
 // ContactSubmission represents a message submitted through the contact form.
 type ContactSubmission struct {
-	ID        uuid.UUID
-	Name      string
-	Email     string
-	Message   string
-	CreatedAt time.Time
+	ID      uuid.UUID
+	Name    string
+	Email   string
+	Message string
+	// RegionID is the region picked on the contact page map, if any.
+	RegionID sql.NullString
+	Status   ContactStatus
+	// AssigneeID is the staff member handling the submission, if any.
+	AssigneeID uuid.NullUUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ContactNote is an internal staff note on a contact submission. Notes
+// are never shown to the person who got in touch.
+type ContactNote struct {
+	ID           uuid.UUID
+	SubmissionID uuid.UUID
+	AuthorID     uuid.UUID
+	Body         string
+	CreatedAt    time.Time
 }
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestContactStatus_IsValid(t *testing.T) {
+	t.Run("accepts every triage status", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, s := range ContactStatuses() {
+			g.Expect(s.IsValid()).To(BeTrue(), string(s))
+		}
+	})
+
+	t.Run("rejects unknown statuses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ContactStatus("archived").IsValid()).To(BeFalse())
+		g.Expect(ContactStatus("").IsValid()).To(BeFalse())
+	})
+}
+
+func TestContactStatus_IsOpen(t *testing.T) {
+	t.Run("new and in-progress submissions are open", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ContactNew.IsOpen()).To(BeTrue())
+		g.Expect(ContactInProgress.IsOpen()).To(BeTrue())
+	})
+
+	t.Run("resolved and spam submissions are closed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ContactResolved.IsOpen()).To(BeFalse())
+		g.Expect(ContactSpam.IsOpen()).To(BeFalse())
+	})
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,12 +19,71 @@ const (
 	HuntStatusCancelled HuntStatus = "cancelled"
 )
 
+// huntTransitions lists the statuses each status may move to. Hunts are
+// drafted, opened for signups, closed for the lottery and completed once
+// they have taken place; any hunt that hasn't completed may be cancelled.
+var huntTransitions = map[HuntStatus][]HuntStatus{
+	HuntStatusDraft:  {HuntStatusOpen, HuntStatusCancelled},
+	HuntStatusOpen:   {HuntStatusClosed, HuntStatusCancelled},
+	HuntStatusClosed: {HuntStatusCompleted, HuntStatusCancelled},
+}
+
+// CanTransitionTo returns true if a hunt may move from s to next.
+func (s HuntStatus) CanTransitionTo(next HuntStatus) bool {
+	for _, allowed := range huntTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// HuntStatusChange records a hunt moving between statuses. Changes are
+// append-only and form the hunt's history. ChangedByID is null for
+// changes made by the system rather than staff.
+type HuntStatusChange struct {
+	ID          uuid.UUID
+	HuntID      uuid.UUID
+	FromStatus  HuntStatus
+	ToStatus    HuntStatus
+	Reason      string
+	ChangedByID uuid.NullUUID
+	ChangedAt   time.Time
+}
+
+// LotteryWeights is a hunt's ticket table for weighted lotteries. Each
+// entry gets tickets according to how long the member has waited and
+// whether they've attended before; more tickets mean better odds.
+type LotteryWeights struct {
+	// MissedDrawTickets[i] is the tickets for a member who has missed i
+	// draws since they were last selected. The last value applies to any
+	// longer wait. An empty table gives everyone one ticket.
+	MissedDrawTickets []int `json:"missed_draw_tickets,omitempty"`
+	// FirstTimerBonus is extra tickets for members who have never attended
+	// a hunt.
+	FirstTimerBonus int `json:"first_timer_bonus,omitempty"`
+}
+
+// DefaultConfirmationWindowHours is how long selected members have to
+// answer unless a hunt says otherwise.
+const DefaultConfirmationWindowHours = 72
+
+// DefaultLotteryAlgorithm is the lottery algorithm version hunts use
+// unless they say otherwise: the uniform draw, lottery.UniformV1.
+const DefaultLotteryAlgorithm = "uniform-v1"
+
+// DefaultLotteryWeights returns the ticket table new hunts start with.
+func DefaultLotteryWeights() LotteryWeights {
+	return LotteryWeights{MissedDrawTickets: []int{1, 2, 3, 4}, FirstTimerBonus: 2}
+}
+
 // Hunt represents a hunting event that members can sign up for.
 type Hunt struct {
 	ID                uuid.UUID
 	Title             string
 	Description       string
 	Location          string
+	State             string // two-letter US state code, used for filtering
 	ImageURLs         []string
 	Qualifiers        sql.NullString
 	HuntDate          time.Time
@@ -31,9 +91,18 @@ type Hunt struct {
 	SignupWindowEnd   time.Time
 	PrimaryCapacity   int
 	AlternateCapacity int
-	Status            HuntStatus
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	// LotteryAlgorithm is the version of the algorithm that draws the
+	// hunt's lottery; LotteryWeights configures weighted algorithms.
+	LotteryAlgorithm string
+	LotteryWeights   LotteryWeights
+	// ConfirmationWindowHours is how long selected members have to accept
+	// or decline their place.
+	ConfirmationWindowHours int
+	Status                  HuntStatus
+	// AutoOpen schedules a draft to open when its signup window starts.
+	AutoOpen  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // TotalCapacity returns the combined primary and alternate capacity.
@@ -50,3 +119,21 @@ func (h *Hunt) IsSignupWindowOpen(now time.Time) bool {
 func (h *Hunt) CanAcceptSignups(now time.Time) bool {
 	return h.Status == HuntStatusOpen && h.IsSignupWindowOpen(now)
 }
+
+// QualifierList returns the hunt's qualifiers one per line, which members
+// attest to when entering. Blank lines are skipped.
+func (h *Hunt) QualifierList() []string {
+	var list []string
+	for _, line := range strings.Split(h.Qualifiers.String, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			list = append(list, line)
+		}
+	}
+	return list
+}
+
+// ConfirmationDeadline returns when members selected at drawnAt must have
+// answered by.
+func (h *Hunt) ConfirmationDeadline(drawnAt time.Time) time.Time {
+	return drawnAt.Add(time.Duration(h.ConfirmationWindowHours) * time.Hour)
+}
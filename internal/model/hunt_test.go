@@ -1,6 +1,7 @@
 package model
 
 import (
+	"database/sql"
 	"testing"
 	"time"
 
@@ -119,3 +120,68 @@ func TestHunt_CanAcceptSignups(t *testing.T) {
 		g.Expect(h.CanAcceptSignups(baseTime)).To(BeFalse())
 	})
 }
+
+func TestHuntStatus_CanTransitionTo(t *testing.T) {
+	t.Run("follows the draft, open, closed, completed lifecycle", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(HuntStatusDraft.CanTransitionTo(HuntStatusOpen)).To(BeTrue())
+		g.Expect(HuntStatusOpen.CanTransitionTo(HuntStatusClosed)).To(BeTrue())
+		g.Expect(HuntStatusClosed.CanTransitionTo(HuntStatusCompleted)).To(BeTrue())
+	})
+
+	t.Run("does not skip or reverse steps", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(HuntStatusDraft.CanTransitionTo(HuntStatusClosed)).To(BeFalse())
+		g.Expect(HuntStatusOpen.CanTransitionTo(HuntStatusCompleted)).To(BeFalse())
+		g.Expect(HuntStatusClosed.CanTransitionTo(HuntStatusOpen)).To(BeFalse())
+	})
+
+	t.Run("any non-completed hunt can be cancelled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(HuntStatusDraft.CanTransitionTo(HuntStatusCancelled)).To(BeTrue())
+		g.Expect(HuntStatusOpen.CanTransitionTo(HuntStatusCancelled)).To(BeTrue())
+		g.Expect(HuntStatusClosed.CanTransitionTo(HuntStatusCancelled)).To(BeTrue())
+		g.Expect(HuntStatusCompleted.CanTransitionTo(HuntStatusCancelled)).To(BeFalse())
+	})
+
+	t.Run("completed and cancelled are final", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, next := range []HuntStatus{HuntStatusDraft, HuntStatusOpen, HuntStatusClosed, HuntStatusCompleted, HuntStatusCancelled} {
+			g.Expect(HuntStatusCompleted.CanTransitionTo(next)).To(BeFalse())
+			g.Expect(HuntStatusCancelled.CanTransitionTo(next)).To(BeFalse())
+		}
+	})
+}
+
+func TestHunt_QualifierList(t *testing.T) {
+	t.Run("returns nil without qualifiers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		h := &Hunt{}
+
+		g.Expect(h.QualifierList()).To(BeEmpty())
+	})
+
+	t.Run("splits lines and skips blanks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		h := &Hunt{Qualifiers: sql.NullString{String: " Hunter safety card\n\nCan walk 5 miles \n", Valid: true}}
+
+		g.Expect(h.QualifierList()).To(Equal([]string{"Hunter safety card", "Can walk 5 miles"}))
+	})
+}
+
+func TestHunt_ConfirmationDeadline(t *testing.T) {
+	t.Run("adds the window to the draw time", func(t *testing.T) {
+		g := NewWithT(t)
+		drawnAt := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+		h := &Hunt{ConfirmationWindowHours: 48}
+
+		g.Expect(h.ConfirmationDeadline(drawnAt)).To(Equal(drawnAt.Add(48 * time.Hour)))
+	})
+}
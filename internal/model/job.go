@@ -0,0 +1,67 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus is where a background job is in the queue.
+type JobStatus string
+
+const (
+	// JobPending jobs wait for RunAt, then for a worker.
+	JobPending JobStatus = "pending"
+	// JobRunning jobs are claimed by a worker until their lease expires.
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	// JobDead jobs failed on every attempt, or permanently, and wait for
+	// staff to retry them.
+	JobDead JobStatus = "dead"
+)
+
+// JobStatuses returns every job status in queue order.
+func JobStatuses() []JobStatus {
+	return []JobStatus{JobPending, JobRunning, JobDone, JobDead}
+}
+
+// IsValid returns true if s is one of JobStatuses.
+func (s JobStatus) IsValid() bool {
+	for _, status := range JobStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultJobMaxAttempts is how many times a job is tried before it's
+// dead-lettered.
+const DefaultJobMaxAttempts = 8
+
+// Job is a unit of background work, such as sending an email, enqueued in
+// the same transaction as the change that caused it.
+type Job struct {
+	ID uuid.UUID
+	// Kind names the handler that runs the job.
+	Kind    string
+	Payload json.RawMessage
+	Status  JobStatus
+	// Attempts counts the times a worker has claimed the job.
+	Attempts    int
+	MaxAttempts int
+	// RunAt is the earliest time the job may next run.
+	RunAt       time.Time
+	LockedUntil sql.NullTime
+	LastError   sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	FinishedAt  sql.NullTime
+}
+
+// HasAttemptsLeft returns true if a failed attempt should be retried.
+func (j *Job) HasAttemptsLeft() bool {
+	return j.Attempts < j.MaxAttempts
+}
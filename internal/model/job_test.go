@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestJobStatus(t *testing.T) {
+	t.Run("accepts known statuses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, s := range JobStatuses() {
+			g.Expect(s.IsValid()).To(BeTrue(), "status %s", s)
+		}
+		g.Expect(JobStatus("failed").IsValid()).To(BeFalse())
+		g.Expect(JobStatus("").IsValid()).To(BeFalse())
+	})
+}
+
+func TestJobHasAttemptsLeft(t *testing.T) {
+	t.Run("retries until max attempts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect((&Job{Attempts: 1, MaxAttempts: 3}).HasAttemptsLeft()).To(BeTrue())
+		g.Expect((&Job{Attempts: 3, MaxAttempts: 3}).HasAttemptsLeft()).To(BeFalse())
+	})
+}
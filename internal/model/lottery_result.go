@@ -9,6 +9,8 @@ import (
 // LotteryResult represents the outcome of a lottery draw for a signup.
 // Each signup has at most one result (unique constraint on SignupID).
 // Results are immutable; acceptance/decline is tracked in the Confirmation model.
+// Position orders every entrant: the first PrimaryCapacity positions are
+// selected and the rest wait as alternates in order.
 type LotteryResult struct {
 	ID               uuid.UUID
 	HuntID           uuid.UUID
@@ -25,3 +27,10 @@ type LotteryResult struct {
 func (r *LotteryResult) IsPrimary(primaryCapacity int) bool {
 	return r.Position <= primaryCapacity
 }
+
+// IsAlternate returns true if this result falls in the alternate positions
+// that follow the primary capacity. Alternates are offered places, in
+// position order, as primaries drop out.
+func (r *LotteryResult) IsAlternate(primaryCapacity, alternateCapacity int) bool {
+	return r.Position > primaryCapacity && r.Position <= primaryCapacity+alternateCapacity
+}
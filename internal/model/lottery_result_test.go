@@ -39,3 +39,19 @@ func TestLotteryResult_IsPrimary(t *testing.T) {
 		g.Expect(r.IsPrimary(1)).To(BeTrue())
 	})
 }
+
+func TestLotteryResult_IsAlternate(t *testing.T) {
+	t.Run("returns true for positions just past primary capacity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect((&LotteryResult{Position: 11}).IsAlternate(10, 2)).To(BeTrue())
+		g.Expect((&LotteryResult{Position: 12}).IsAlternate(10, 2)).To(BeTrue())
+	})
+
+	t.Run("returns false for primaries and positions past the alternates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect((&LotteryResult{Position: 10}).IsAlternate(10, 2)).To(BeFalse())
+		g.Expect((&LotteryResult{Position: 13}).IsAlternate(10, 2)).To(BeFalse())
+	})
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// membershipTransitions lists the statuses each status may move to.
+// Pending members are approved (active) or rejected (inactive); active
+// members may be suspended or lapse; suspended and inactive members may be
+// reinstated.
+var membershipTransitions = map[MembershipStatus][]MembershipStatus{
+	MembershipPending:   {MembershipActive, MembershipInactive},
+	MembershipActive:    {MembershipSuspended, MembershipInactive},
+	MembershipSuspended: {MembershipActive, MembershipInactive},
+	MembershipInactive:  {MembershipActive},
+}
+
+// CanTransitionTo returns true if a membership may move from s to next.
+func (s MembershipStatus) CanTransitionTo(next MembershipStatus) bool {
+	for _, allowed := range membershipTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// MembershipDecision records a staff change to a user's membership status.
+// Decisions are append-only and form the audit trail for approvals.
+type MembershipDecision struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	FromStatus  MembershipStatus
+	ToStatus    MembershipStatus
+	Reason      string
+	DecidedByID uuid.UUID
+	DecidedAt   time.Time
+}
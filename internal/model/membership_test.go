@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMembershipStatus_CanTransitionTo(t *testing.T) {
+	t.Run("pending can be approved or rejected", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(MembershipPending.CanTransitionTo(MembershipActive)).To(BeTrue())
+		g.Expect(MembershipPending.CanTransitionTo(MembershipInactive)).To(BeTrue())
+		g.Expect(MembershipPending.CanTransitionTo(MembershipSuspended)).To(BeFalse())
+	})
+
+	t.Run("active can be suspended or lapse", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(MembershipActive.CanTransitionTo(MembershipSuspended)).To(BeTrue())
+		g.Expect(MembershipActive.CanTransitionTo(MembershipInactive)).To(BeTrue())
+		g.Expect(MembershipActive.CanTransitionTo(MembershipPending)).To(BeFalse())
+	})
+
+	t.Run("suspended can be reinstated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(MembershipSuspended.CanTransitionTo(MembershipActive)).To(BeTrue())
+	})
+
+	t.Run("inactive can only be reactivated", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(MembershipInactive.CanTransitionTo(MembershipActive)).To(BeTrue())
+		g.Expect(MembershipInactive.CanTransitionTo(MembershipSuspended)).To(BeFalse())
+	})
+
+	t.Run("no status transitions to itself", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, s := range []MembershipStatus{MembershipPending, MembershipActive, MembershipSuspended, MembershipInactive} {
+			g.Expect(s.CanTransitionTo(s)).To(BeFalse(), "status %s", s)
+		}
+	})
+
+	t.Run("unknown status has no transitions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(MembershipStatus("bogus").CanTransitionTo(MembershipActive)).To(BeFalse())
+	})
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Region groups states under a regional leader. It's shown on the contact
+// page map and used to filter hunts.
+type Region struct {
+	// ID is a URL-safe slug such as "west-coast".
+	ID    string
+	Name  string
+	Color string // map fill, as #RRGGBB
+	// Position orders regions on the map and in lists.
+	Position int
+	// States are the two-letter codes of the region's states, sorted.
+	States    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// HasState returns true if the region includes the state code.
+func (r *Region) HasState(code string) bool {
+	for _, s := range r.States {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// RegionalLeader is the director visitors in a region are put in touch
+// with.
+type RegionalLeader struct {
+	RegionID  string
+	Name      string
+	Email     string
+	UpdatedAt time.Time
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRegion_HasState(t *testing.T) {
+	t.Run("returns true for the region's states only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &Region{States: []string{"IA", "IL"}}
+
+		g.Expect(r.HasState("IL")).To(BeTrue())
+		g.Expect(r.HasState("TX")).To(BeFalse())
+	})
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session represents a server-side login session.
+// Only a hash of the session token is stored; the token itself lives in the
+// user's signed cookie.
+type Session struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	// PreviousTokenHash is the token the session was last rotated from.
+	PreviousTokenHash sql.NullString
+	CreatedAt         time.Time
+	LastSeenAt        time.Time
+	RotatedAt         time.Time
+	ExpiresAt         time.Time
+	RevokedAt         sql.NullTime
+}
+
+// IsRevoked returns true if the session has been logged out or revoked.
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt.Valid
+}
+
+// IsExpired returns true if the session has passed its absolute expiry or
+// has been idle for at least idleTimeout.
+func (s *Session) IsExpired(now time.Time, idleTimeout time.Duration) bool {
+	return !now.Before(s.ExpiresAt) || now.Sub(s.LastSeenAt) >= idleTimeout
+}
+
+// IsValid returns true if the session is neither revoked nor expired.
+func (s *Session) IsValid(now time.Time, idleTimeout time.Duration) bool {
+	return !s.IsRevoked() && !s.IsExpired(now, idleTimeout)
+}
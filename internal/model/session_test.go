@@ -0,0 +1,80 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSession_IsRevoked(t *testing.T) {
+	t.Run("returns false when RevokedAt is null", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Session{RevokedAt: sql.NullTime{Valid: false}}
+
+		g.Expect(s.IsRevoked()).To(BeFalse())
+	})
+
+	t.Run("returns true when RevokedAt is set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Session{RevokedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+
+		g.Expect(s.IsRevoked()).To(BeTrue())
+	})
+}
+
+func TestSession_IsExpired(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	idle := 2 * time.Hour
+
+	t.Run("returns false when recently seen and before expiry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Session{LastSeenAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+
+		g.Expect(s.IsExpired(now, idle)).To(BeFalse())
+	})
+
+	t.Run("returns true when idle too long", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Session{LastSeenAt: now.Add(-idle), ExpiresAt: now.Add(time.Hour)}
+
+		g.Expect(s.IsExpired(now, idle)).To(BeTrue())
+	})
+
+	t.Run("returns true at absolute expiry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Session{LastSeenAt: now, ExpiresAt: now}
+
+		g.Expect(s.IsExpired(now, idle)).To(BeTrue())
+	})
+}
+
+func TestSession_IsValid(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("returns false when revoked", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Session{
+			LastSeenAt: now,
+			ExpiresAt:  now.Add(time.Hour),
+			RevokedAt:  sql.NullTime{Time: now, Valid: true},
+		}
+
+		g.Expect(s.IsValid(now, time.Hour)).To(BeFalse())
+	})
+
+	t.Run("returns true when active", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Session{LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}
+
+		g.Expect(s.IsValid(now, time.Hour)).To(BeTrue())
+	})
+}
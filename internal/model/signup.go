@@ -2,11 +2,34 @@ package model
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrNoSnapshot is returned when a signup has no eligibility snapshot.
+// Signups made before snapshots were recorded have none.
+var ErrNoSnapshot = errors.New("no eligibility snapshot")
+
+// EligibilitySnapshot records why a member was eligible when they entered
+// a lottery. It is stored as JSON with the signup so staff can audit an
+// entry after the member's profile has changed.
+type EligibilitySnapshot struct {
+	MembershipStatus MembershipStatus `json:"membership_status"`
+	BranchOfService  string           `json:"branch_of_service"`
+	// PriorHunts counts completed hunts the member was selected for.
+	PriorHunts     int        `json:"prior_hunts"`
+	LastSelectedAt *time.Time `json:"last_selected_at,omitempty"`
+	// MissedDraws counts draws the member entered without being selected
+	// since they were last selected.
+	MissedDraws        int       `json:"missed_draws"`
+	AttestedQualifiers []string  `json:"attested_qualifiers"`
+	CapturedAt         time.Time `json:"captured_at"`
+}
+
 // Signup represents a user's lottery entry for a hunt.
 // Signups are immutable once created; withdrawal is tracked via WithdrawnAt.
 type Signup struct {
@@ -22,3 +45,16 @@ type Signup struct {
 func (s *Signup) IsWithdrawn() bool {
 	return s.WithdrawnAt.Valid
 }
+
+// Eligibility decodes the signup's eligibility snapshot. Returns
+// ErrNoSnapshot if none was recorded.
+func (s *Signup) Eligibility() (EligibilitySnapshot, error) {
+	var e EligibilitySnapshot
+	if !s.EligibilitySnapshot.Valid {
+		return e, ErrNoSnapshot
+	}
+	if err := json.Unmarshal([]byte(s.EligibilitySnapshot.String), &e); err != nil {
+		return EligibilitySnapshot{}, fmt.Errorf("decoding eligibility snapshot: %w", err)
+	}
+	return e, nil
+}
@@ -25,3 +25,41 @@ func TestSignup_IsWithdrawn(t *testing.T) {
 		g.Expect(s.IsWithdrawn()).To(BeTrue())
 	})
 }
+
+func TestSignup_Eligibility(t *testing.T) {
+	t.Run("returns ErrNoSnapshot when none was recorded", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Signup{}
+
+		_, err := s.Eligibility()
+		g.Expect(err).To(MatchError(ErrNoSnapshot))
+	})
+
+	t.Run("decodes the stored snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Signup{EligibilitySnapshot: sql.NullString{
+			String: `{"membership_status":"active","branch_of_service":"Navy","prior_hunts":2,` +
+				`"last_selected_at":"2026-03-01T00:00:00Z","attested_qualifiers":["Hunter safety card"]}`,
+			Valid: true,
+		}}
+
+		e, err := s.Eligibility()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(e.MembershipStatus).To(Equal(MembershipActive))
+		g.Expect(e.BranchOfService).To(Equal("Navy"))
+		g.Expect(e.PriorHunts).To(Equal(2))
+		g.Expect(*e.LastSelectedAt).To(BeTemporally("==", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+		g.Expect(e.AttestedQualifiers).To(ConsistOf("Hunter safety card"))
+	})
+
+	t.Run("returns an error for malformed JSON", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &Signup{EligibilitySnapshot: sql.NullString{String: "{", Valid: true}}
+
+		_, err := s.Eligibility()
+		g.Expect(err).To(HaveOccurred())
+	})
+}
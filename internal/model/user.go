@@ -17,6 +17,20 @@ const (
 	RoleAdmin  Role = "admin"
 )
 
+// roleRank orders roles from least to most privileged.
+var roleRank = map[Role]int{
+	RoleMember: 1,
+	RoleStaff:  2,
+	RoleAdmin:  3,
+}
+
+// AtLeast returns true if r grants at least the privileges of min.
+// Unknown roles grant nothing.
+func (r Role) AtLeast(min Role) bool {
+	rank, ok := roleRank[r]
+	return ok && rank >= roleRank[min]
+}
+
 // MembershipStatus represents a user's account status.
 type MembershipStatus string
 
@@ -27,19 +41,62 @@ const (
 	MembershipSuspended MembershipStatus = "suspended"
 )
 
+// ContactPreference is how a member prefers to be reached.
+type ContactPreference string
+
+const (
+	ContactByEmail ContactPreference = "email"
+	ContactByPhone ContactPreference = "phone"
+	ContactByText  ContactPreference = "text"
+)
+
+// NeedsPhone returns true if the preference requires a phone number.
+func (p ContactPreference) NeedsPhone() bool {
+	return p == ContactByPhone || p == ContactByText
+}
+
+// BranchGoldStarFamily is the branch recorded for Gold Star family members,
+// who join through a fallen service member rather than their own service.
+const BranchGoldStarFamily = "Gold Star Family"
+
+// BranchesOfService returns the accepted values for User.BranchOfService.
+func BranchesOfService() []string {
+	return []string{
+		"Army",
+		"Marine Corps",
+		"Navy",
+		"Air Force",
+		"Space Force",
+		"Coast Guard",
+		"National Guard",
+		BranchGoldStarFamily,
+	}
+}
+
+// IsValidBranchOfService returns true if branch is one of BranchesOfService.
+func IsValidBranchOfService(branch string) bool {
+	for _, b := range BranchesOfService() {
+		if b == branch {
+			return true
+		}
+	}
+	return false
+}
+
 // User represents an authenticated user in the system.
 type User struct {
-	ID               uuid.UUID
-	Email            string
-	Name             string
-	Phone            sql.NullString
-	BranchOfService  string
-	Role             Role
-	MembershipStatus MembershipStatus
-	FacebookID       sql.NullString
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
-	DeletedAt        sql.NullTime
+	ID                uuid.UUID
+	Email             string
+	Name              string
+	Phone             sql.NullString
+	BranchOfService   string
+	ContactPreference ContactPreference
+	Role              Role
+	MembershipStatus  MembershipStatus
+	FacebookID        sql.NullString
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         sql.NullTime
 }
 
 // IsDeleted returns true if the user has been soft-deleted.
@@ -8,6 +8,37 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+func TestRole_AtLeast(t *testing.T) {
+	t.Run("admin satisfies every role", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(RoleAdmin.AtLeast(RoleMember)).To(BeTrue())
+		g.Expect(RoleAdmin.AtLeast(RoleStaff)).To(BeTrue())
+		g.Expect(RoleAdmin.AtLeast(RoleAdmin)).To(BeTrue())
+	})
+
+	t.Run("staff satisfies member and staff only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(RoleStaff.AtLeast(RoleMember)).To(BeTrue())
+		g.Expect(RoleStaff.AtLeast(RoleStaff)).To(BeTrue())
+		g.Expect(RoleStaff.AtLeast(RoleAdmin)).To(BeFalse())
+	})
+
+	t.Run("member satisfies member only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(RoleMember.AtLeast(RoleMember)).To(BeTrue())
+		g.Expect(RoleMember.AtLeast(RoleStaff)).To(BeFalse())
+	})
+
+	t.Run("unknown role satisfies nothing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Role("guest").AtLeast(RoleMember)).To(BeFalse())
+	})
+}
+
 func TestUser_IsDeleted(t *testing.T) {
 	t.Run("returns false when DeletedAt is null", func(t *testing.T) {
 		g := NewWithT(t)
@@ -100,3 +131,34 @@ func TestUser_IsAccountComplete(t *testing.T) {
 		g.Expect(u.IsAccountComplete()).To(BeFalse())
 	})
 }
+
+func TestContactPreference_NeedsPhone(t *testing.T) {
+	t.Run("email does not need a phone", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ContactByEmail.NeedsPhone()).To(BeFalse())
+	})
+
+	t.Run("phone and text need a phone", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ContactByPhone.NeedsPhone()).To(BeTrue())
+		g.Expect(ContactByText.NeedsPhone()).To(BeTrue())
+	})
+}
+
+func TestIsValidBranchOfService(t *testing.T) {
+	t.Run("accepts listed branches including Gold Star family", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(IsValidBranchOfService("Army")).To(BeTrue())
+		g.Expect(IsValidBranchOfService(BranchGoldStarFamily)).To(BeTrue())
+	})
+
+	t.Run("rejects unknown branches", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(IsValidBranchOfService("")).To(BeFalse())
+		g.Expect(IsValidBranchOfService("army")).To(BeFalse())
+	})
+}
@@ -0,0 +1,104 @@
+// Package notify emails people about things that happen in the app. Its
+// Mailer implements the contact, membership, hunt and confirmation services'
+// notifiers.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/brian-abo/tfo-webapp/internal/contact"
+	"github.com/brian-abo/tfo-webapp/internal/mail"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/web/emails"
+	"github.com/brian-abo/tfo-webapp/web/features/confirmation"
+	"github.com/brian-abo/tfo-webapp/web/features/contactadmin"
+	"github.com/brian-abo/tfo-webapp/web/features/hunts"
+)
+
+// Mailer sends notifications as email.
+type Mailer struct {
+	sender  mail.Sender
+	siteURL string
+}
+
+// NewMailer creates a Mailer. siteURL is the public base URL links in
+// messages point to.
+func NewMailer(sender mail.Sender, siteURL string) *Mailer {
+	return &Mailer{sender: sender, siteURL: siteURL}
+}
+
+// MessageReceived forwards a contact form message to its region's leader.
+// Replies go to the visitor who sent it.
+func (m *Mailer) MessageReceived(ctx context.Context, leader contact.Leader, sub model.ContactSubmission) error {
+	p := emails.ContactMessageProps{
+		SiteURL:     m.siteURL,
+		LeaderName:  leader.Name,
+		SenderName:  sub.Name,
+		SenderEmail: sub.Email,
+		Message:     sub.Message,
+		InboxURL:    m.siteURL + contactadmin.DetailURL(sub.ID),
+	}
+	msg, err := mail.Compose(ctx, []string{leader.Email}, p.Subject(), emails.ContactMessage(p), emails.ContactMessageText(p))
+	if err != nil {
+		return err
+	}
+	msg.ReplyTo = sub.Email
+	return m.sender.Send(ctx, msg)
+}
+
+// MembershipDecided tells a member their membership status changed.
+func (m *Mailer) MembershipDecided(ctx context.Context, member model.User, d model.MembershipDecision) error {
+	p := emails.MembershipDecisionProps{SiteURL: m.siteURL, Name: member.Name, Status: d.ToStatus, Reason: d.Reason}
+	msg, err := mail.Compose(ctx, []string{member.Email}, p.Subject(), emails.MembershipDecision(p), emails.MembershipDecisionText(p))
+	if err != nil {
+		return err
+	}
+	return m.sender.Send(ctx, msg)
+}
+
+// HuntCancelled emails each entrant separately, so they don't see each
+// other's addresses. Every entrant is tried; failures are returned
+// together.
+func (m *Mailer) HuntCancelled(ctx context.Context, hunt model.Hunt, entrants []model.User, reason string) error {
+	var errs []error
+	for _, u := range entrants {
+		p := emails.HuntCancelledProps{
+			SiteURL:   m.siteURL,
+			Name:      u.Name,
+			HuntTitle: hunt.Title,
+			HuntDate:  hunt.HuntDate,
+			Reason:    reason,
+			HuntURL:   m.siteURL + hunts.DetailURL(hunt.ID),
+		}
+		msg, err := mail.Compose(ctx, []string{u.Email}, p.Subject(), emails.HuntCancelled(p), emails.HuntCancelledText(p))
+		if err == nil {
+			err = m.sender.Send(ctx, msg)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("emailing %s: %w", u.Email, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PlaceOffered tells a member they've been offered a place on a hunt,
+// drawn in its lottery or promoted from the alternates, and asks them to
+// confirm by the deadline.
+func (m *Mailer) PlaceOffered(ctx context.Context, member model.User, hunt model.Hunt, c model.Confirmation, promoted bool) error {
+	p := emails.PlaceOfferedProps{
+		SiteURL:    m.siteURL,
+		Name:       member.Name,
+		HuntTitle:  hunt.Title,
+		HuntDate:   hunt.HuntDate,
+		Deadline:   c.Deadline,
+		Promoted:   promoted,
+		ConfirmURL: m.siteURL + confirmation.URL(c.ID),
+	}
+	msg, err := mail.Compose(ctx, []string{member.Email}, p.Subject(), emails.PlaceOffered(p), emails.PlaceOfferedText(p))
+	if err != nil {
+		return err
+	}
+	return m.sender.Send(ctx, msg)
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/confirmation"
+	"github.com/brian-abo/tfo-webapp/internal/contact"
+	"github.com/brian-abo/tfo-webapp/internal/hunt"
+	"github.com/brian-abo/tfo-webapp/internal/mail"
+	"github.com/brian-abo/tfo-webapp/internal/membership"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestMailer(t *testing.T) {
+	t.Run("implements the service notifiers", func(t *testing.T) {
+		var m *Mailer
+		var _ contact.Notifier = m
+		var _ membership.Notifier = m
+		var _ hunt.Notifier = m
+		var _ confirmation.Notifier = m
+	})
+
+	t.Run("forwards contact messages to the leader with replies to the visitor", func(t *testing.T) {
+		g := NewWithT(t)
+		sent := &mail.Recorder{}
+		m := NewMailer(sent, "https://example.org")
+		sub := model.ContactSubmission{ID: uuid.New(), Name: "Jane Doe", Email: "jane@example.com", Message: "Any hunts in Iowa?"}
+
+		err := m.MessageReceived(context.Background(), contact.Leader{RegionID: "midwest", Name: "James Wilson", Email: "midwest@example.org"}, sub)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sent.Sent()).To(HaveLen(1))
+		msg := sent.Sent()[0]
+		g.Expect(msg.To).To(Equal([]string{"midwest@example.org"}))
+		g.Expect(msg.ReplyTo).To(Equal("jane@example.com"))
+		g.Expect(msg.Subject).To(Equal("New message from Jane Doe"))
+		g.Expect(msg.Text).To(ContainSubstring("> Any hunts in Iowa?"))
+		g.Expect(msg.Text).To(ContainSubstring("https://example.org/admin/contact/" + sub.ID.String()))
+	})
+
+	t.Run("tells members about membership decisions", func(t *testing.T) {
+		g := NewWithT(t)
+		sent := &mail.Recorder{}
+		m := NewMailer(sent, "https://example.org")
+
+		err := m.MembershipDecided(context.Background(),
+			model.User{Name: "Jane", Email: "jane@example.com"},
+			model.MembershipDecision{ToStatus: model.MembershipActive},
+		)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sent.Sent()).To(HaveLen(1))
+		g.Expect(sent.Sent()[0].To).To(Equal([]string{"jane@example.com"}))
+		g.Expect(sent.Sent()[0].Subject).To(Equal("Your membership is active"))
+	})
+
+	t.Run("offers selected members their place with a link to confirm", func(t *testing.T) {
+		g := NewWithT(t)
+		sent := &mail.Recorder{}
+		m := NewMailer(sent, "https://example.org")
+		h := model.Hunt{ID: uuid.New(), Title: "Iowa Whitetail", HuntDate: time.Date(2030, 11, 2, 6, 0, 0, 0, time.UTC)}
+		c := model.Confirmation{ID: uuid.New(), Deadline: time.Date(2030, 10, 4, 17, 0, 0, 0, time.UTC)}
+
+		err := m.PlaceOffered(context.Background(), model.User{Name: "Jane", Email: "jane@example.com"}, h, c, true)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sent.Sent()).To(HaveLen(1))
+		msg := sent.Sent()[0]
+		g.Expect(msg.To).To(Equal([]string{"jane@example.com"}))
+		g.Expect(msg.Subject).To(Equal("A place opened up on Iowa Whitetail"))
+		g.Expect(msg.Text).To(ContainSubstring("https://example.org/confirmations/" + c.ID.String()))
+	})
+
+	t.Run("emails each entrant of a cancelled hunt separately", func(t *testing.T) {
+		g := NewWithT(t)
+		sent := &mail.Recorder{}
+		m := NewMailer(sent, "https://example.org")
+		h := model.Hunt{ID: uuid.New(), Title: "Iowa Whitetail", HuntDate: time.Date(2030, 11, 2, 6, 0, 0, 0, time.UTC)}
+
+		err := m.HuntCancelled(context.Background(), h, []model.User{
+			{Name: "Jane", Email: "jane@example.com"},
+			{Name: "Joe", Email: "joe@example.com"},
+		}, "Flooding")
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sent.Sent()).To(HaveLen(2))
+		g.Expect(sent.Sent()[0].To).To(Equal([]string{"jane@example.com"}))
+		g.Expect(sent.Sent()[1].To).To(Equal([]string{"joe@example.com"}))
+		g.Expect(sent.Sent()[1].Text).To(ContainSubstring("Reason: Flooding"))
+	})
+
+	t.Run("reports every entrant it couldn't email", func(t *testing.T) {
+		g := NewWithT(t)
+		m := NewMailer(&mail.Recorder{Err: errors.New("relay down")}, "https://example.org")
+
+		err := m.HuntCancelled(context.Background(), model.Hunt{Title: "Iowa Whitetail"}, []model.User{
+			{Email: "jane@example.com"},
+			{Email: "joe@example.com"},
+		}, "Flooding")
+
+		g.Expect(err).To(MatchError(ContainSubstring("emailing jane@example.com: relay down")))
+		g.Expect(err).To(MatchError(ContainSubstring("emailing joe@example.com: relay down")))
+	})
+}
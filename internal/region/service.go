@@ -0,0 +1,98 @@
+// Package region implements staff editing of regions: the states each
+// covers and the leader visitors in it are put in touch with.
+package region
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// ErrRegionExists is returned when creating a region whose ID is taken.
+var ErrRegionExists = repository.ErrRegionExists
+
+// Service saves regions with their states and leaders.
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates a region Service.
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// Create adds a region covering r.States, taking them from any region
+// that had them. leader is nil for a region without one.
+func (s *Service) Create(ctx context.Context, r model.Region, leader *model.RegionalLeader) (model.Region, error) {
+	saved, err := s.save(ctx, r, leader, (*repository.RegionRepository).Create)
+	if err != nil {
+		return model.Region{}, fmt.Errorf("creating region: %w", err)
+	}
+	return saved, nil
+}
+
+// Update saves changes to a region, replacing its states and leader.
+func (s *Service) Update(ctx context.Context, r model.Region, leader *model.RegionalLeader) (model.Region, error) {
+	saved, err := s.save(ctx, r, leader, (*repository.RegionRepository).Update)
+	if err != nil {
+		return model.Region{}, fmt.Errorf("updating region: %w", err)
+	}
+	return saved, nil
+}
+
+// save writes the region with write, then its states and leader, in one
+// transaction.
+func (s *Service) save(
+	ctx context.Context,
+	r model.Region,
+	leader *model.RegionalLeader,
+	write func(*repository.RegionRepository, context.Context, model.Region) (model.Region, error),
+) (model.Region, error) {
+	var saved model.Region
+	err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		regions := repository.NewRegionRepository(tx)
+		if _, err := write(regions, ctx, r); err != nil {
+			return err
+		}
+		if err := regions.SetStates(ctx, r.ID, normalizeStates(r.States)); err != nil {
+			return err
+		}
+		if leader == nil {
+			if err := regions.RemoveLeader(ctx, r.ID); err != nil {
+				return err
+			}
+		} else {
+			l := *leader
+			l.RegionID = r.ID
+			if _, err := regions.SaveLeader(ctx, l); err != nil {
+				return err
+			}
+		}
+		var err error
+		saved, err = regions.GetByID(ctx, r.ID)
+		return err
+	})
+	return saved, err
+}
+
+// normalizeStates upper-cases state codes, dropping blanks and
+// duplicates, and sorts them.
+func normalizeStates(states []string) []string {
+	seen := make(map[string]bool, len(states))
+	out := make([]string, 0, len(states))
+	for _, s := range states {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,27 @@
+package region
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNormalizeStates(t *testing.T) {
+	t.Run("upper-cases and sorts state codes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(normalizeStates([]string{"tx", " OK ", "AR"})).To(Equal([]string{"AR", "OK", "TX"}))
+	})
+
+	t.Run("drops blanks and duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(normalizeStates([]string{"TX", "", "tx", "  "})).To(Equal([]string{"TX"}))
+	})
+
+	t.Run("returns an empty list for no states", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(normalizeStates(nil)).To(BeEmpty())
+	})
+}
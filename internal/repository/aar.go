@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// ErrAARExists is returned when a hunt already has an after-action report.
+var ErrAARExists = errors.New("hunt already has an after-action report")
+
+const aarColumns = `id, hunt_id, description, image_urls, created_by_id, created_at, updated_at`
+
+func scanAAR(row rowScanner) (model.HuntAfterActionReport, error) {
+	var (
+		a      model.HuntAfterActionReport
+		images stringList
+	)
+	err := row.Scan(&a.ID, &a.HuntID, &a.Description, &images, &a.CreatedByID, &a.CreatedAt, &a.UpdatedAt)
+	a.ImageURLs = images
+	return a, err
+}
+
+// AARRepository handles persistence of after-action reports and their
+// tagged participants.
+type AARRepository struct {
+	db DBTX
+}
+
+// NewAARRepository creates an AARRepository backed by the given DBTX.
+func NewAARRepository(db DBTX) *AARRepository {
+	return &AARRepository{db: db}
+}
+
+// getOne runs a single-report query, mapping no rows to ErrNotFound.
+func (r *AARRepository) getOne(ctx context.Context, op, query string, args ...any) (model.HuntAfterActionReport, error) {
+	a, err := scanAAR(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.HuntAfterActionReport{}, ErrNotFound
+	}
+	if isUniqueViolation(err, "aars_hunt_unique") {
+		return model.HuntAfterActionReport{}, ErrAARExists
+	}
+	if err != nil {
+		return model.HuntAfterActionReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return a, nil
+}
+
+// Create inserts a report for a.HuntID. Returns ErrAARExists if the hunt
+// already has one.
+func (r *AARRepository) Create(ctx context.Context, a model.HuntAfterActionReport) (model.HuntAfterActionReport, error) {
+	return r.getOne(ctx, "inserting after-action report",
+		`INSERT INTO hunt_after_action_reports (hunt_id, description, image_urls, created_by_id)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+aarColumns,
+		a.HuntID, a.Description, stringList(a.ImageURLs), a.CreatedByID,
+	)
+}
+
+// Update saves a report's narrative and images. Returns ErrNotFound if no
+// report with a.ID belongs to a.HuntID.
+func (r *AARRepository) Update(ctx context.Context, a model.HuntAfterActionReport) (model.HuntAfterActionReport, error) {
+	return r.getOne(ctx, "updating after-action report",
+		`UPDATE hunt_after_action_reports
+		 SET description = $3, image_urls = $4, updated_at = NOW()
+		 WHERE id = $1 AND hunt_id = $2
+		 RETURNING `+aarColumns,
+		a.ID, a.HuntID, a.Description, stringList(a.ImageURLs),
+	)
+}
+
+// GetByHunt returns the report for huntID.
+func (r *AARRepository) GetByHunt(ctx context.Context, huntID uuid.UUID) (model.HuntAfterActionReport, error) {
+	return r.getOne(ctx, "getting after-action report",
+		`SELECT `+aarColumns+` FROM hunt_after_action_reports WHERE hunt_id = $1`,
+		huntID,
+	)
+}
+
+// SetParticipants replaces the users tagged in a report.
+func (r *AARRepository) SetParticipants(ctx context.Context, aarID uuid.UUID, userIDs []uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM aar_participants WHERE aar_id = $1`,
+		aarID,
+	); err != nil {
+		return fmt.Errorf("clearing after-action report participants: %w", err)
+	}
+	for _, id := range userIDs {
+		if _, err := r.db.ExecContext(ctx,
+			`INSERT INTO aar_participants (aar_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			aarID, id,
+		); err != nil {
+			return fmt.Errorf("tagging after-action report participant: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListParticipants returns the users tagged in a report, ordered by name.
+// Soft-deleted users are excluded.
+func (r *AARRepository) ListParticipants(ctx context.Context, aarID uuid.UUID) ([]model.User, error) {
+	return listUsers(ctx, r.db, "listing after-action report participants",
+		`SELECT `+prefixColumns("u", userColumns)+`
+		 FROM users u
+		 JOIN aar_participants p ON p.user_id = u.id
+		 WHERE p.aar_id = $1 AND u.deleted_at IS NULL
+		 ORDER BY u.name, u.id`,
+		aarID,
+	)
+}
@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestAARRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewAARRepository(tx)
+		users := repository.NewUserRepository(tx)
+		staff := createTestUser(t, users, "staff@aar.test", "Staff User")
+		hunter := createTestUser(t, users, "hunter@aar.test", "Hunter User")
+		h := createTestHunt(t, repository.NewHuntRepository(tx), testHunt("AAR Hunt", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+
+		s, err := repository.NewSignupRepository(tx).Create(t.Context(), hunter.ID, h.ID, model.EligibilitySnapshot{})
+		if err != nil {
+			t.Fatalf("creating signup: %v", err)
+		}
+		drawnAt := time.Date(2030, 10, 1, 0, 0, 0, 0, time.UTC)
+		res, err := repository.NewLotteryResultRepository(tx).Insert(t.Context(), model.LotteryResult{
+			HuntID: h.ID, SignupID: s.ID, Position: 1, AuditSeed: 1, AlgorithmVersion: "test", DrawnAt: drawnAt,
+		})
+		if err != nil {
+			t.Fatalf("creating lottery result: %v", err)
+		}
+		confirmations := repository.NewConfirmationRepository(tx)
+		c, err := confirmations.Create(t.Context(), model.Confirmation{
+			LotteryResultID: res.ID, HuntID: h.ID, UserID: hunter.ID, Deadline: h.ConfirmationDeadline(drawnAt),
+		})
+		if err != nil {
+			t.Fatalf("creating confirmation: %v", err)
+		}
+
+		var report model.HuntAfterActionReport
+
+		t.Run("lists the confirmed roster", func(t *testing.T) {
+			g := NewWithT(t)
+			confirmed, err := users.ListConfirmed(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(confirmed).To(BeEmpty())
+
+			g.Expect(confirmations.Respond(t.Context(), c.ID, model.ConfirmationAccepted, "")).To(Succeed())
+			confirmed, err = users.ListConfirmed(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(confirmed).To(HaveLen(1))
+			g.Expect(confirmed[0].ID).To(Equal(hunter.ID))
+		})
+
+		t.Run("creates and reads a report", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.GetByHunt(t.Context(), h.ID)
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+
+			report, err = repo.Create(t.Context(), model.HuntAfterActionReport{
+				HuntID:      h.ID,
+				Description: "Clear skies.",
+				ImageURLs:   []string{"https://example.com/a.jpg"},
+				CreatedByID: staff.ID,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			got, err := repo.GetByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.ID).To(Equal(report.ID))
+			g.Expect(got.ImageURLs).To(Equal([]string{"https://example.com/a.jpg"}))
+		})
+
+		t.Run("updates the narrative and images", func(t *testing.T) {
+			g := NewWithT(t)
+			report.Description = "Clear skies, two bucks."
+			report.ImageURLs = nil
+
+			updated, err := repo.Update(t.Context(), report)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(updated.Description).To(Equal("Clear skies, two bucks."))
+			g.Expect(updated.ImageURLs).To(BeEmpty())
+
+			_, err = repo.Update(t.Context(), model.HuntAfterActionReport{ID: report.ID, HuntID: uuid.New()})
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+
+		t.Run("replaces tagged participants", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(repo.SetParticipants(t.Context(), report.ID, []uuid.UUID{hunter.ID, staff.ID})).To(Succeed())
+			g.Expect(repo.SetParticipants(t.Context(), report.ID, []uuid.UUID{hunter.ID})).To(Succeed())
+
+			participants, err := repo.ListParticipants(t.Context(), report.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(participants).To(HaveLen(1))
+			g.Expect(participants[0].Name).To(Equal("Hunter User"))
+		})
+
+		// A unique violation aborts the transaction, so this runs last.
+		t.Run("maps a second report to ErrAARExists", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.Create(t.Context(), model.HuntAfterActionReport{HuntID: h.ID, Description: "Again", CreatedByID: staff.ID})
+			g.Expect(err).To(MatchError(repository.ErrAARExists))
+		})
+	})
+}
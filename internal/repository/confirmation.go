@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+const confirmationColumns = `id, lottery_result_id, hunt_id, user_id, deadline, decision, reason,
+	responded_at, created_at`
+
+func scanConfirmation(row rowScanner) (model.Confirmation, error) {
+	var c model.Confirmation
+	err := row.Scan(
+		&c.ID, &c.LotteryResultID, &c.HuntID, &c.UserID, &c.Deadline, &c.Decision, &c.Reason,
+		&c.RespondedAt, &c.CreatedAt,
+	)
+	return c, err
+}
+
+// ConfirmationRepository handles persistence of selection confirmations.
+type ConfirmationRepository struct {
+	db DBTX
+}
+
+// NewConfirmationRepository creates a ConfirmationRepository backed by the
+// given DBTX.
+func NewConfirmationRepository(db DBTX) *ConfirmationRepository {
+	return &ConfirmationRepository{db: db}
+}
+
+// getOne runs a single-confirmation query, mapping no rows to ErrNotFound.
+func (r *ConfirmationRepository) getOne(ctx context.Context, op, query string, args ...any) (model.Confirmation, error) {
+	c, err := scanConfirmation(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Confirmation{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Confirmation{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return c, nil
+}
+
+// Create asks the member behind a lottery result to confirm their place
+// by c.Deadline.
+func (r *ConfirmationRepository) Create(ctx context.Context, c model.Confirmation) (model.Confirmation, error) {
+	return r.getOne(ctx, "inserting confirmation",
+		`INSERT INTO confirmations (lottery_result_id, hunt_id, user_id, deadline)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+confirmationColumns,
+		c.LotteryResultID, c.HuntID, c.UserID, c.Deadline,
+	)
+}
+
+// GetByID returns the confirmation with the given ID.
+func (r *ConfirmationRepository) GetByID(ctx context.Context, id uuid.UUID) (model.Confirmation, error) {
+	return r.getOne(ctx, "getting confirmation",
+		`SELECT `+confirmationColumns+` FROM confirmations WHERE id = $1`,
+		id,
+	)
+}
+
+// GetByIDForUpdate is GetByID that also locks the row until the enclosing
+// transaction ends, so a response can't race the deadline expiring it.
+func (r *ConfirmationRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.Confirmation, error) {
+	return r.getOne(ctx, "locking confirmation",
+		`SELECT `+confirmationColumns+` FROM confirmations WHERE id = $1 FOR UPDATE`,
+		id,
+	)
+}
+
+// GetByHuntAndUser returns userID's confirmation for huntID. Returns
+// ErrNotFound if the user hasn't been asked to confirm.
+func (r *ConfirmationRepository) GetByHuntAndUser(ctx context.Context, huntID, userID uuid.UUID) (model.Confirmation, error) {
+	return r.getOne(ctx, "getting confirmation",
+		`SELECT `+confirmationColumns+` FROM confirmations WHERE hunt_id = $1 AND user_id = $2`,
+		huntID, userID,
+	)
+}
+
+// Respond records a decision on a pending confirmation. Returns
+// ErrNotFound if the confirmation is missing or already answered.
+func (r *ConfirmationRepository) Respond(ctx context.Context, id uuid.UUID, decision model.ConfirmationDecision, reason string) error {
+	return execOne(ctx, r.db, "responding to confirmation",
+		`UPDATE confirmations SET decision = $2, reason = $3, responded_at = NOW()
+		 WHERE id = $1 AND decision = 'pending'`,
+		id, decision, reason,
+	)
+}
+
+// Withdraw releases an accepted place, recording it as declined. Returns
+// ErrNotFound if the confirmation is missing or wasn't accepted.
+func (r *ConfirmationRepository) Withdraw(ctx context.Context, id uuid.UUID, reason string) error {
+	return execOne(ctx, r.db, "withdrawing confirmation",
+		`UPDATE confirmations SET decision = 'declined', reason = $2, responded_at = NOW()
+		 WHERE id = $1 AND decision = 'accepted'`,
+		id, reason,
+	)
+}
+
+// ExpireOverdue marks a hunt's pending confirmations whose deadline is at
+// or before now as expired, returning how many it expired.
+func (r *ConfirmationRepository) ExpireOverdue(ctx context.Context, huntID uuid.UUID, now time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE confirmations SET decision = 'expired', responded_at = NOW()
+		 WHERE hunt_id = $1 AND decision = 'pending' AND deadline <= $2`,
+		huntID, now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("expiring confirmations: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("expiring confirmations: %w", err)
+	}
+	return int(n), nil
+}
+
+// ExpirePending marks all of a hunt's pending confirmations as expired,
+// as when the hunt is cancelled, returning how many it expired.
+func (r *ConfirmationRepository) ExpirePending(ctx context.Context, huntID uuid.UUID) (int, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE confirmations SET decision = 'expired', responded_at = NOW()
+		 WHERE hunt_id = $1 AND decision = 'pending'`,
+		huntID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("expiring pending confirmations: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("expiring pending confirmations: %w", err)
+	}
+	return int(n), nil
+}
+
+// ListHuntsWithOverdue returns the closed hunts that have pending
+// confirmations whose deadline is at or before now.
+func (r *ConfirmationRepository) ListHuntsWithOverdue(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT DISTINCT c.hunt_id FROM confirmations c
+		 JOIN hunts h ON h.id = c.hunt_id
+		 WHERE c.decision = 'pending' AND c.deadline <= $1 AND h.status = $2
+		 ORDER BY c.hunt_id`,
+		now, model.HuntStatusClosed,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing hunts with overdue confirmations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning hunt ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating hunt IDs: %w", err)
+	}
+	return ids, nil
+}
+
+// CountHoldingByHunt returns how many of a hunt's confirmations still hold
+// a place: those pending or accepted.
+func (r *ConfirmationRepository) CountHoldingByHunt(ctx context.Context, huntID uuid.UUID) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM confirmations WHERE hunt_id = $1 AND decision IN ('pending', 'accepted')`,
+		huntID,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting confirmations: %w", err)
+	}
+	return n, nil
+}
+
+// ListByHunt returns a hunt's confirmations, oldest first.
+func (r *ConfirmationRepository) ListByHunt(ctx context.Context, huntID uuid.UUID) ([]model.Confirmation, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+confirmationColumns+`
+		 FROM confirmations
+		 WHERE hunt_id = $1
+		 ORDER BY created_at, id`,
+		huntID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing confirmations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var confirmations []model.Confirmation
+	for rows.Next() {
+		c, err := scanConfirmation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning confirmation: %w", err)
+		}
+		confirmations = append(confirmations, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating confirmations: %w", err)
+	}
+	return confirmations, nil
+}
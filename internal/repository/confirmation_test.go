@@ -0,0 +1,138 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestConfirmationRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewConfirmationRepository(tx)
+		users := repository.NewUserRepository(tx)
+		h := createTestHunt(t, repository.NewHuntRepository(tx), testHunt("Confirm Hunt", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+		drawnAt := time.Date(2030, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		// offer draws a new entrant into position and asks them to confirm.
+		offer := func(email string, position int) (model.User, model.Confirmation) {
+			user := createTestUser(t, users, email, email)
+			s, err := repository.NewSignupRepository(tx).Create(t.Context(), user.ID, h.ID, model.EligibilitySnapshot{})
+			if err != nil {
+				t.Fatalf("creating signup: %v", err)
+			}
+			res, err := repository.NewLotteryResultRepository(tx).Insert(t.Context(), model.LotteryResult{
+				HuntID: h.ID, SignupID: s.ID, Position: position, AuditSeed: 1, AlgorithmVersion: "test", DrawnAt: drawnAt,
+			})
+			if err != nil {
+				t.Fatalf("creating lottery result: %v", err)
+			}
+			c, err := repo.Create(t.Context(), model.Confirmation{
+				LotteryResultID: res.ID, HuntID: h.ID, UserID: user.ID, Deadline: h.ConfirmationDeadline(drawnAt),
+			})
+			if err != nil {
+				t.Fatalf("creating confirmation: %v", err)
+			}
+			return user, c
+		}
+		user, created := offer("confirm@confirm.test", 1)
+
+		t.Run("starts pending", func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := repo.GetByHuntAndUser(t.Context(), h.ID, user.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.ID).To(Equal(created.ID))
+			g.Expect(got.IsPending()).To(BeTrue())
+			g.Expect(got.Deadline).To(BeTemporally("==", drawnAt.Add(72*time.Hour)))
+		})
+
+		t.Run("records one response", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(repo.Respond(t.Context(), created.ID, model.ConfirmationDeclined, "Work trip")).To(Succeed())
+			g.Expect(repo.Respond(t.Context(), created.ID, model.ConfirmationAccepted, "")).To(MatchError(repository.ErrNotFound))
+
+			got, err := repo.GetByIDForUpdate(t.Context(), created.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Decision).To(Equal(model.ConfirmationDeclined))
+			g.Expect(got.Reason).To(Equal("Work trip"))
+			g.Expect(got.RespondedAt.Valid).To(BeTrue())
+		})
+
+		t.Run("withdraws accepted places", func(t *testing.T) {
+			g := NewWithT(t)
+			_, c := offer("withdraw@confirm.test", 2)
+			g.Expect(repo.Withdraw(t.Context(), c.ID, "")).To(MatchError(repository.ErrNotFound))
+			g.Expect(repo.Respond(t.Context(), c.ID, model.ConfirmationAccepted, "")).To(Succeed())
+
+			n, err := repo.CountHoldingByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(n).To(Equal(1))
+
+			g.Expect(repo.Withdraw(t.Context(), c.ID, "Injured")).To(Succeed())
+			got, err := repo.GetByID(t.Context(), c.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Decision).To(Equal(model.ConfirmationDeclined))
+			g.Expect(got.Reason).To(Equal("Injured"))
+		})
+
+		t.Run("expires pending confirmations past their deadline", func(t *testing.T) {
+			g := NewWithT(t)
+			_, c := offer("expire@confirm.test", 3)
+
+			g.Expect(repository.NewHuntRepository(tx).SetStatus(t.Context(), h.ID, model.HuntStatusClosed)).To(Succeed())
+			overdue, err := repo.ListHuntsWithOverdue(t.Context(), c.Deadline.Add(-time.Minute))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(overdue).ToNot(ContainElement(h.ID))
+			overdue, err = repo.ListHuntsWithOverdue(t.Context(), c.Deadline)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(overdue).To(ContainElement(h.ID))
+
+			n, err := repo.ExpireOverdue(t.Context(), h.ID, c.Deadline.Add(-time.Minute))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(n).To(BeZero())
+
+			n, err = repo.ExpireOverdue(t.Context(), h.ID, c.Deadline)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(n).To(Equal(1))
+
+			got, err := repo.GetByID(t.Context(), c.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Decision).To(Equal(model.ConfirmationExpired))
+
+			held, err := repo.CountHoldingByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(held).To(BeZero())
+		})
+
+		t.Run("lists by hunt", func(t *testing.T) {
+			g := NewWithT(t)
+			list, err := repo.ListByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(list).To(HaveLen(3))
+		})
+
+		t.Run("expires every pending confirmation regardless of deadline", func(t *testing.T) {
+			g := NewWithT(t)
+			_, pending := offer("cancelled@confirm.test", 4)
+			_, accepted := offer("kept@confirm.test", 5)
+			g.Expect(repo.Respond(t.Context(), accepted.ID, model.ConfirmationAccepted, "")).To(Succeed())
+
+			n, err := repo.ExpirePending(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(n).To(Equal(1))
+
+			got, err := repo.GetByID(t.Context(), pending.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Decision).To(Equal(model.ConfirmationExpired))
+			got, err = repo.GetByID(t.Context(), accepted.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Decision).To(Equal(model.ConfirmationAccepted))
+		})
+	})
+}
@@ -2,12 +2,26 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
 
 	"github.com/brian-abo/tfo-webapp/internal/model"
 )
 
-// ContactRepository handles persistence of contact form submissions.
+const contactColumns = `id, name, email, message, region_id, status, assignee_id, created_at, updated_at`
+
+func scanContactSubmission(row rowScanner) (model.ContactSubmission, error) {
+	var s model.ContactSubmission
+	err := row.Scan(&s.ID, &s.Name, &s.Email, &s.Message, &s.RegionID, &s.Status, &s.AssigneeID, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+// ContactRepository handles persistence of contact form submissions and
+// the staff notes on them.
 type ContactRepository struct {
 	db DBTX
 }
@@ -17,45 +31,172 @@ func NewContactRepository(db DBTX) *ContactRepository {
 	return &ContactRepository{db: db}
 }
 
-// Insert stores a new contact form submission.
-func (r *ContactRepository) Insert(ctx context.Context, name, email, message string) (model.ContactSubmission, error) {
-	var s model.ContactSubmission
-	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO contact_submissions (name, email, message)
-		 VALUES ($1, $2, $3)
-		 RETURNING id, name, email, message, created_at`,
-		name, email, message,
-	).Scan(&s.ID, &s.Name, &s.Email, &s.Message, &s.CreatedAt)
+// ContactFilter narrows List results. Zero values match everything.
+type ContactFilter struct {
+	Status model.ContactStatus
+	// Assignee matches submissions assigned to the given user.
+	Assignee uuid.NullUUID
+	// Unassigned matches submissions nobody is handling yet.
+	Unassigned bool
+	// RegionID matches submissions sent to a region's leader.
+	RegionID string
+	// Search matches submissions whose name, email or message contain
+	// the words of a web-style full-text query, or whose email contains
+	// it as written.
+	Search string
+}
+
+// ContactNoteEntry is a staff note with its author's name, as shown in
+// the inbox.
+type ContactNoteEntry struct {
+	Note       model.ContactNote
+	AuthorName string
+}
+
+// getOne runs a single-submission query, mapping no rows to ErrNotFound.
+func (r *ContactRepository) getOne(ctx context.Context, op, query string, args ...any) (model.ContactSubmission, error) {
+	s, err := scanContactSubmission(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ContactSubmission{}, ErrNotFound
+	}
 	if err != nil {
-		return model.ContactSubmission{}, fmt.Errorf("inserting contact submission: %w", err)
+		return model.ContactSubmission{}, fmt.Errorf("%s: %w", op, err)
 	}
 	return s, nil
 }
 
-// List returns the most recent contact submissions, up to limit.
-func (r *ContactRepository) List(ctx context.Context, limit int) ([]model.ContactSubmission, error) {
+// Insert stores a new contact form submission.
+func (r *ContactRepository) Insert(ctx context.Context, s model.ContactSubmission) (model.ContactSubmission, error) {
+	return r.getOne(ctx, "inserting contact submission",
+		`INSERT INTO contact_submissions (name, email, message, region_id)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+contactColumns,
+		s.Name, s.Email, s.Message, s.RegionID,
+	)
+}
+
+// GetByID returns the submission with the given ID.
+func (r *ContactRepository) GetByID(ctx context.Context, id uuid.UUID) (model.ContactSubmission, error) {
+	return r.getOne(ctx, "getting contact submission",
+		`SELECT `+contactColumns+` FROM contact_submissions WHERE id = $1`,
+		id,
+	)
+}
+
+// UpdateTriage sets a submission's status and assignee. It doesn't check
+// the assignee: contact.Service.Triage does, returning
+// ErrAssigneeNotStaff, which the handler shows as a 422.
+func (r *ContactRepository) UpdateTriage(ctx context.Context, id uuid.UUID, status model.ContactStatus, assignee uuid.NullUUID) (model.ContactSubmission, error) {
+	return r.getOne(ctx, "updating contact submission",
+		`UPDATE contact_submissions SET status = $2, assignee_id = $3, updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+contactColumns,
+		id, status, assignee,
+	)
+}
+
+// List returns submissions matching filter, newest first, along with the
+// total number of matches for pagination.
+func (r *ContactRepository) List(ctx context.Context, filter ContactFilter, page Page) ([]model.ContactSubmission, int, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Assignee.Valid {
+		args = append(args, filter.Assignee)
+		conds = append(conds, fmt.Sprintf("assignee_id = $%d", len(args)))
+	}
+	if filter.Unassigned {
+		conds = append(conds, "assignee_id IS NULL")
+	}
+	if filter.RegionID != "" {
+		args = append(args, filter.RegionID)
+		conds = append(conds, fmt.Sprintf("region_id = $%d", len(args)))
+	}
+	if filter.Search != "" {
+		// The full-text index sees an email address as a single token, so
+		// match addresses by substring as well.
+		args = append(args, filter.Search, "%"+filter.Search+"%")
+		conds = append(conds, fmt.Sprintf("(search @@ websearch_to_tsquery('english', $%d) OR email ILIKE $%d)", len(args)-1, len(args)))
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM contact_submissions`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting contact submissions: %w", err)
+	}
+
+	args = append(args, page.Limit, page.Offset)
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, name, email, message, created_at
-		 FROM contact_submissions
-		 ORDER BY created_at DESC
-		 LIMIT $1`,
-		limit,
+		`SELECT `+contactColumns+` FROM contact_submissions`+where+
+			fmt.Sprintf(` ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`, len(args)-1, len(args)),
+		args...,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("listing contact submissions: %w", err)
+		return nil, 0, fmt.Errorf("listing contact submissions: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
 	var submissions []model.ContactSubmission
 	for rows.Next() {
-		var s model.ContactSubmission
-		if err := rows.Scan(&s.ID, &s.Name, &s.Email, &s.Message, &s.CreatedAt); err != nil {
-			return nil, fmt.Errorf("scanning contact submission: %w", err)
+		s, err := scanContactSubmission(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning contact submission: %w", err)
 		}
 		submissions = append(submissions, s)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterating contact submissions: %w", err)
+		return nil, 0, fmt.Errorf("iterating contact submissions: %w", err)
+	}
+	return submissions, total, nil
+}
+
+// InsertNote adds an internal note to a submission.
+func (r *ContactRepository) InsertNote(ctx context.Context, n model.ContactNote) (model.ContactNote, error) {
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO contact_notes (submission_id, author_id, body)
+		 VALUES ($1, $2, $3)
+		 RETURNING id, submission_id, author_id, body, created_at`,
+		n.SubmissionID, n.AuthorID, n.Body,
+	).Scan(&n.ID, &n.SubmissionID, &n.AuthorID, &n.Body, &n.CreatedAt)
+	if err != nil {
+		return model.ContactNote{}, fmt.Errorf("inserting contact note: %w", err)
+	}
+	return n, nil
+}
+
+// ListNotes returns a submission's notes, oldest first.
+func (r *ContactRepository) ListNotes(ctx context.Context, submissionID uuid.UUID) ([]ContactNoteEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT n.id, n.submission_id, n.author_id, n.body, n.created_at, u.name
+		 FROM contact_notes n
+		 JOIN users u ON u.id = n.author_id
+		 WHERE n.submission_id = $1
+		 ORDER BY n.created_at, n.id`,
+		submissionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing contact notes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var notes []ContactNoteEntry
+	for rows.Next() {
+		var e ContactNoteEntry
+		if err := rows.Scan(&e.Note.ID, &e.Note.SubmissionID, &e.Note.AuthorID, &e.Note.Body, &e.Note.CreatedAt, &e.AuthorName); err != nil {
+			return nil, fmt.Errorf("scanning contact note: %w", err)
+		}
+		notes = append(notes, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating contact notes: %w", err)
 	}
-	return submissions, nil
+	return notes, nil
 }
@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 
+	"github.com/brian-abo/tfo-webapp/internal/model"
 	"github.com/brian-abo/tfo-webapp/internal/repository"
 )
 
@@ -16,12 +18,20 @@ func TestContactRepository_Insert(t *testing.T) {
 		g := NewWithT(t)
 		repo := repository.NewContactRepository(tx)
 
-		sub, err := repo.Insert(t.Context(), "Jane Doe", "jane@example.com", "Hello there")
+		sub, err := repo.Insert(t.Context(), model.ContactSubmission{
+			Name:     "Jane Doe",
+			Email:    "jane@example.com",
+			Message:  "Hello there",
+			RegionID: sql.NullString{String: "midwest", Valid: true},
+		})
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(sub.ID.String()).ToNot(BeEmpty())
 		g.Expect(sub.Name).To(Equal("Jane Doe"))
 		g.Expect(sub.Email).To(Equal("jane@example.com"))
 		g.Expect(sub.Message).To(Equal("Hello there"))
+		g.Expect(sub.RegionID.String).To(Equal("midwest"))
+		g.Expect(sub.Status).To(Equal(model.ContactNew))
+		g.Expect(sub.AssigneeID.Valid).To(BeFalse())
 		g.Expect(sub.CreatedAt.IsZero()).To(BeFalse())
 	})
 }
@@ -32,30 +42,116 @@ func TestContactRepository_List(t *testing.T) {
 	withTestTx(t, db, func(tx *sql.Tx) {
 		g := NewWithT(t)
 		repo := repository.NewContactRepository(tx)
+		staff := createTestUser(t, repository.NewUserRepository(tx), "staff@contactlist.test", "Staff")
 
+		subs := map[string]model.ContactSubmission{}
 		for _, name := range []string{"Alice", "Bob", "Charlie"} {
-			_, err := repo.Insert(t.Context(), name, name+"@example.com", "Message "+name)
+			sub, err := repo.Insert(t.Context(), model.ContactSubmission{
+				Name:     name,
+				Email:    name + "@contactlist.test",
+				Message:  "Question about elk tags from " + name,
+				RegionID: sql.NullString{String: "southern", Valid: name == "Alice"},
+			})
 			g.Expect(err).ToNot(HaveOccurred())
+			subs[name] = sub
 		}
+		_, err := repo.UpdateTriage(t.Context(), subs["Bob"].ID, model.ContactInProgress, uuid.NullUUID{UUID: staff.ID, Valid: true})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		search := repository.ContactFilter{Search: "elk"}
+
+		t.Run("searches names, emails and messages", func(t *testing.T) {
+			g := NewWithT(t)
+			found, total, err := repo.List(t.Context(), repository.ContactFilter{Search: "elk charlie"}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(1))
+			g.Expect(found[0].ID).To(Equal(subs["Charlie"].ID))
+		})
 
-		t.Run("respects limit", func(t *testing.T) {
+		t.Run("paginates", func(t *testing.T) {
 			g := NewWithT(t)
-			subs, err := repo.List(t.Context(), 2)
+			found, total, err := repo.List(t.Context(), search, repository.Page{Limit: 2})
 			g.Expect(err).ToNot(HaveOccurred())
-			g.Expect(subs).To(HaveLen(2))
+			g.Expect(total).To(BeNumerically(">=", 3))
+			g.Expect(found).To(HaveLen(2))
 		})
 
-		t.Run("returns all when limit is high", func(t *testing.T) {
+		t.Run("filters by status and assignee", func(t *testing.T) {
 			g := NewWithT(t)
-			all, err := repo.List(t.Context(), 100)
+			filter := search
+			filter.Status = model.ContactInProgress
+			found, _, err := repo.List(t.Context(), filter, repository.Page{Limit: 10})
 			g.Expect(err).ToNot(HaveOccurred())
-			g.Expect(all).To(HaveLen(3))
+			g.Expect(found).To(HaveLen(1))
+			g.Expect(found[0].ID).To(Equal(subs["Bob"].ID))
 
-			names := make([]string, len(all))
-			for i, s := range all {
-				names[i] = s.Name
+			filter = repository.ContactFilter{Assignee: uuid.NullUUID{UUID: staff.ID, Valid: true}}
+			found, _, err = repo.List(t.Context(), filter, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(found).To(HaveLen(1))
+			g.Expect(found[0].ID).To(Equal(subs["Bob"].ID))
+
+			filter = repository.ContactFilter{Search: "contactlist", Unassigned: true}
+			_, total, err := repo.List(t.Context(), filter, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(2))
+		})
+
+		t.Run("filters by region", func(t *testing.T) {
+			g := NewWithT(t)
+			found, _, err := repo.List(t.Context(), repository.ContactFilter{RegionID: "southern"}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(found).To(HaveLen(1))
+			g.Expect(found[0].ID).To(Equal(subs["Alice"].ID))
+		})
+	})
+}
+
+func TestContactRepository_Triage(t *testing.T) {
+	db := testDB(t)
+
+	withTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewContactRepository(tx)
+		staff := createTestUser(t, repository.NewUserRepository(tx), "staff@contacttriage.test", "Staff Member")
+		sub, err := repo.Insert(t.Context(), model.ContactSubmission{Name: "Jane Doe", Email: "jane@example.com", Message: "Hello there"})
+		if err != nil {
+			t.Fatalf("inserting submission: %v", err)
+		}
+
+		t.Run("updates status and assignee", func(t *testing.T) {
+			g := NewWithT(t)
+			updated, err := repo.UpdateTriage(t.Context(), sub.ID, model.ContactResolved, uuid.NullUUID{UUID: staff.ID, Valid: true})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(updated.Status).To(Equal(model.ContactResolved))
+			g.Expect(updated.AssigneeID.UUID).To(Equal(staff.ID))
+
+			got, err := repo.GetByID(t.Context(), sub.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(updated))
+		})
+
+		t.Run("returns ErrNotFound for unknown submissions", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.UpdateTriage(t.Context(), uuid.New(), model.ContactSpam, uuid.NullUUID{})
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+
+			_, err = repo.GetByID(t.Context(), uuid.New())
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+
+		t.Run("adds and lists notes", func(t *testing.T) {
+			g := NewWithT(t)
+			for _, body := range []string{"Called back", "Sent tag info"} {
+				_, err := repo.InsertNote(t.Context(), model.ContactNote{SubmissionID: sub.ID, AuthorID: staff.ID, Body: body})
+				g.Expect(err).ToNot(HaveOccurred())
 			}
-			g.Expect(names).To(ContainElements("Alice", "Bob", "Charlie"))
+
+			notes, err := repo.ListNotes(t.Context(), sub.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(notes).To(HaveLen(2))
+			bodies := []string{notes[0].Note.Body, notes[1].Note.Body}
+			g.Expect(bodies).To(ConsistOf("Called back", "Sent tag info"))
+			g.Expect(notes[0].AuthorName).To(Equal("Staff Member"))
 		})
 	})
 }
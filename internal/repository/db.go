@@ -4,8 +4,18 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ErrNotFound is returned when a requested row does not exist.
+var ErrNotFound = errors.New("not found")
+
 // DBTX is an interface that abstracts database operations, allowing
 // repositories to work with both *sql.DB and *sql.Tx. This enables
 // transaction support without changing repository method signatures.
@@ -20,3 +30,115 @@ var (
 	_ DBTX = (*sql.DB)(nil)
 	_ DBTX = (*sql.Tx)(nil)
 )
+
+// WithTx runs fn inside a transaction on db, committing if fn returns nil
+// and rolling back otherwise.
+func WithTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// Page selects a window of a paginated list.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// execOne runs a statement expected to affect exactly one row, returning
+// ErrNotFound when it affects none. op describes the statement for errors.
+func execOne(ctx context.Context, db DBTX, op, query string, args ...any) error {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// uniqueViolation is the Postgres error code for unique_violation.
+const uniqueViolation = "23505"
+
+// isUniqueViolation returns true if err is a unique violation of the
+// named constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == constraint
+}
+
+// extraColumns is a rowScanner that also scans trailing columns into
+// extra, so a scanX function can be reused for joins that select more.
+type extraColumns struct {
+	row   rowScanner
+	extra []any
+}
+
+// Scan implements rowScanner.
+func (e extraColumns) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
+
+// prefixColumns qualifies each column in a comma-separated column list
+// with alias, for use in joins.
+func prefixColumns(alias, columns string) string {
+	cols := strings.Split(columns, ",")
+	for i, c := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// stringList adapts a []string to a JSONB array column. A nil slice is
+// stored as an empty array so NOT NULL DEFAULT '[]' columns stay uniform.
+type stringList []string
+
+// Value implements driver.Valuer.
+func (l stringList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(l))
+}
+
+// Scan implements sql.Scanner.
+func (l *stringList) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return fmt.Errorf("scanning string list: unsupported type %T", src)
+	}
+	var out []string
+	if err := json.Unmarshal(data, &out); err != nil {
+		return fmt.Errorf("scanning string list: %w", err)
+	}
+	*l = out
+	return nil
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// ErrHuntNotEditable is returned when updating a hunt whose signups have
+// closed.
+var ErrHuntNotEditable = errors.New("hunt can no longer be edited")
+
+const huntColumns = `id, title, description, location, state, image_urls, qualifiers, hunt_date,
+	signup_window_start, signup_window_end, primary_capacity, alternate_capacity,
+	lottery_algorithm, lottery_weights, confirmation_window_hours, status, auto_open, created_at, updated_at`
+
+func scanHunt(row rowScanner) (model.Hunt, error) {
+	var (
+		h      model.Hunt
+		images stringList
+	)
+	err := row.Scan(
+		&h.ID, &h.Title, &h.Description, &h.Location, &h.State, &images, &h.Qualifiers, &h.HuntDate,
+		&h.SignupWindowStart, &h.SignupWindowEnd, &h.PrimaryCapacity, &h.AlternateCapacity,
+		&h.LotteryAlgorithm, (*lotteryWeights)(&h.LotteryWeights), &h.ConfirmationWindowHours,
+		&h.Status, &h.AutoOpen, &h.CreatedAt, &h.UpdatedAt,
+	)
+	h.ImageURLs = images
+	return h, err
+}
+
+// lotteryWeights adapts model.LotteryWeights to a JSONB column.
+type lotteryWeights model.LotteryWeights
+
+// Value implements driver.Valuer.
+func (w lotteryWeights) Value() (driver.Value, error) {
+	return json.Marshal(model.LotteryWeights(w))
+}
+
+// Scan implements sql.Scanner.
+func (w *lotteryWeights) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("scanning lottery weights: unsupported type %T", src)
+	}
+	if err := json.Unmarshal(data, (*model.LotteryWeights)(w)); err != nil {
+		return fmt.Errorf("scanning lottery weights: %w", err)
+	}
+	return nil
+}
+
+// HuntRepository handles persistence of hunts.
+type HuntRepository struct {
+	db DBTX
+}
+
+// NewHuntRepository creates a HuntRepository backed by the given DBTX.
+func NewHuntRepository(db DBTX) *HuntRepository {
+	return &HuntRepository{db: db}
+}
+
+// HuntFilter narrows List results. Zero values match everything.
+type HuntFilter struct {
+	Status model.HuntStatus
+	// States matches hunts in any of the given state codes.
+	States []string
+	// From and To bound hunt_date inclusively.
+	From time.Time
+	To   time.Time
+}
+
+// getOne runs a single-hunt query, mapping no rows to ErrNotFound.
+func (r *HuntRepository) getOne(ctx context.Context, op, query string, args ...any) (model.Hunt, error) {
+	h, err := scanHunt(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Hunt{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Hunt{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return h, nil
+}
+
+// GetByID returns the hunt with the given ID.
+func (r *HuntRepository) GetByID(ctx context.Context, id uuid.UUID) (model.Hunt, error) {
+	return r.getOne(ctx, "getting hunt",
+		`SELECT `+huntColumns+` FROM hunts WHERE id = $1`,
+		id,
+	)
+}
+
+// GetByIDForUpdate is GetByID that also locks the row until the enclosing
+// transaction ends. Status changes and signups take this lock so a signup
+// can't slip in while a hunt is closing.
+func (r *HuntRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.Hunt, error) {
+	return r.getOne(ctx, "locking hunt",
+		`SELECT `+huntColumns+` FROM hunts WHERE id = $1 FOR UPDATE`,
+		id,
+	)
+}
+
+// Create inserts a hunt. An empty Status falls back to draft; an empty
+// LotteryAlgorithm and zero ConfirmationWindowHours fall back to
+// model.DefaultLotteryAlgorithm and model.DefaultConfirmationWindowHours.
+func (r *HuntRepository) Create(ctx context.Context, h model.Hunt) (model.Hunt, error) {
+	if h.Status == "" {
+		h.Status = model.HuntStatusDraft
+	}
+	if h.LotteryAlgorithm == "" {
+		h.LotteryAlgorithm = model.DefaultLotteryAlgorithm
+	}
+	if h.ConfirmationWindowHours == 0 {
+		h.ConfirmationWindowHours = model.DefaultConfirmationWindowHours
+	}
+	return r.getOne(ctx, "inserting hunt",
+		`INSERT INTO hunts (title, description, location, state, image_urls, qualifiers, hunt_date,
+			signup_window_start, signup_window_end, primary_capacity, alternate_capacity,
+			lottery_algorithm, lottery_weights, confirmation_window_hours, status, auto_open)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		 RETURNING `+huntColumns,
+		h.Title, h.Description, h.Location, h.State, stringList(h.ImageURLs), h.Qualifiers, h.HuntDate,
+		h.SignupWindowStart, h.SignupWindowEnd, h.PrimaryCapacity, h.AlternateCapacity,
+		h.LotteryAlgorithm, lotteryWeights(h.LotteryWeights), h.ConfirmationWindowHours, h.Status, h.AutoOpen,
+	)
+}
+
+// Update replaces a hunt's editable fields. Status is left unchanged.
+// Only drafts and open hunts can be updated; the status is checked in the
+// same statement, so a hunt closed since the caller loaded it returns
+// ErrHuntNotEditable rather than being changed.
+func (r *HuntRepository) Update(ctx context.Context, h model.Hunt) (model.Hunt, error) {
+	updated, err := r.getOne(ctx, "updating hunt",
+		`UPDATE hunts
+		 SET title = $2, description = $3, location = $4, state = $5, image_urls = $6, qualifiers = $7,
+			hunt_date = $8, signup_window_start = $9, signup_window_end = $10,
+			primary_capacity = $11, alternate_capacity = $12,
+			lottery_algorithm = COALESCE(NULLIF($13, ''), lottery_algorithm), lottery_weights = $14,
+			confirmation_window_hours = COALESCE(NULLIF($15, 0), confirmation_window_hours),
+			auto_open = $16, updated_at = NOW()
+		 WHERE id = $1 AND status IN ($17, $18)
+		 RETURNING `+huntColumns,
+		h.ID, h.Title, h.Description, h.Location, h.State, stringList(h.ImageURLs), h.Qualifiers,
+		h.HuntDate, h.SignupWindowStart, h.SignupWindowEnd,
+		h.PrimaryCapacity, h.AlternateCapacity,
+		h.LotteryAlgorithm, lotteryWeights(h.LotteryWeights), h.ConfirmationWindowHours, h.AutoOpen,
+		model.HuntStatusDraft, model.HuntStatusOpen,
+	)
+	if !errors.Is(err, ErrNotFound) {
+		return updated, err
+	}
+	if _, err := r.GetByID(ctx, h.ID); err != nil {
+		return model.Hunt{}, err
+	}
+	return model.Hunt{}, ErrHuntNotEditable
+}
+
+// SetStatus sets a hunt's status. Callers are responsible for checking
+// the transition is allowed; see hunt.Service.
+func (r *HuntRepository) SetStatus(ctx context.Context, id uuid.UUID, status model.HuntStatus) error {
+	return execOne(ctx, r.db, "setting hunt status",
+		`UPDATE hunts SET status = $2, updated_at = NOW() WHERE id = $1`,
+		id, status,
+	)
+}
+
+// List returns hunts matching filter, soonest first, along with the total
+// number of matches for pagination.
+func (r *HuntRepository) List(ctx context.Context, filter HuntFilter, page Page) ([]model.Hunt, int, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(filter.States) > 0 {
+		args = append(args, filter.States)
+		conds = append(conds, fmt.Sprintf("state = ANY($%d)", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, fmt.Sprintf("hunt_date >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, fmt.Sprintf("hunt_date <= $%d", len(args)))
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM hunts`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting hunts: %w", err)
+	}
+
+	args = append(args, page.Limit, page.Offset)
+	hunts, err := r.list(ctx,
+		`SELECT `+huntColumns+` FROM hunts`+where+
+			fmt.Sprintf(` ORDER BY hunt_date, id LIMIT $%d OFFSET $%d`, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hunts, total, nil
+}
+
+// ListUpcomingOpen returns open hunts taking place after now, soonest
+// first.
+func (r *HuntRepository) ListUpcomingOpen(ctx context.Context, now time.Time) ([]model.Hunt, error) {
+	return r.list(ctx,
+		`SELECT `+huntColumns+` FROM hunts
+		 WHERE status = $1 AND hunt_date > $2
+		 ORDER BY hunt_date, id`,
+		model.HuntStatusOpen, now,
+	)
+}
+
+// ListDueToOpen returns drafts scheduled to open whose signup window has
+// started by now, earliest window first.
+func (r *HuntRepository) ListDueToOpen(ctx context.Context, now time.Time) ([]model.Hunt, error) {
+	return r.list(ctx,
+		`SELECT `+huntColumns+` FROM hunts
+		 WHERE status = $1 AND auto_open AND signup_window_start <= $2
+		 ORDER BY signup_window_start, id`,
+		model.HuntStatusDraft, now,
+	)
+}
+
+// ListDueToClose returns open hunts whose signup window has ended by now,
+// earliest window first.
+func (r *HuntRepository) ListDueToClose(ctx context.Context, now time.Time) ([]model.Hunt, error) {
+	return r.list(ctx,
+		`SELECT `+huntColumns+` FROM hunts
+		 WHERE status = $1 AND signup_window_end <= $2
+		 ORDER BY signup_window_end, id`,
+		model.HuntStatusOpen, now,
+	)
+}
+
+func (r *HuntRepository) list(ctx context.Context, query string, args ...any) ([]model.Hunt, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing hunts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var hunts []model.Hunt
+	for rows.Next() {
+		h, err := scanHunt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning hunt: %w", err)
+		}
+		hunts = append(hunts, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating hunts: %w", err)
+	}
+	return hunts, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+const huntStatusChangeColumns = `id, hunt_id, from_status, to_status, reason, changed_by_id, changed_at`
+
+func scanHuntStatusChange(row rowScanner) (model.HuntStatusChange, error) {
+	var c model.HuntStatusChange
+	err := row.Scan(&c.ID, &c.HuntID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.ChangedByID, &c.ChangedAt)
+	return c, err
+}
+
+// HuntStatusChangeRepository handles persistence of hunt status history.
+type HuntStatusChangeRepository struct {
+	db DBTX
+}
+
+// NewHuntStatusChangeRepository creates a HuntStatusChangeRepository
+// backed by the given DBTX.
+func NewHuntStatusChangeRepository(db DBTX) *HuntStatusChangeRepository {
+	return &HuntStatusChangeRepository{db: db}
+}
+
+// Insert records a hunt status change.
+func (r *HuntStatusChangeRepository) Insert(ctx context.Context, c model.HuntStatusChange) (model.HuntStatusChange, error) {
+	created, err := scanHuntStatusChange(r.db.QueryRowContext(ctx,
+		`INSERT INTO hunt_status_changes (hunt_id, from_status, to_status, reason, changed_by_id)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+huntStatusChangeColumns,
+		c.HuntID, c.FromStatus, c.ToStatus, c.Reason, c.ChangedByID,
+	))
+	if err != nil {
+		return model.HuntStatusChange{}, fmt.Errorf("inserting hunt status change: %w", err)
+	}
+	return created, nil
+}
+
+// ListByHunt returns a hunt's status history, oldest first.
+func (r *HuntStatusChangeRepository) ListByHunt(ctx context.Context, huntID uuid.UUID) ([]model.HuntStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+huntStatusChangeColumns+`
+		 FROM hunt_status_changes
+		 WHERE hunt_id = $1
+		 ORDER BY changed_at, id`,
+		huntID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing hunt status changes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var changes []model.HuntStatusChange
+	for rows.Next() {
+		c, err := scanHuntStatusChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning hunt status change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating hunt status changes: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestHuntStatusChangeRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		hunts := repository.NewHuntRepository(tx)
+		repo := repository.NewHuntStatusChangeRepository(tx)
+
+		staff := createTestUser(t, repository.NewUserRepository(tx), "staff@huntstatus.test", "Staff")
+		h := createTestHunt(t, hunts, testHunt("History Hunt", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+
+		_, err := repo.Insert(t.Context(), model.HuntStatusChange{
+			HuntID:      h.ID,
+			FromStatus:  model.HuntStatusDraft,
+			ToStatus:    model.HuntStatusOpen,
+			ChangedByID: uuid.NullUUID{UUID: staff.ID, Valid: true},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = repo.Insert(t.Context(), model.HuntStatusChange{
+			HuntID:     h.ID,
+			FromStatus: model.HuntStatusOpen,
+			ToStatus:   model.HuntStatusClosed,
+			Reason:     "signup window ended",
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		changes, err := repo.ListByHunt(t.Context(), h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changes).To(HaveLen(2))
+		g.Expect(changes[0].ToStatus).To(Equal(model.HuntStatusOpen))
+		g.Expect(changes[0].ChangedByID.UUID).To(Equal(staff.ID))
+		g.Expect(changes[1].ChangedByID.Valid).To(BeFalse())
+	})
+}
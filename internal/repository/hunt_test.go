@@ -0,0 +1,262 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// testHunt returns a valid draft hunt taking place at date.
+func testHunt(title string, date time.Time) model.Hunt {
+	return model.Hunt{
+		Title:             title,
+		Description:       "A test hunt",
+		Location:          "Somewhere, TX",
+		State:             "TX",
+		HuntDate:          date,
+		SignupWindowStart: date.AddDate(0, -2, 0),
+		SignupWindowEnd:   date.AddDate(0, -1, 0),
+		PrimaryCapacity:   4,
+		AlternateCapacity: 2,
+	}
+}
+
+func createTestHunt(t *testing.T, repo *repository.HuntRepository, h model.Hunt) model.Hunt {
+	t.Helper()
+	created, err := repo.Create(t.Context(), h)
+	if err != nil {
+		t.Fatalf("creating hunt: %v", err)
+	}
+	return created
+}
+
+func TestHuntRepository_CreateAndGet(t *testing.T) {
+	db := testDB(t)
+	date := time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)
+
+	t.Run("round-trips image urls and qualifiers", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewHuntRepository(tx)
+
+			h := testHunt("Whitetail Weekend", date)
+			h.ImageURLs = []string{"https://example.com/a.jpg", "https://example.com/b.jpg"}
+			h.Qualifiers = sql.NullString{String: "Hunter safety card", Valid: true}
+			created := createTestHunt(t, repo, h)
+
+			got, err := repo.GetByID(t.Context(), created.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.ImageURLs).To(Equal(h.ImageURLs))
+			g.Expect(got.Qualifiers).To(Equal(h.Qualifiers))
+			g.Expect(got.Status).To(Equal(model.HuntStatusDraft))
+			g.Expect(got.HuntDate.Equal(date)).To(BeTrue())
+		})
+	})
+
+	t.Run("stores no images as an empty list and null qualifiers", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewHuntRepository(tx)
+
+			created := createTestHunt(t, repo, testHunt("Bare Hunt", date))
+
+			var raw string
+			g.Expect(tx.QueryRowContext(t.Context(),
+				`SELECT image_urls::text FROM hunts WHERE id = $1`, created.ID).Scan(&raw)).To(Succeed())
+			g.Expect(raw).To(Equal("[]"))
+			g.Expect(created.ImageURLs).To(BeEmpty())
+			g.Expect(created.Qualifiers.Valid).To(BeFalse())
+		})
+	})
+
+	t.Run("round-trips lottery settings", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewHuntRepository(tx)
+
+			bare := createTestHunt(t, repo, testHunt("Uniform Hunt", date))
+			g.Expect(bare.LotteryAlgorithm).To(Equal("uniform-v1"))
+			g.Expect(bare.LotteryWeights).To(Equal(model.LotteryWeights{}))
+			g.Expect(bare.ConfirmationWindowHours).To(Equal(72))
+
+			h := testHunt("Weighted Hunt", date)
+			h.LotteryAlgorithm = "weighted-v1"
+			h.LotteryWeights = model.DefaultLotteryWeights()
+			created := createTestHunt(t, repo, h)
+
+			got, err := repo.GetByID(t.Context(), created.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.LotteryAlgorithm).To(Equal("weighted-v1"))
+			g.Expect(got.LotteryWeights).To(Equal(model.DefaultLotteryWeights()))
+		})
+	})
+
+	t.Run("missing hunt", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewHuntRepository(tx)
+
+			_, err := repo.GetByID(t.Context(), uuid.New())
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+}
+
+func TestHuntRepository_Update(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		repo := repository.NewHuntRepository(tx)
+		h := createTestHunt(t, repo, testHunt("Before", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+
+		h.Title = "After"
+		h.ImageURLs = []string{"https://example.com/new.jpg"}
+		h.Qualifiers = sql.NullString{}
+		h.PrimaryCapacity = 8
+		h.AutoOpen = true
+		h.Status = model.HuntStatusOpen
+		got, err := repo.Update(t.Context(), h)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Title).To(Equal("After"))
+		g.Expect(got.ImageURLs).To(Equal([]string{"https://example.com/new.jpg"}))
+		g.Expect(got.PrimaryCapacity).To(Equal(8))
+		g.Expect(got.AutoOpen).To(BeTrue())
+		g.Expect(got.Status).To(Equal(model.HuntStatusDraft))
+	})
+}
+
+func TestHuntRepository_UpdateAfterClose(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		repo := repository.NewHuntRepository(tx)
+		h := createTestHunt(t, repo, testHunt("Drawn", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+		g.Expect(repo.SetStatus(t.Context(), h.ID, model.HuntStatusClosed)).To(Succeed())
+
+		edited := h
+		edited.PrimaryCapacity = 10
+		edited.LotteryWeights = model.LotteryWeights{MissedDrawTickets: []int{1, 5}}
+		_, err := repo.Update(t.Context(), edited)
+		g.Expect(err).To(MatchError(repository.ErrHuntNotEditable))
+
+		got, err := repo.GetByID(t.Context(), h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.PrimaryCapacity).To(Equal(h.PrimaryCapacity))
+		g.Expect(got.LotteryWeights).To(Equal(h.LotteryWeights))
+
+		edited.ID = uuid.New()
+		_, err = repo.Update(t.Context(), edited)
+		g.Expect(err).To(MatchError(repository.ErrNotFound))
+	})
+}
+
+func TestHuntRepository_List(t *testing.T) {
+	db := testDB(t)
+	base := time.Date(2031, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewHuntRepository(tx)
+		early := createTestHunt(t, repo, testHunt("Early", base))
+		mid := testHunt("Mid", base.AddDate(0, 1, 0))
+		mid.Status = model.HuntStatusOpen
+		midHunt := createTestHunt(t, repo, mid)
+		late := testHunt("Late", base.AddDate(0, 2, 0))
+		late.Status = model.HuntStatusOpen
+		late.State = "MT"
+		lateHunt := createTestHunt(t, repo, late)
+
+		t.Run("filters by status", func(t *testing.T) {
+			g := NewWithT(t)
+			hunts, total, err := repo.List(t.Context(), repository.HuntFilter{
+				Status: model.HuntStatusOpen, From: base,
+			}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(2))
+			g.Expect(hunts[0].ID).To(Equal(midHunt.ID))
+			g.Expect(hunts[1].ID).To(Equal(lateHunt.ID))
+		})
+
+		t.Run("filters by date range", func(t *testing.T) {
+			g := NewWithT(t)
+			hunts, total, err := repo.List(t.Context(), repository.HuntFilter{
+				From: base, To: base.AddDate(0, 1, 0),
+			}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(2))
+			g.Expect(hunts[0].ID).To(Equal(early.ID))
+			g.Expect(hunts[1].ID).To(Equal(midHunt.ID))
+		})
+
+		t.Run("filters by state", func(t *testing.T) {
+			g := NewWithT(t)
+			hunts, total, err := repo.List(t.Context(), repository.HuntFilter{
+				From: base, States: []string{"MT", "WY"},
+			}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(1))
+			g.Expect(hunts[0].ID).To(Equal(lateHunt.ID))
+		})
+
+		t.Run("paginates", func(t *testing.T) {
+			g := NewWithT(t)
+			hunts, total, err := repo.List(t.Context(), repository.HuntFilter{From: base}, repository.Page{Limit: 1, Offset: 2})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(3))
+			g.Expect(hunts).To(HaveLen(1))
+			g.Expect(hunts[0].ID).To(Equal(lateHunt.ID))
+		})
+
+		t.Run("lists upcoming open hunts", func(t *testing.T) {
+			g := NewWithT(t)
+			hunts, err := repo.ListUpcomingOpen(t.Context(), base.AddDate(0, 1, 1))
+			g.Expect(err).ToNot(HaveOccurred())
+			ids := make([]uuid.UUID, 0, len(hunts))
+			for _, h := range hunts {
+				ids = append(ids, h.ID)
+			}
+			g.Expect(ids).To(ContainElement(lateHunt.ID))
+			g.Expect(ids).ToNot(ContainElement(midHunt.ID))
+			g.Expect(ids).ToNot(ContainElement(early.ID))
+		})
+
+		t.Run("lists hunts due to open and close", func(t *testing.T) {
+			g := NewWithT(t)
+			scheduled := testHunt("Scheduled", base.AddDate(0, 1, 0))
+			scheduled.AutoOpen = true
+			scheduledHunt := createTestHunt(t, repo, scheduled)
+			g.Expect(scheduledHunt.AutoOpen).To(BeTrue())
+
+			toOpen, err := repo.ListDueToOpen(t.Context(), scheduled.SignupWindowStart)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(huntIDs(toOpen)).To(ContainElement(scheduledHunt.ID))
+			g.Expect(huntIDs(toOpen)).ToNot(ContainElement(early.ID))
+
+			toOpen, err = repo.ListDueToOpen(t.Context(), scheduled.SignupWindowStart.Add(-time.Minute))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(huntIDs(toOpen)).ToNot(ContainElement(scheduledHunt.ID))
+
+			toClose, err := repo.ListDueToClose(t.Context(), mid.SignupWindowEnd)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(huntIDs(toClose)).To(ContainElement(midHunt.ID))
+			g.Expect(huntIDs(toClose)).ToNot(ContainElement(lateHunt.ID))
+			g.Expect(huntIDs(toClose)).ToNot(ContainElement(scheduledHunt.ID))
+		})
+	})
+}
+
+func huntIDs(hunts []model.Hunt) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(hunts))
+	for _, h := range hunts {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+const jobColumns = `id, kind, payload, status, attempts, max_attempts, run_at, locked_until, last_error, created_at, updated_at, finished_at`
+
+func scanJob(row rowScanner) (model.Job, error) {
+	var j model.Job
+	var payload []byte
+	err := row.Scan(&j.ID, &j.Kind, &payload, &j.Status, &j.Attempts, &j.MaxAttempts, &j.RunAt,
+		&j.LockedUntil, &j.LastError, &j.CreatedAt, &j.UpdatedAt, &j.FinishedAt)
+	j.Payload = payload
+	return j, err
+}
+
+// JobRepository handles persistence of the background job queue.
+//
+// Workers claim jobs with FOR UPDATE SKIP LOCKED, so concurrent workers
+// never claim the same job. A claim holds a lease; the claimed attempt
+// number fences off a worker whose lease expired and whose job was
+// claimed again, so its late result is discarded.
+type JobRepository struct {
+	db DBTX
+}
+
+// NewJobRepository creates a JobRepository backed by the given DBTX.
+func NewJobRepository(db DBTX) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// getOne runs a single-job query, mapping no rows to ErrNotFound.
+func (r *JobRepository) getOne(ctx context.Context, op, query string, args ...any) (model.Job, error) {
+	j, err := scanJob(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Job{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return j, nil
+}
+
+// Enqueue adds a pending job. A zero RunAt runs it as soon as possible
+// and a zero MaxAttempts uses model.DefaultJobMaxAttempts. Call it with
+// the transaction making the change the job follows from, so the job
+// exists if and only if the change commits.
+func (r *JobRepository) Enqueue(ctx context.Context, j model.Job) (model.Job, error) {
+	if j.MaxAttempts == 0 {
+		j.MaxAttempts = model.DefaultJobMaxAttempts
+	}
+	payload := []byte(j.Payload)
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	return r.getOne(ctx, "enqueuing job",
+		`INSERT INTO jobs (kind, payload, max_attempts, run_at)
+		 VALUES ($1, $2, $3, COALESCE($4, NOW()))
+		 RETURNING `+jobColumns,
+		j.Kind, payload, j.MaxAttempts, sql.NullTime{Time: j.RunAt, Valid: !j.RunAt.IsZero()},
+	)
+}
+
+// GetByID returns the job with the given ID.
+func (r *JobRepository) GetByID(ctx context.Context, id uuid.UUID) (model.Job, error) {
+	return r.getOne(ctx, "getting job",
+		`SELECT `+jobColumns+` FROM jobs WHERE id = $1`,
+		id,
+	)
+}
+
+// Claim marks the next job due at now as running until now+lease and
+// counts the attempt. Jobs whose lease has expired are claimed again.
+// Returns ErrNotFound when no job is due.
+func (r *JobRepository) Claim(ctx context.Context, now time.Time, lease time.Duration) (model.Job, error) {
+	return r.getOne(ctx, "claiming job",
+		`UPDATE jobs SET status = 'running', attempts = attempts + 1, locked_until = $2, updated_at = NOW()
+		 WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'pending' AND run_at <= $1)
+			   OR (status = 'running' AND locked_until <= $1)
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING `+jobColumns,
+		now, now.Add(lease),
+	)
+}
+
+// Complete marks a claimed job done. Returns ErrNotFound if the claim was
+// lost to another worker.
+func (r *JobRepository) Complete(ctx context.Context, j model.Job) error {
+	return execOne(ctx, r.db, "completing job",
+		`UPDATE jobs SET status = 'done', locked_until = NULL, last_error = NULL, finished_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'running' AND attempts = $2`,
+		j.ID, j.Attempts,
+	)
+}
+
+// Reschedule returns a claimed job that failed to the queue, to run again
+// at runAt. Returns ErrNotFound if the claim was lost to another worker.
+func (r *JobRepository) Reschedule(ctx context.Context, j model.Job, runAt time.Time, lastErr string) error {
+	return execOne(ctx, r.db, "rescheduling job",
+		`UPDATE jobs SET status = 'pending', run_at = $3, locked_until = NULL, last_error = $4, updated_at = NOW()
+		 WHERE id = $1 AND status = 'running' AND attempts = $2`,
+		j.ID, j.Attempts, runAt, lastErr,
+	)
+}
+
+// Bury dead-letters a claimed job that won't be retried automatically.
+// Returns ErrNotFound if the claim was lost to another worker.
+func (r *JobRepository) Bury(ctx context.Context, j model.Job, lastErr string) error {
+	return execOne(ctx, r.db, "burying job",
+		`UPDATE jobs SET status = 'dead', locked_until = NULL, last_error = $3, finished_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'running' AND attempts = $2`,
+		j.ID, j.Attempts, lastErr,
+	)
+}
+
+// Retry puts a dead job back in the queue to run at now, with as many
+// attempts again as it was enqueued with. The attempt count keeps
+// climbing rather than being reset, so a worker still holding a claim
+// from before the job died can't match a later claim's attempt number.
+// Returns ErrNotFound unless the job is dead.
+func (r *JobRepository) Retry(ctx context.Context, id uuid.UUID, now time.Time) (model.Job, error) {
+	return r.getOne(ctx, "retrying job",
+		`UPDATE jobs SET status = 'pending', max_attempts = attempts + max_attempts, run_at = $2, finished_at = NULL, updated_at = NOW()
+		 WHERE id = $1 AND status = 'dead'
+		 RETURNING `+jobColumns,
+		id, now,
+	)
+}
+
+// List returns jobs in status, or every job if status is empty, newest
+// first, along with the total number of matches for pagination.
+func (r *JobRepository) List(ctx context.Context, status model.JobStatus, page Page) ([]model.Job, int, error) {
+	where := ""
+	var args []any
+	if status != "" {
+		args = append(args, status)
+		where = " WHERE status = $1"
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM jobs`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting jobs: %w", err)
+	}
+
+	args = append(args, page.Limit, page.Offset)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+jobColumns+` FROM jobs`+where+
+			fmt.Sprintf(` ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var jobs []model.Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning job: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating jobs: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// CountByStatus returns the number of jobs in each status. Statuses
+// without jobs are absent.
+func (r *JobRepository) CountByStatus(ctx context.Context) (map[model.JobStatus]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM jobs GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("counting jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := map[model.JobStatus]int{}
+	for rows.Next() {
+		var (
+			status model.JobStatus
+			n      int
+		)
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("scanning job count: %w", err)
+		}
+		counts[status] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating job counts: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,148 @@
+package repository_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestJobRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewJobRepository(tx)
+		// Far in the future so jobs enqueued by other tests never interfere.
+		now := time.Now().Add(100 * 365 * 24 * time.Hour).Truncate(time.Microsecond)
+		lease := time.Minute
+
+		t.Run("enqueues a pending job with defaults", func(t *testing.T) {
+			g := NewWithT(t)
+			j, err := repo.Enqueue(t.Context(), model.Job{Kind: "test.default"})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(j.Status).To(Equal(model.JobPending))
+			g.Expect(j.MaxAttempts).To(Equal(model.DefaultJobMaxAttempts))
+			g.Expect(string(j.Payload)).To(Equal("{}"))
+
+			got, err := repo.GetByID(t.Context(), j.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Kind).To(Equal("test.default"))
+		})
+
+		t.Run("claims due jobs in order and skips future ones", func(t *testing.T) {
+			g := NewWithT(t)
+			later, err := repo.Enqueue(t.Context(), model.Job{Kind: "test.later", RunAt: now.Add(-time.Second)})
+			g.Expect(err).ToNot(HaveOccurred())
+			first, err := repo.Enqueue(t.Context(), model.Job{
+				Kind:    "test.first",
+				Payload: json.RawMessage(`{"n": 1}`),
+				RunAt:   now.Add(-time.Minute),
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = repo.Enqueue(t.Context(), model.Job{Kind: "test.future", RunAt: now.Add(time.Hour)})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// Drain whatever other tests left due before the first job.
+			var claimed model.Job
+			for claimed.ID != first.ID {
+				if claimed.ID != uuid.Nil {
+					g.Expect(repo.Complete(t.Context(), claimed)).To(Succeed())
+				}
+				claimed, err = repo.Claim(t.Context(), now, lease)
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(claimed.Status).To(Equal(model.JobRunning))
+			g.Expect(claimed.Attempts).To(Equal(1))
+			g.Expect(claimed.LockedUntil.Time).To(BeTemporally("==", now.Add(lease)))
+			g.Expect(claimed.Payload).To(MatchJSON(`{"n": 1}`))
+
+			next, err := repo.Claim(t.Context(), now, lease)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(next.ID).To(Equal(later.ID))
+
+			_, err = repo.Claim(t.Context(), now, lease)
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+
+			g.Expect(repo.Complete(t.Context(), claimed)).To(Succeed())
+			done, err := repo.GetByID(t.Context(), claimed.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(done.Status).To(Equal(model.JobDone))
+			g.Expect(done.FinishedAt.Valid).To(BeTrue())
+			g.Expect(done.LockedUntil.Valid).To(BeFalse())
+
+			g.Expect(repo.Complete(t.Context(), next)).To(Succeed())
+		})
+
+		t.Run("reclaims a job whose lease expired and fences the old claim", func(t *testing.T) {
+			g := NewWithT(t)
+			j, err := repo.Enqueue(t.Context(), model.Job{Kind: "test.lease", RunAt: now})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			stale, err := repo.Claim(t.Context(), now, lease)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(stale.ID).To(Equal(j.ID))
+
+			_, err = repo.Claim(t.Context(), now.Add(lease/2), lease)
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+
+			fresh, err := repo.Claim(t.Context(), now.Add(lease), lease)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(fresh.ID).To(Equal(j.ID))
+			g.Expect(fresh.Attempts).To(Equal(2))
+
+			g.Expect(repo.Complete(t.Context(), stale)).To(MatchError(repository.ErrNotFound))
+			g.Expect(repo.Complete(t.Context(), fresh)).To(Succeed())
+		})
+
+		t.Run("reschedules, buries and retries a failing job", func(t *testing.T) {
+			g := NewWithT(t)
+			j, err := repo.Enqueue(t.Context(), model.Job{Kind: "test.fail", RunAt: now, MaxAttempts: 2})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			claimed, err := repo.Claim(t.Context(), now, lease)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(claimed.ID).To(Equal(j.ID))
+			g.Expect(repo.Reschedule(t.Context(), claimed, now.Add(time.Minute), "boom")).To(Succeed())
+
+			_, err = repo.Claim(t.Context(), now, lease)
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+
+			claimed, err = repo.Claim(t.Context(), now.Add(time.Minute), lease)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(claimed.LastError.String).To(Equal("boom"))
+			g.Expect(claimed.HasAttemptsLeft()).To(BeFalse())
+			g.Expect(repo.Bury(t.Context(), claimed, "boom again")).To(Succeed())
+
+			dead, _, err := repo.List(t.Context(), model.JobDead, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(dead).To(ContainElement(HaveField("ID", j.ID)))
+
+			counts, err := repo.CountByStatus(t.Context())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(counts[model.JobDead]).To(BeNumerically(">=", 1))
+
+			retried, err := repo.Retry(t.Context(), j.ID, now)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(retried.Status).To(Equal(model.JobPending))
+			g.Expect(retried.Attempts).To(Equal(2))
+			g.Expect(retried.MaxAttempts).To(Equal(4))
+			g.Expect(retried.LastError.String).To(Equal("boom again"))
+
+			reclaimed, err := repo.Claim(t.Context(), now, lease)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(reclaimed.ID).To(Equal(j.ID))
+			g.Expect(reclaimed.Attempts).To(Equal(3))
+			g.Expect(repo.Complete(t.Context(), claimed)).To(MatchError(repository.ErrNotFound))
+			g.Expect(repo.Bury(t.Context(), reclaimed, "still failing")).To(Succeed())
+
+			_, err = repo.Retry(t.Context(), j.ID, now)
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+}
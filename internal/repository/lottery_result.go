@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// ErrAlreadyDrawn is returned when a hunt's lottery already has results.
+var ErrAlreadyDrawn = errors.New("lottery already drawn")
+
+const lotteryResultColumns = `id, hunt_id, signup_id, position, audit_seed, algorithm_version, drawn_at, created_at`
+
+func scanLotteryResult(row rowScanner) (model.LotteryResult, error) {
+	var r model.LotteryResult
+	err := row.Scan(&r.ID, &r.HuntID, &r.SignupID, &r.Position, &r.AuditSeed, &r.AlgorithmVersion, &r.DrawnAt, &r.CreatedAt)
+	return r, err
+}
+
+// LotteryResultRepository handles persistence of lottery draw results.
+type LotteryResultRepository struct {
+	db DBTX
+}
+
+// NewLotteryResultRepository creates a LotteryResultRepository backed by
+// the given DBTX.
+func NewLotteryResultRepository(db DBTX) *LotteryResultRepository {
+	return &LotteryResultRepository{db: db}
+}
+
+// Insert records one signup's position in a draw. Returns ErrAlreadyDrawn
+// if the position or signup already has a result.
+func (r *LotteryResultRepository) Insert(ctx context.Context, res model.LotteryResult) (model.LotteryResult, error) {
+	created, err := scanLotteryResult(r.db.QueryRowContext(ctx,
+		`INSERT INTO lottery_results (hunt_id, signup_id, position, audit_seed, algorithm_version, drawn_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+lotteryResultColumns,
+		res.HuntID, res.SignupID, res.Position, res.AuditSeed, res.AlgorithmVersion, res.DrawnAt,
+	))
+	if isUniqueViolation(err, "lottery_results_hunt_position_unique") || isUniqueViolation(err, "lottery_results_signup_unique") {
+		return model.LotteryResult{}, ErrAlreadyDrawn
+	}
+	if err != nil {
+		return model.LotteryResult{}, fmt.Errorf("inserting lottery result: %w", err)
+	}
+	return created, nil
+}
+
+// CountByHunt returns the number of results recorded for a hunt.
+func (r *LotteryResultRepository) CountByHunt(ctx context.Context, huntID uuid.UUID) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM lottery_results WHERE hunt_id = $1`,
+		huntID,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting lottery results: %w", err)
+	}
+	return n, nil
+}
+
+// Candidate is a lottery result that hasn't been offered a place yet,
+// together with the entrant's user ID.
+type Candidate struct {
+	Result model.LotteryResult
+	UserID uuid.UUID
+}
+
+// ListCandidates returns up to limit of a hunt's results at or before
+// maxPosition that have no confirmation yet and whose signup is still
+// active, in draw order. Entrants who were soft-deleted or suspended
+// since signing up are skipped.
+func (r *LotteryResultRepository) ListCandidates(ctx context.Context, huntID uuid.UUID, maxPosition, limit int) ([]Candidate, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+prefixColumns("lr", lotteryResultColumns)+`, s.user_id
+		 FROM lottery_results lr
+		 JOIN signups s ON s.id = lr.signup_id
+		 JOIN users u ON u.id = s.user_id
+		 WHERE lr.hunt_id = $1
+		   AND lr.position <= $2
+		   AND s.withdrawn_at IS NULL
+		   AND u.deleted_at IS NULL
+		   AND u.membership_status <> $4
+		   AND NOT EXISTS (SELECT 1 FROM confirmations c WHERE c.lottery_result_id = lr.id)
+		 ORDER BY lr.position
+		 LIMIT $3`,
+		huntID, maxPosition, limit, model.MembershipSuspended,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing candidates: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var candidates []Candidate
+	for rows.Next() {
+		var c Candidate
+		res := &c.Result
+		if err := rows.Scan(
+			&res.ID, &res.HuntID, &res.SignupID, &res.Position, &res.AuditSeed, &res.AlgorithmVersion, &res.DrawnAt, &res.CreatedAt,
+			&c.UserID,
+		); err != nil {
+			return nil, fmt.Errorf("scanning candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// ListByHunt returns a hunt's results in draw order.
+func (r *LotteryResultRepository) ListByHunt(ctx context.Context, huntID uuid.UUID) ([]model.LotteryResult, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+lotteryResultColumns+`
+		 FROM lottery_results
+		 WHERE hunt_id = $1
+		 ORDER BY position`,
+		huntID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing lottery results: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []model.LotteryResult
+	for rows.Next() {
+		res, err := scanLotteryResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning lottery result: %w", err)
+		}
+		results = append(results, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating lottery results: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,108 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestLotteryResultRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewLotteryResultRepository(tx)
+		users := repository.NewUserRepository(tx)
+		signups := repository.NewSignupRepository(tx)
+		h := createTestHunt(t, repository.NewHuntRepository(tx), testHunt("Lottery Hunt", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+
+		var entries []model.Signup
+		for _, email := range []string{"a@lottery.test", "b@lottery.test"} {
+			s, err := signups.Create(t.Context(), createTestUser(t, users, email, email).ID, h.ID, model.EligibilitySnapshot{})
+			if err != nil {
+				t.Fatalf("creating signup: %v", err)
+			}
+			entries = append(entries, s)
+		}
+		drawnAt := time.Date(2030, 10, 1, 0, 0, 0, 0, time.UTC)
+		result := func(s model.Signup, pos int) model.LotteryResult {
+			return model.LotteryResult{
+				HuntID: h.ID, SignupID: s.ID, Position: pos,
+				AuditSeed: 42, AlgorithmVersion: "test", DrawnAt: drawnAt,
+			}
+		}
+
+		t.Run("records results and lists them in order", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.Insert(t.Context(), result(entries[1], 2))
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = repo.Insert(t.Context(), result(entries[0], 1))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			n, err := repo.CountByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(n).To(Equal(2))
+
+			results, err := repo.ListByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(results).To(HaveLen(2))
+			g.Expect(results[0].SignupID).To(Equal(entries[0].ID))
+			g.Expect(results[0].AuditSeed).To(Equal(int64(42)))
+
+			drawn, err := signups.ListDrawnByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(drawn).To(HaveLen(2))
+		})
+
+		t.Run("lists entrants not yet offered a place", func(t *testing.T) {
+			g := NewWithT(t)
+			candidates, err := repo.ListCandidates(t.Context(), h.ID, 2, 1)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(candidates).To(HaveLen(1))
+			g.Expect(candidates[0].Result.Position).To(Equal(1))
+			g.Expect(candidates[0].UserID).To(Equal(entries[0].UserID))
+
+			_, err = repository.NewConfirmationRepository(tx).Create(t.Context(), model.Confirmation{
+				LotteryResultID: candidates[0].Result.ID, HuntID: h.ID, UserID: entries[0].UserID, Deadline: drawnAt,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			candidates, err = repo.ListCandidates(t.Context(), h.ID, 2, 5)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(candidates).To(HaveLen(1))
+			g.Expect(candidates[0].Result.Position).To(Equal(2))
+		})
+
+		t.Run("skips suspended and deleted entrants", func(t *testing.T) {
+			g := NewWithT(t)
+			userID := entries[1].UserID
+
+			g.Expect(users.ChangeMembershipStatus(t.Context(), userID, model.MembershipSuspended)).To(Succeed())
+			candidates, err := repo.ListCandidates(t.Context(), h.ID, 2, 5)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(candidates).To(BeEmpty())
+
+			g.Expect(users.ChangeMembershipStatus(t.Context(), userID, model.MembershipActive)).To(Succeed())
+			g.Expect(users.SoftDelete(t.Context(), userID)).To(Succeed())
+			candidates, err = repo.ListCandidates(t.Context(), h.ID, 2, 5)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(candidates).To(BeEmpty())
+
+			g.Expect(users.Restore(t.Context(), userID)).To(Succeed())
+			candidates, err = repo.ListCandidates(t.Context(), h.ID, 2, 5)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(candidates).To(HaveLen(1))
+		})
+
+		// A unique violation aborts the transaction, so this runs last.
+		t.Run("maps duplicate positions to ErrAlreadyDrawn", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.Insert(t.Context(), result(entries[0], 1))
+			g.Expect(err).To(MatchError(repository.ErrAlreadyDrawn))
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+const membershipDecisionColumns = `id, user_id, from_status, to_status, reason, decided_by_id, decided_at`
+
+func scanMembershipDecision(row rowScanner) (model.MembershipDecision, error) {
+	var d model.MembershipDecision
+	err := row.Scan(&d.ID, &d.UserID, &d.FromStatus, &d.ToStatus, &d.Reason, &d.DecidedByID, &d.DecidedAt)
+	return d, err
+}
+
+// MembershipDecisionRepository handles persistence of membership decisions.
+type MembershipDecisionRepository struct {
+	db DBTX
+}
+
+// NewMembershipDecisionRepository creates a MembershipDecisionRepository
+// backed by the given DBTX.
+func NewMembershipDecisionRepository(db DBTX) *MembershipDecisionRepository {
+	return &MembershipDecisionRepository{db: db}
+}
+
+// Insert records a membership decision.
+func (r *MembershipDecisionRepository) Insert(ctx context.Context, d model.MembershipDecision) (model.MembershipDecision, error) {
+	created, err := scanMembershipDecision(r.db.QueryRowContext(ctx,
+		`INSERT INTO membership_decisions (user_id, from_status, to_status, reason, decided_by_id)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+membershipDecisionColumns,
+		d.UserID, d.FromStatus, d.ToStatus, d.Reason, d.DecidedByID,
+	))
+	if err != nil {
+		return model.MembershipDecision{}, fmt.Errorf("inserting membership decision: %w", err)
+	}
+	return created, nil
+}
+
+// ListByUser returns a user's membership decisions, newest first.
+func (r *MembershipDecisionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.MembershipDecision, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+membershipDecisionColumns+`
+		 FROM membership_decisions
+		 WHERE user_id = $1
+		 ORDER BY decided_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing membership decisions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var decisions []model.MembershipDecision
+	for rows.Next() {
+		d, err := scanMembershipDecision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning membership decision: %w", err)
+		}
+		decisions = append(decisions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating membership decisions: %w", err)
+	}
+	return decisions, nil
+}
@@ -0,0 +1,41 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestMembershipDecisionRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		users := repository.NewUserRepository(tx)
+		repo := repository.NewMembershipDecisionRepository(tx)
+
+		member := createTestUser(t, users, "member@decision.test", "Member")
+		staff := createTestUser(t, users, "staff@decision.test", "Staff")
+
+		for _, to := range []model.MembershipStatus{model.MembershipActive, model.MembershipSuspended} {
+			_, err := repo.Insert(t.Context(), model.MembershipDecision{
+				UserID:      member.ID,
+				FromStatus:  model.MembershipPending,
+				ToStatus:    to,
+				Reason:      "because " + string(to),
+				DecidedByID: staff.ID,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+
+		decisions, err := repo.ListByUser(t.Context(), member.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(decisions).To(HaveLen(2))
+		g.Expect(decisions[0].DecidedByID).To(Equal(staff.ID))
+		g.Expect(decisions[0].DecidedAt.IsZero()).To(BeFalse())
+	})
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// ErrRegionExists is returned when creating a region whose ID is taken.
+var ErrRegionExists = errors.New("region already exists")
+
+// regionColumns selects a region from alias r, aggregating its states.
+const regionColumns = `r.id, r.name, r.color, r.position,
+	COALESCE((SELECT json_agg(s.state ORDER BY s.state) FROM region_states s WHERE s.region_id = r.id), '[]'),
+	r.created_at, r.updated_at`
+
+func scanRegion(row rowScanner) (model.Region, error) {
+	var (
+		r      model.Region
+		states stringList
+	)
+	err := row.Scan(&r.ID, &r.Name, &r.Color, &r.Position, &states, &r.CreatedAt, &r.UpdatedAt)
+	r.States = states
+	return r, err
+}
+
+// RegionRepository handles persistence of regions, the states they cover
+// and their leaders.
+type RegionRepository struct {
+	db DBTX
+}
+
+// NewRegionRepository creates a RegionRepository backed by the given DBTX.
+func NewRegionRepository(db DBTX) *RegionRepository {
+	return &RegionRepository{db: db}
+}
+
+// getOne runs a single-region query, mapping no rows to ErrNotFound.
+func (r *RegionRepository) getOne(ctx context.Context, op, query string, args ...any) (model.Region, error) {
+	region, err := scanRegion(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Region{}, ErrNotFound
+	}
+	if isUniqueViolation(err, "regions_pkey") {
+		return model.Region{}, ErrRegionExists
+	}
+	if err != nil {
+		return model.Region{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return region, nil
+}
+
+// GetByID returns the region with the given ID.
+func (r *RegionRepository) GetByID(ctx context.Context, id string) (model.Region, error) {
+	return r.getOne(ctx, "getting region",
+		`SELECT `+regionColumns+` FROM regions r WHERE r.id = $1`,
+		id,
+	)
+}
+
+// Create inserts a region without states. Returns ErrRegionExists if its
+// ID is taken.
+func (r *RegionRepository) Create(ctx context.Context, region model.Region) (model.Region, error) {
+	return r.getOne(ctx, "inserting region",
+		`WITH r AS (
+			INSERT INTO regions (id, name, color, position) VALUES ($1, $2, $3, $4)
+			RETURNING *
+		 )
+		 SELECT `+regionColumns+` FROM r`,
+		region.ID, region.Name, region.Color, region.Position,
+	)
+}
+
+// Update saves a region's name, color and position.
+func (r *RegionRepository) Update(ctx context.Context, region model.Region) (model.Region, error) {
+	return r.getOne(ctx, "updating region",
+		`WITH r AS (
+			UPDATE regions SET name = $2, color = $3, position = $4, updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		 )
+		 SELECT `+regionColumns+` FROM r`,
+		region.ID, region.Name, region.Color, region.Position,
+	)
+}
+
+// List returns every region in map order.
+func (r *RegionRepository) List(ctx context.Context) ([]model.Region, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+regionColumns+` FROM regions r ORDER BY r.position, r.name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing regions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var regions []model.Region
+	for rows.Next() {
+		region, err := scanRegion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning region: %w", err)
+		}
+		regions = append(regions, region)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating regions: %w", err)
+	}
+	return regions, nil
+}
+
+// SetStates replaces the states a region covers. States belong to one
+// region at a time, so any taken from another region move to this one.
+func (r *RegionRepository) SetStates(ctx context.Context, regionID string, states []string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM region_states WHERE region_id = $1`,
+		regionID,
+	); err != nil {
+		return fmt.Errorf("clearing region states: %w", err)
+	}
+	for _, state := range states {
+		if _, err := r.db.ExecContext(ctx,
+			`INSERT INTO region_states (state, region_id) VALUES ($1, $2)
+			 ON CONFLICT (state) DO UPDATE SET region_id = EXCLUDED.region_id`,
+			state, regionID,
+		); err != nil {
+			return fmt.Errorf("adding region state: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetLeader returns the leader of a region.
+func (r *RegionRepository) GetLeader(ctx context.Context, regionID string) (model.RegionalLeader, error) {
+	var l model.RegionalLeader
+	err := r.db.QueryRowContext(ctx,
+		`SELECT region_id, name, email, updated_at FROM regional_leaders WHERE region_id = $1`,
+		regionID,
+	).Scan(&l.RegionID, &l.Name, &l.Email, &l.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.RegionalLeader{}, ErrNotFound
+	}
+	if err != nil {
+		return model.RegionalLeader{}, fmt.Errorf("getting regional leader: %w", err)
+	}
+	return l, nil
+}
+
+// SaveLeader sets a region's leader, replacing any existing one.
+func (r *RegionRepository) SaveLeader(ctx context.Context, l model.RegionalLeader) (model.RegionalLeader, error) {
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO regional_leaders (region_id, name, email) VALUES ($1, $2, $3)
+		 ON CONFLICT (region_id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email, updated_at = NOW()
+		 RETURNING region_id, name, email, updated_at`,
+		l.RegionID, l.Name, l.Email,
+	).Scan(&l.RegionID, &l.Name, &l.Email, &l.UpdatedAt)
+	if err != nil {
+		return model.RegionalLeader{}, fmt.Errorf("saving regional leader: %w", err)
+	}
+	return l, nil
+}
+
+// RemoveLeader clears a region's leader, if it has one.
+func (r *RegionRepository) RemoveLeader(ctx context.Context, regionID string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM regional_leaders WHERE region_id = $1`,
+		regionID,
+	); err != nil {
+		return fmt.Errorf("removing regional leader: %w", err)
+	}
+	return nil
+}
+
+// ListLeaders returns every region's leader in map order.
+func (r *RegionRepository) ListLeaders(ctx context.Context) ([]model.RegionalLeader, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT l.region_id, l.name, l.email, l.updated_at
+		 FROM regional_leaders l
+		 JOIN regions r ON r.id = l.region_id
+		 ORDER BY r.position, r.name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing regional leaders: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var leaders []model.RegionalLeader
+	for rows.Next() {
+		var l model.RegionalLeader
+		if err := rows.Scan(&l.RegionID, &l.Name, &l.Email, &l.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning regional leader: %w", err)
+		}
+		leaders = append(leaders, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating regional leaders: %w", err)
+	}
+	return leaders, nil
+}
@@ -0,0 +1,95 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestRegionRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewRegionRepository(tx)
+
+		t.Run("seeds the original regions and leaders", func(t *testing.T) {
+			g := NewWithT(t)
+			midwest, err := repo.GetByID(t.Context(), "midwest")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(midwest.States).To(HaveLen(12))
+			g.Expect(midwest.HasState("IA")).To(BeTrue())
+
+			leader, err := repo.GetLeader(t.Context(), "midwest")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(leader.Email).To(Equal("midwest@thefallenoutdoors.org"))
+		})
+
+		t.Run("creates and updates a region", func(t *testing.T) {
+			g := NewWithT(t)
+			created, err := repo.Create(t.Context(), model.Region{ID: "plains", Name: "Plains", Color: "#AABBCC", Position: 9})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(created.States).To(BeEmpty())
+
+			created.Name = "Great Plains"
+			updated, err := repo.Update(t.Context(), created)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(updated.Name).To(Equal("Great Plains"))
+
+			_, err = repo.Update(t.Context(), model.Region{ID: "nowhere", Name: "Nowhere", Color: "#000000"})
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+
+		t.Run("moves states between regions", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(repo.SetStates(t.Context(), "plains", []string{"NE", "KS"})).To(Succeed())
+
+			plains, err := repo.GetByID(t.Context(), "plains")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(plains.States).To(Equal([]string{"KS", "NE"}))
+
+			midwest, err := repo.GetByID(t.Context(), "midwest")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(midwest.HasState("NE")).To(BeFalse())
+			g.Expect(midwest.States).To(HaveLen(10))
+		})
+
+		t.Run("saves and removes a leader", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.GetLeader(t.Context(), "plains")
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+
+			_, err = repo.SaveLeader(t.Context(), model.RegionalLeader{RegionID: "plains", Name: "Pat", Email: "plains@example.com"})
+			g.Expect(err).ToNot(HaveOccurred())
+			saved, err := repo.SaveLeader(t.Context(), model.RegionalLeader{RegionID: "plains", Name: "Pat Doe", Email: "plains@example.com"})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(saved.Name).To(Equal("Pat Doe"))
+
+			leaders, err := repo.ListLeaders(t.Context())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(leaders).To(ContainElement(HaveField("RegionID", "plains")))
+
+			g.Expect(repo.RemoveLeader(t.Context(), "plains")).To(Succeed())
+			_, err = repo.GetLeader(t.Context(), "plains")
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+
+		t.Run("lists regions in map order", func(t *testing.T) {
+			g := NewWithT(t)
+			regions, err := repo.List(t.Context())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(regions[0].ID).To(Equal("west-coast"))
+			g.Expect(regions[len(regions)-1].ID).To(Equal("plains"))
+		})
+
+		// A unique violation aborts the transaction, so this runs last.
+		t.Run("returns ErrRegionExists for a taken ID", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.Create(t.Context(), model.Region{ID: "midwest", Name: "Midwest", Color: "#81D742"})
+			g.Expect(err).To(MatchError(repository.ErrRegionExists))
+		})
+	})
+}
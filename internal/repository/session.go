@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+const sessionColumns = `id, user_id, token_hash, previous_token_hash, created_at, last_seen_at, rotated_at, expires_at, revoked_at`
+
+func scanSession(row rowScanner) (model.Session, error) {
+	var s model.Session
+	err := row.Scan(&s.ID, &s.UserID, &s.TokenHash, &s.PreviousTokenHash, &s.CreatedAt, &s.LastSeenAt, &s.RotatedAt, &s.ExpiresAt, &s.RevokedAt)
+	return s, err
+}
+
+// SessionRepository handles persistence of login sessions.
+type SessionRepository struct {
+	db DBTX
+}
+
+// NewSessionRepository creates a SessionRepository backed by the given DBTX.
+func NewSessionRepository(db DBTX) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create stores a new session for userID that expires at expiresAt.
+func (r *SessionRepository) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) (model.Session, error) {
+	s, err := scanSession(r.db.QueryRowContext(ctx,
+		`INSERT INTO sessions (user_id, token_hash, expires_at)
+		 VALUES ($1, $2, $3)
+		 RETURNING `+sessionColumns,
+		userID, tokenHash, expiresAt,
+	))
+	if err != nil {
+		return model.Session{}, fmt.Errorf("inserting session: %w", err)
+	}
+	return s, nil
+}
+
+// GetByTokenHash returns the session whose current or previous token has
+// the given hash, including revoked and expired sessions. Callers compare
+// TokenHash to tell which matched. Returns ErrNotFound if none exists.
+func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (model.Session, error) {
+	s, err := scanSession(r.db.QueryRowContext(ctx,
+		`SELECT `+sessionColumns+` FROM sessions WHERE token_hash = $1 OR previous_token_hash = $1
+		 ORDER BY token_hash = $1 DESC
+		 LIMIT 1`,
+		tokenHash,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Session{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Session{}, fmt.Errorf("getting session: %w", err)
+	}
+	return s, nil
+}
+
+// Touch records activity on a session, extending its idle timeout.
+func (r *SessionRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET last_seen_at = NOW() WHERE id = $1`,
+		id,
+	); err != nil {
+		return fmt.Errorf("touching session: %w", err)
+	}
+	return nil
+}
+
+// Rotate replaces a session's token hash currentHash with tokenHash,
+// keeping currentHash as the previous token. Returns ErrNotFound if the
+// session was revoked or already rotated away from currentHash, as when
+// two requests carrying the same stale cookie race to rotate it.
+func (r *SessionRepository) Rotate(ctx context.Context, id uuid.UUID, currentHash, tokenHash string) error {
+	return execOne(ctx, r.db, "rotating session",
+		`UPDATE sessions SET previous_token_hash = token_hash, token_hash = $3, rotated_at = NOW(), last_seen_at = NOW()
+		 WHERE id = $1 AND token_hash = $2 AND revoked_at IS NULL`,
+		id, currentHash, tokenHash,
+	)
+}
+
+// Revoke marks a session as logged out.
+func (r *SessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`,
+		id,
+	); err != nil {
+		return fmt.Errorf("revoking session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllForUser logs a user out everywhere.
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID,
+	); err != nil {
+		return fmt.Errorf("revoking user sessions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestSessionRepository(t *testing.T) {
+	db := testDB(t)
+
+	withTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		users := repository.NewUserRepository(tx)
+		repo := repository.NewSessionRepository(tx)
+
+		u, err := users.UpsertFacebookUser(t.Context(), "fb-session", "session@example.com", "Session User")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		created, err := repo.Create(t.Context(), u.ID, "hash-1", time.Now().Add(time.Hour))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(created.UserID).To(Equal(u.ID))
+
+		t.Run("gets by token hash", func(t *testing.T) {
+			g := NewWithT(t)
+			s, err := repo.GetByTokenHash(t.Context(), "hash-1")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.ID).To(Equal(created.ID))
+		})
+
+		t.Run("rotate replaces the token hash and keeps the previous one", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(repo.Rotate(t.Context(), created.ID, "hash-1", "hash-2")).To(Succeed())
+
+			s, err := repo.GetByTokenHash(t.Context(), "hash-2")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.ID).To(Equal(created.ID))
+			g.Expect(s.TokenHash).To(Equal("hash-2"))
+
+			prev, err := repo.GetByTokenHash(t.Context(), "hash-1")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(prev.ID).To(Equal(created.ID))
+			g.Expect(prev.PreviousTokenHash.String).To(Equal("hash-1"))
+
+			g.Expect(repo.Rotate(t.Context(), created.ID, "hash-1", "hash-3")).To(MatchError(repository.ErrNotFound))
+			g.Expect(repo.Rotate(t.Context(), created.ID, "hash-2", "hash-3")).To(Succeed())
+			_, err = repo.GetByTokenHash(t.Context(), "hash-1")
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+
+		t.Run("revoke marks the session revoked", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(repo.Revoke(t.Context(), created.ID)).To(Succeed())
+
+			s, err := repo.GetByTokenHash(t.Context(), "hash-3")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.IsRevoked()).To(BeTrue())
+		})
+	})
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// ErrAlreadyEntered is returned when a user already has a signup for a
+// hunt, withdrawn or not.
+var ErrAlreadyEntered = errors.New("already entered")
+
+const signupColumns = `id, user_id, hunt_id, eligibility_snapshot, created_at, withdrawn_at`
+
+func scanSignup(row rowScanner) (model.Signup, error) {
+	var s model.Signup
+	err := row.Scan(&s.ID, &s.UserID, &s.HuntID, &s.EligibilitySnapshot, &s.CreatedAt, &s.WithdrawnAt)
+	return s, err
+}
+
+// SignupRepository handles persistence of hunt signups.
+type SignupRepository struct {
+	db DBTX
+}
+
+// NewSignupRepository creates a SignupRepository backed by the given DBTX.
+func NewSignupRepository(db DBTX) *SignupRepository {
+	return &SignupRepository{db: db}
+}
+
+// Create enters userID into the lottery for huntID, recording why they
+// were eligible. Returns ErrAlreadyEntered if the user has ever signed up
+// for the hunt; use Reenter to undo a withdrawal.
+func (r *SignupRepository) Create(ctx context.Context, userID, huntID uuid.UUID, snapshot model.EligibilitySnapshot) (model.Signup, error) {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return model.Signup{}, fmt.Errorf("encoding eligibility snapshot: %w", err)
+	}
+	s, err := scanSignup(r.db.QueryRowContext(ctx,
+		`INSERT INTO signups (user_id, hunt_id, eligibility_snapshot)
+		 VALUES ($1, $2, $3)
+		 RETURNING `+signupColumns,
+		userID, huntID, string(encoded),
+	))
+	if isUniqueViolation(err, "signups_user_hunt_unique") {
+		return model.Signup{}, ErrAlreadyEntered
+	}
+	if err != nil {
+		return model.Signup{}, fmt.Errorf("inserting signup: %w", err)
+	}
+	return s, nil
+}
+
+// GetByUserAndHunt returns userID's signup for huntID, including a
+// withdrawn one. Returns ErrNotFound if the user never signed up.
+func (r *SignupRepository) GetByUserAndHunt(ctx context.Context, userID, huntID uuid.UUID) (model.Signup, error) {
+	s, err := scanSignup(r.db.QueryRowContext(ctx,
+		`SELECT `+signupColumns+` FROM signups WHERE user_id = $1 AND hunt_id = $2`,
+		userID, huntID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Signup{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Signup{}, fmt.Errorf("getting signup: %w", err)
+	}
+	return s, nil
+}
+
+// Withdraw marks an active signup as withdrawn. The row is kept so the
+// entry's history survives. Returns ErrNotFound if the signup is missing
+// or already withdrawn.
+func (r *SignupRepository) Withdraw(ctx context.Context, id uuid.UUID) error {
+	return execOne(ctx, r.db, "withdrawing signup",
+		`UPDATE signups SET withdrawn_at = NOW() WHERE id = $1 AND withdrawn_at IS NULL`,
+		id,
+	)
+}
+
+// Reenter reactivates a withdrawn signup, replacing its eligibility
+// snapshot with one taken at re-entry. Returns ErrNotFound if the signup
+// is missing or not withdrawn.
+func (r *SignupRepository) Reenter(ctx context.Context, id uuid.UUID, snapshot model.EligibilitySnapshot) error {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("encoding eligibility snapshot: %w", err)
+	}
+	return execOne(ctx, r.db, "re-entering signup",
+		`UPDATE signups SET withdrawn_at = NULL, eligibility_snapshot = $2
+		 WHERE id = $1 AND withdrawn_at IS NOT NULL`,
+		id, string(encoded),
+	)
+}
+
+// ParticipationHistory summarises a member's past lottery draws.
+// Draws for cancelled hunts are ignored.
+type ParticipationHistory struct {
+	// CompletedHunts counts completed hunts the member was selected for
+	// and accepted their place on.
+	CompletedHunts int
+	LastSelectedAt sql.NullTime
+	// MissedDraws counts draws the member wasn't selected in since
+	// LastSelectedAt, or ever if they've never been selected.
+	MissedDraws int
+}
+
+// GetParticipationHistory returns userID's history of lottery draws.
+// Being selected means being offered a place, whether from a primary
+// position or as a promoted alternate; an alternate who is never offered
+// one has missed the draw.
+func (r *SignupRepository) GetParticipationHistory(ctx context.Context, userID uuid.UUID) (ParticipationHistory, error) {
+	var h ParticipationHistory
+	if err := r.db.QueryRowContext(ctx,
+		`WITH draws AS (
+			SELECT lr.drawn_at, h.status,
+				EXISTS (
+					SELECT 1 FROM confirmations c WHERE c.lottery_result_id = lr.id
+				) AS selected,
+				EXISTS (
+					SELECT 1 FROM confirmations c
+					WHERE c.lottery_result_id = lr.id AND c.decision = 'accepted'
+				) AS accepted
+			FROM lottery_results lr
+			JOIN signups s ON s.id = lr.signup_id
+			JOIN hunts h ON h.id = lr.hunt_id
+			WHERE s.user_id = $1 AND h.status <> 'cancelled'
+		 ), last AS (
+			SELECT MAX(drawn_at) AS drawn_at FROM draws WHERE selected
+		 )
+		 SELECT
+			COUNT(*) FILTER (WHERE accepted AND status = 'completed'),
+			(SELECT drawn_at FROM last),
+			COUNT(*) FILTER (WHERE NOT selected AND drawn_at > COALESCE((SELECT drawn_at FROM last), '-infinity'))
+		 FROM draws`,
+		userID,
+	).Scan(&h.CompletedHunts, &h.LastSelectedAt, &h.MissedDraws); err != nil {
+		return ParticipationHistory{}, fmt.Errorf("getting participation history: %w", err)
+	}
+	return h, nil
+}
+
+// HuntEntry is a signup together with the entrant's name and email.
+type HuntEntry struct {
+	Signup model.Signup
+	Name   string
+	Email  string
+}
+
+// ListEntriesByHunt returns every signup for a hunt, including withdrawn
+// ones, oldest first.
+func (r *SignupRepository) ListEntriesByHunt(ctx context.Context, huntID uuid.UUID) ([]HuntEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+prefixColumns("s", signupColumns)+`, u.name, u.email
+		 FROM signups s
+		 JOIN users u ON u.id = s.user_id
+		 WHERE s.hunt_id = $1
+		 ORDER BY s.created_at, s.id`,
+		huntID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing hunt entries: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []HuntEntry
+	for rows.Next() {
+		var e HuntEntry
+		s := &e.Signup
+		if err := rows.Scan(
+			&s.ID, &s.UserID, &s.HuntID, &s.EligibilitySnapshot, &s.CreatedAt, &s.WithdrawnAt,
+			&e.Name, &e.Email,
+		); err != nil {
+			return nil, fmt.Errorf("scanning hunt entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating hunt entries: %w", err)
+	}
+	return entries, nil
+}
+
+// HuntHistory is one hunt in a member's history: a hunt they entered or
+// were tagged in the after-action report of.
+type HuntHistory struct {
+	Hunt      model.Hunt
+	Entered   bool
+	Withdrawn bool
+	// Position is the member's place in the draw, or 0 if not drawn.
+	Position int
+	// Decision is the member's answer to their selection, or empty if
+	// they weren't offered a place.
+	Decision model.ConfirmationDecision
+	// ReportID is the hunt's after-action report, if it has one.
+	ReportID uuid.NullUUID
+	Tagged   bool
+}
+
+// ListHistory returns every hunt userID entered or was tagged in,
+// most recent hunt first.
+func (r *SignupRepository) ListHistory(ctx context.Context, userID uuid.UUID) ([]HuntHistory, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+prefixColumns("h", huntColumns)+`,
+			s.id IS NOT NULL, s.withdrawn_at IS NOT NULL,
+			COALESCE(lr.position, 0), COALESCE(c.decision, ''),
+			a.id, p.user_id IS NOT NULL
+		 FROM hunts h
+		 LEFT JOIN signups s ON s.hunt_id = h.id AND s.user_id = $1
+		 LEFT JOIN lottery_results lr ON lr.signup_id = s.id
+		 LEFT JOIN confirmations c ON c.lottery_result_id = lr.id
+		 LEFT JOIN hunt_after_action_reports a ON a.hunt_id = h.id
+		 LEFT JOIN aar_participants p ON p.aar_id = a.id AND p.user_id = $1
+		 WHERE s.id IS NOT NULL OR p.user_id IS NOT NULL
+		 ORDER BY h.hunt_date DESC, h.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing hunt history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var history []HuntHistory
+	for rows.Next() {
+		var e HuntHistory
+		hunt, err := scanHunt(extraColumns{row: rows, extra: []any{
+			&e.Entered, &e.Withdrawn, &e.Position, &e.Decision, &e.ReportID, &e.Tagged,
+		}})
+		if err != nil {
+			return nil, fmt.Errorf("scanning hunt history: %w", err)
+		}
+		e.Hunt = hunt
+		history = append(history, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating hunt history: %w", err)
+	}
+	return history, nil
+}
+
+// ListActiveByHunt returns a hunt's signups that haven't been withdrawn,
+// oldest first. Entrants who were soft-deleted or suspended since signing
+// up are skipped, as ListCandidates skips them when promoting alternates.
+func (r *SignupRepository) ListActiveByHunt(ctx context.Context, huntID uuid.UUID) ([]model.Signup, error) {
+	return r.list(ctx, "listing signups",
+		`SELECT `+prefixColumns("s", signupColumns)+`
+		 FROM signups s
+		 JOIN users u ON u.id = s.user_id
+		 WHERE s.hunt_id = $1
+		   AND s.withdrawn_at IS NULL
+		   AND u.deleted_at IS NULL
+		   AND u.membership_status <> $2
+		 ORDER BY s.created_at, s.id`,
+		huntID, model.MembershipSuspended,
+	)
+}
+
+// ListDrawnByHunt returns the signups that were entered in a hunt's
+// lottery draw, that is those with a lottery result.
+func (r *SignupRepository) ListDrawnByHunt(ctx context.Context, huntID uuid.UUID) ([]model.Signup, error) {
+	return r.list(ctx, "listing drawn signups",
+		`SELECT `+prefixColumns("s", signupColumns)+`
+		 FROM signups s
+		 JOIN lottery_results lr ON lr.signup_id = s.id
+		 WHERE s.hunt_id = $1
+		 ORDER BY s.id`,
+		huntID,
+	)
+}
+
+// CountActiveByHunt returns the number of a hunt's signups that haven't
+// been withdrawn.
+func (r *SignupRepository) CountActiveByHunt(ctx context.Context, huntID uuid.UUID) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM signups WHERE hunt_id = $1 AND withdrawn_at IS NULL`,
+		huntID,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting signups: %w", err)
+	}
+	return n, nil
+}
+
+// list runs a query returning signup rows. op describes the query for
+// errors.
+func (r *SignupRepository) list(ctx context.Context, op, query string, args ...any) ([]model.Signup, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var signups []model.Signup
+	for rows.Next() {
+		s, err := scanSignup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning signup: %w", err)
+		}
+		signups = append(signups, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return signups, nil
+}
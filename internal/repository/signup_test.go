@@ -0,0 +1,169 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestSignupRepository(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewSignupRepository(tx)
+		user := createTestUser(t, repository.NewUserRepository(tx), "signup@signup.test", "Signup User")
+		h := createTestHunt(t, repository.NewHuntRepository(tx), testHunt("Signup Hunt", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+
+		snapshot := model.EligibilitySnapshot{MembershipStatus: model.MembershipActive, BranchOfService: "Army"}
+
+		t.Run("creates a signup", func(t *testing.T) {
+			g := NewWithT(t)
+			s, err := repo.Create(t.Context(), user.ID, h.ID, snapshot)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.IsWithdrawn()).To(BeFalse())
+			e, err := s.Eligibility()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(e.BranchOfService).To(Equal("Army"))
+
+			n, err := repo.CountActiveByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(n).To(Equal(1))
+		})
+
+		t.Run("withdraws and re-enters", func(t *testing.T) {
+			g := NewWithT(t)
+			s, err := repo.GetByUserAndHunt(t.Context(), user.ID, h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(repo.Withdraw(t.Context(), s.ID)).To(Succeed())
+			g.Expect(repo.Withdraw(t.Context(), s.ID)).To(MatchError(repository.ErrNotFound))
+			active, err := repo.ListActiveByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(active).To(BeEmpty())
+
+			withdrawn, err := repo.GetByUserAndHunt(t.Context(), user.ID, h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(withdrawn.IsWithdrawn()).To(BeTrue())
+
+			g.Expect(repo.Reenter(t.Context(), s.ID, snapshot)).To(Succeed())
+			g.Expect(repo.Reenter(t.Context(), s.ID, snapshot)).To(MatchError(repository.ErrNotFound))
+			active, err = repo.ListActiveByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(active).To(HaveLen(1))
+		})
+
+		t.Run("leaves suspended and deleted entrants out of the active entries", func(t *testing.T) {
+			g := NewWithT(t)
+			users := repository.NewUserRepository(tx)
+
+			g.Expect(users.ChangeMembershipStatus(t.Context(), user.ID, model.MembershipSuspended)).To(Succeed())
+			active, err := repo.ListActiveByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(active).To(BeEmpty())
+
+			g.Expect(users.ChangeMembershipStatus(t.Context(), user.ID, model.MembershipPending)).To(Succeed())
+			g.Expect(users.SoftDelete(t.Context(), user.ID)).To(Succeed())
+			active, err = repo.ListActiveByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(active).To(BeEmpty())
+
+			g.Expect(users.Restore(t.Context(), user.ID)).To(Succeed())
+		})
+
+		t.Run("lists entries with entrant details", func(t *testing.T) {
+			g := NewWithT(t)
+			entries, err := repo.ListEntriesByHunt(t.Context(), h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(entries).To(HaveLen(1))
+			g.Expect(entries[0].Name).To(Equal("Signup User"))
+			g.Expect(entries[0].Signup.EligibilitySnapshot.Valid).To(BeTrue())
+		})
+
+		t.Run("counts completed hunts the member accepted", func(t *testing.T) {
+			g := NewWithT(t)
+			s, err := repo.GetByUserAndHunt(t.Context(), user.ID, h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			drawnAt := time.Date(2030, 10, 1, 0, 0, 0, 0, time.UTC)
+			res, err := repository.NewLotteryResultRepository(tx).Insert(t.Context(), model.LotteryResult{
+				HuntID: h.ID, SignupID: s.ID, Position: 1, AuditSeed: 42, AlgorithmVersion: "test", DrawnAt: drawnAt,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = tx.ExecContext(t.Context(),
+				`INSERT INTO confirmations (lottery_result_id, hunt_id, user_id, deadline, decision)
+				 VALUES ($1, $2, $3, $4, 'accepted')`,
+				res.ID, h.ID, user.ID, drawnAt.Add(72*time.Hour))
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = tx.ExecContext(t.Context(), `UPDATE hunts SET status = 'completed' WHERE id = $1`, h.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			history, err := repo.GetParticipationHistory(t.Context(), user.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(history.CompletedHunts).To(Equal(1))
+			g.Expect(history.LastSelectedAt.Time).To(BeTemporally("==", drawnAt))
+		})
+
+		t.Run("counts alternates as selected only once offered a place", func(t *testing.T) {
+			g := NewWithT(t)
+			later := createTestHunt(t, repository.NewHuntRepository(tx), testHunt("Later Hunt", time.Date(2030, 12, 1, 6, 0, 0, 0, time.UTC)))
+			s, err := repo.Create(t.Context(), user.ID, later.ID, snapshot)
+			g.Expect(err).ToNot(HaveOccurred())
+			drawnAt := time.Date(2030, 11, 1, 0, 0, 0, 0, time.UTC)
+			res, err := repository.NewLotteryResultRepository(tx).Insert(t.Context(), model.LotteryResult{
+				HuntID: later.ID, SignupID: s.ID, Position: later.PrimaryCapacity + 1, AuditSeed: 42, AlgorithmVersion: "test", DrawnAt: drawnAt,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			history, err := repo.GetParticipationHistory(t.Context(), user.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(history.LastSelectedAt.Time).ToNot(BeTemporally("==", drawnAt))
+			g.Expect(history.MissedDraws).To(Equal(1))
+
+			_, err = tx.ExecContext(t.Context(),
+				`INSERT INTO confirmations (lottery_result_id, hunt_id, user_id, deadline)
+				 VALUES ($1, $2, $3, $4)`,
+				res.ID, later.ID, user.ID, drawnAt.Add(72*time.Hour))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			history, err = repo.GetParticipationHistory(t.Context(), user.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(history.LastSelectedAt.Time).To(BeTemporally("==", drawnAt))
+			g.Expect(history.MissedDraws).To(BeZero())
+		})
+
+		t.Run("lists the member's hunt history", func(t *testing.T) {
+			g := NewWithT(t)
+			staff := createTestUser(t, repository.NewUserRepository(tx), "staff@signup.test", "Staff User")
+			reports := repository.NewAARRepository(tx)
+			report, err := reports.Create(t.Context(), model.HuntAfterActionReport{HuntID: h.ID, Description: "Done", CreatedByID: staff.ID})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(reports.SetParticipants(t.Context(), report.ID, []uuid.UUID{user.ID})).To(Succeed())
+
+			history, err := repo.ListHistory(t.Context(), user.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(history).To(HaveLen(1))
+			g.Expect(history[0].Hunt.ID).To(Equal(h.ID))
+			g.Expect(history[0].Entered).To(BeTrue())
+			g.Expect(history[0].Position).To(Equal(1))
+			g.Expect(history[0].Decision).To(Equal(model.ConfirmationAccepted))
+			g.Expect(history[0].ReportID.UUID).To(Equal(report.ID))
+			g.Expect(history[0].Tagged).To(BeTrue())
+
+			history, err = repo.ListHistory(t.Context(), staff.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(history).To(BeEmpty())
+		})
+
+		// A unique violation aborts the transaction, so this runs last.
+		t.Run("maps duplicates to ErrAlreadyEntered", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.Create(t.Context(), user.ID, h.ID, snapshot)
+			g.Expect(err).To(MatchError(repository.ErrAlreadyEntered))
+		})
+	})
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+var (
+	// ErrUserDeleted is returned when a login matches a soft-deleted user.
+	ErrUserDeleted = errors.New("user has been deleted")
+	// ErrFacebookAccountMismatch is returned when a login's email belongs
+	// to a user already linked to a different Facebook account.
+	ErrFacebookAccountMismatch = errors.New("email is linked to a different Facebook account")
+)
+
+const userColumns = `id, email, name, phone, branch_of_service, contact_preference, role,
+	membership_status, facebook_id, created_at, updated_at, deleted_at`
+
+func scanUser(row rowScanner) (model.User, error) {
+	var u model.User
+	err := row.Scan(
+		&u.ID, &u.Email, &u.Name, &u.Phone, &u.BranchOfService, &u.ContactPreference, &u.Role,
+		&u.MembershipStatus, &u.FacebookID, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt,
+	)
+	return u, err
+}
+
+// UserRepository handles persistence of users.
+type UserRepository struct {
+	db DBTX
+}
+
+// NewUserRepository creates a UserRepository backed by the given DBTX.
+func NewUserRepository(db DBTX) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// ProfileUpdate holds the user-editable profile fields.
+type ProfileUpdate struct {
+	Name              string
+	Phone             sql.NullString
+	BranchOfService   string
+	ContactPreference model.ContactPreference
+}
+
+// UserFilter narrows List results. Zero values match everything.
+type UserFilter struct {
+	Role             model.Role
+	MembershipStatus model.MembershipStatus
+	// Search matches a case-insensitive substring of name or email.
+	Search string
+	// IncludeDeleted also returns soft-deleted users.
+	IncludeDeleted bool
+}
+
+// getOne runs a single-user query, mapping no rows to ErrNotFound.
+func (r *UserRepository) getOne(ctx context.Context, op, query string, args ...any) (model.User, error) {
+	u, err := scanUser(r.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, ErrNotFound
+	}
+	if err != nil {
+		return model.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return u, nil
+}
+
+// GetByID returns the user with the given ID. Soft-deleted users are
+// treated as missing and yield ErrNotFound.
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (model.User, error) {
+	return r.getOne(ctx, "getting user",
+		`SELECT `+userColumns+` FROM users WHERE id = $1 AND deleted_at IS NULL`,
+		id,
+	)
+}
+
+// GetByIDForUpdate is GetByID that also locks the row until the enclosing
+// transaction ends. Use it to serialize read-modify-write changes.
+func (r *UserRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.User, error) {
+	return r.getOne(ctx, "locking user",
+		`SELECT `+userColumns+` FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		id,
+	)
+}
+
+// GetByEmail returns the non-deleted user with the given email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (model.User, error) {
+	return r.getOne(ctx, "getting user by email",
+		`SELECT `+userColumns+` FROM users WHERE email = $1 AND deleted_at IS NULL`,
+		email,
+	)
+}
+
+// GetByFacebookID returns the non-deleted user linked to facebookID.
+func (r *UserRepository) GetByFacebookID(ctx context.Context, facebookID string) (model.User, error) {
+	return r.getOne(ctx, "getting user by facebook id",
+		`SELECT `+userColumns+` FROM users WHERE facebook_id = $1 AND deleted_at IS NULL`,
+		facebookID,
+	)
+}
+
+// Create inserts a user. Empty Role, MembershipStatus and ContactPreference
+// fall back to the table defaults (member, pending, email).
+func (r *UserRepository) Create(ctx context.Context, u model.User) (model.User, error) {
+	role := u.Role
+	if role == "" {
+		role = model.RoleMember
+	}
+	status := u.MembershipStatus
+	if status == "" {
+		status = model.MembershipPending
+	}
+	pref := u.ContactPreference
+	if pref == "" {
+		pref = model.ContactByEmail
+	}
+	created, err := scanUser(r.db.QueryRowContext(ctx,
+		`INSERT INTO users (email, name, phone, branch_of_service, contact_preference, role, membership_status, facebook_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING `+userColumns,
+		u.Email, u.Name, u.Phone, u.BranchOfService, pref, role, status, u.FacebookID,
+	))
+	if err != nil {
+		return model.User{}, fmt.Errorf("inserting user: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateProfile replaces a user's editable profile fields.
+func (r *UserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, p ProfileUpdate) (model.User, error) {
+	return r.getOne(ctx, "updating user profile",
+		`UPDATE users
+		 SET name = $2, phone = $3, branch_of_service = $4, contact_preference = $5, updated_at = NOW()
+		 WHERE id = $1 AND deleted_at IS NULL
+		 RETURNING `+userColumns,
+		id, p.Name, p.Phone, p.BranchOfService, p.ContactPreference,
+	)
+}
+
+// ChangeRole sets a user's role.
+func (r *UserRepository) ChangeRole(ctx context.Context, id uuid.UUID, role model.Role) error {
+	return execOne(ctx, r.db, "changing user role",
+		`UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`,
+		id, role,
+	)
+}
+
+// ChangeMembershipStatus sets a user's membership status.
+func (r *UserRepository) ChangeMembershipStatus(ctx context.Context, id uuid.UUID, status model.MembershipStatus) error {
+	return execOne(ctx, r.db, "changing membership status",
+		`UPDATE users SET membership_status = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`,
+		id, status,
+	)
+}
+
+// SoftDelete marks a user as deleted. Deleted users disappear from the
+// default queries but keep their history.
+func (r *UserRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	return execOne(ctx, r.db, "deleting user",
+		`UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`,
+		id,
+	)
+}
+
+// Restore reverses a soft delete.
+func (r *UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return execOne(ctx, r.db, "restoring user",
+		`UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`,
+		id,
+	)
+}
+
+// List returns users matching filter, ordered by name, along with the
+// total number of matches for pagination.
+func (r *UserRepository) List(ctx context.Context, filter UserFilter, page Page) ([]model.User, int, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if !filter.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conds = append(conds, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.MembershipStatus != "" {
+		args = append(args, filter.MembershipStatus)
+		conds = append(conds, fmt.Sprintf("membership_status = $%d", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conds = append(conds, fmt.Sprintf("(name ILIKE $%d OR email ILIKE $%d)", len(args), len(args)))
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting users: %w", err)
+	}
+
+	args = append(args, page.Limit, page.Offset)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+userColumns+` FROM users`+where+
+			fmt.Sprintf(` ORDER BY name, id LIMIT $%d OFFSET $%d`, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing users: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var users []model.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterating users: %w", err)
+	}
+	return users, total, nil
+}
+
+// UpsertFacebookUser finds or creates the user for a Facebook login.
+// A user already linked to facebookID is returned as-is. Otherwise an
+// existing user whose email matches case-insensitively is linked to
+// facebookID, unless they're linked to another Facebook account, which
+// yields ErrFacebookAccountMismatch. If no such user exists a new pending
+// member is created. Soft-deleted users cannot log in and yield
+// ErrUserDeleted.
+func (r *UserRepository) UpsertFacebookUser(ctx context.Context, facebookID, email, name string) (model.User, error) {
+	u, err := r.findFacebookUser(ctx, facebookID, email)
+	if !errors.Is(err, ErrNotFound) {
+		return u, err
+	}
+
+	u, err = r.getOne(ctx, "inserting user",
+		`INSERT INTO users (email, name, branch_of_service, facebook_id)
+		 VALUES ($1, $2, '', $3)
+		 ON CONFLICT DO NOTHING
+		 RETURNING `+userColumns,
+		email, name, facebookID,
+	)
+	if !errors.Is(err, ErrNotFound) {
+		return u, err
+	}
+	// A concurrent first login inserted the user since the lookup. The
+	// conflict waited for it to commit, so reading again finds it.
+	u, err = r.findFacebookUser(ctx, facebookID, email)
+	if errors.Is(err, ErrNotFound) {
+		return model.User{}, fmt.Errorf("inserting user: conflicting user not found")
+	}
+	return u, err
+}
+
+// findFacebookUser returns the user linked to facebookID, or links the
+// user matching email. Returns ErrNotFound if neither exists.
+func (r *UserRepository) findFacebookUser(ctx context.Context, facebookID, email string) (model.User, error) {
+	u, err := r.getOne(ctx, "getting user by facebook id",
+		`SELECT `+userColumns+` FROM users WHERE facebook_id = $1`,
+		facebookID,
+	)
+	if err == nil && u.IsDeleted() {
+		return model.User{}, ErrUserDeleted
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return u, err
+	}
+
+	u, err = r.getOne(ctx, "getting user by email",
+		`SELECT `+userColumns+` FROM users WHERE lower(email) = lower($1) ORDER BY created_at LIMIT 1`,
+		email,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+	if u.IsDeleted() {
+		return model.User{}, ErrUserDeleted
+	}
+	if u.FacebookID.Valid {
+		return model.User{}, ErrFacebookAccountMismatch
+	}
+
+	linked, err := r.getOne(ctx, "linking facebook id",
+		`UPDATE users SET facebook_id = $2, updated_at = NOW()
+		 WHERE id = $1 AND facebook_id IS NULL
+		 RETURNING `+userColumns,
+		u.ID, facebookID,
+	)
+	if !errors.Is(err, ErrNotFound) {
+		return linked, err
+	}
+	// Linked since it was read: by this Facebook account's concurrent
+	// first login, or by another account.
+	linked, err = r.getOne(ctx, "getting user by facebook id",
+		`SELECT `+userColumns+` FROM users WHERE id = $1 AND facebook_id = $2`,
+		u.ID, facebookID,
+	)
+	if errors.Is(err, ErrNotFound) {
+		return model.User{}, ErrFacebookAccountMismatch
+	}
+	return linked, err
+}
+
+// ListEntrants returns the users with an active (not withdrawn) signup
+// for huntID, ordered by name. Soft-deleted users are excluded.
+func (r *UserRepository) ListEntrants(ctx context.Context, huntID uuid.UUID) ([]model.User, error) {
+	return listUsers(ctx, r.db, "listing hunt entrants",
+		`SELECT `+prefixColumns("u", userColumns)+`
+		 FROM users u
+		 JOIN signups s ON s.user_id = u.id
+		 WHERE s.hunt_id = $1 AND s.withdrawn_at IS NULL AND u.deleted_at IS NULL
+		 ORDER BY u.name, u.id`,
+		huntID,
+	)
+}
+
+// ListConfirmed returns the users who accepted their place on huntID,
+// ordered by name: the hunt's confirmed roster. Soft-deleted users are
+// excluded.
+func (r *UserRepository) ListConfirmed(ctx context.Context, huntID uuid.UUID) ([]model.User, error) {
+	return listUsers(ctx, r.db, "listing confirmed users",
+		`SELECT `+prefixColumns("u", userColumns)+`
+		 FROM users u
+		 JOIN confirmations c ON c.user_id = u.id
+		 WHERE c.hunt_id = $1 AND c.decision = 'accepted' AND u.deleted_at IS NULL
+		 ORDER BY u.name, u.id`,
+		huntID,
+	)
+}
+
+// ListStaff returns active staff and admins, ordered by name: the people
+// work can be assigned to.
+func (r *UserRepository) ListStaff(ctx context.Context) ([]model.User, error) {
+	return listUsers(ctx, r.db, "listing staff",
+		`SELECT `+userColumns+` FROM users
+		 WHERE role IN ($1, $2) AND membership_status = $3 AND deleted_at IS NULL
+		 ORDER BY name, id`,
+		model.RoleStaff, model.RoleAdmin, model.MembershipActive,
+	)
+}
+
+// listUsers runs a query selecting userColumns and scans every row. op
+// describes the query for errors.
+func listUsers(ctx context.Context, db DBTX, op, query string, args ...any) ([]model.User, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var users []model.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return users, nil
+}
@@ -0,0 +1,335 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+func TestUserRepository_UpsertFacebookUser(t *testing.T) {
+	db := testDB(t)
+
+	t.Run("creates a pending member for a new identity", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewUserRepository(tx)
+
+			u, err := repo.UpsertFacebookUser(t.Context(), "fb-1", "new@example.com", "New User")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(u.Email).To(Equal("new@example.com"))
+			g.Expect(u.FacebookID.String).To(Equal("fb-1"))
+			g.Expect(u.Role).To(Equal(model.RoleMember))
+			g.Expect(u.MembershipStatus).To(Equal(model.MembershipPending))
+			g.Expect(u.IsAccountComplete()).To(BeFalse())
+		})
+	})
+
+	t.Run("returns the same user on repeat login", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewUserRepository(tx)
+
+			first, err := repo.UpsertFacebookUser(t.Context(), "fb-2", "repeat@example.com", "Repeat")
+			g.Expect(err).ToNot(HaveOccurred())
+			second, err := repo.UpsertFacebookUser(t.Context(), "fb-2", "repeat@example.com", "Repeat")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(second.ID).To(Equal(first.ID))
+		})
+	})
+
+	t.Run("links an existing user by email", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewUserRepository(tx)
+
+			_, err := tx.ExecContext(t.Context(),
+				`INSERT INTO users (email, name, branch_of_service) VALUES ('link@example.com', 'Link', 'Army')`)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			u, err := repo.UpsertFacebookUser(t.Context(), "fb-3", "link@example.com", "Link")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(u.FacebookID.String).To(Equal("fb-3"))
+			g.Expect(u.BranchOfService).To(Equal("Army"))
+		})
+	})
+
+	t.Run("links an existing user whose email differs in case", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewUserRepository(tx)
+			existing := createTestUser(t, repo, "Mixed.Case@example.com", "Mixed")
+
+			u, err := repo.UpsertFacebookUser(t.Context(), "fb-5", "mixed.case@EXAMPLE.com", "Mixed")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(u.ID).To(Equal(existing.ID))
+			g.Expect(u.FacebookID.String).To(Equal("fb-5"))
+		})
+	})
+
+	t.Run("refuses to relink a user linked to another facebook account", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewUserRepository(tx)
+			first, err := repo.UpsertFacebookUser(t.Context(), "fb-6", "taken@example.com", "Taken")
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = repo.UpsertFacebookUser(t.Context(), "fb-7", "Taken@example.com", "Impostor")
+			g.Expect(err).To(MatchError(repository.ErrFacebookAccountMismatch))
+
+			got, err := repo.GetByID(t.Context(), first.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.FacebookID.String).To(Equal("fb-6"))
+		})
+	})
+
+	t.Run("refuses soft-deleted users", func(t *testing.T) {
+		withTestTx(t, db, func(tx *sql.Tx) {
+			g := NewWithT(t)
+			repo := repository.NewUserRepository(tx)
+
+			_, err := tx.ExecContext(t.Context(),
+				`INSERT INTO users (email, name, branch_of_service, facebook_id, deleted_at)
+				 VALUES ('gone@example.com', 'Gone', 'Navy', 'fb-4', NOW())`)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = repo.UpsertFacebookUser(t.Context(), "fb-4", "gone@example.com", "Gone")
+			g.Expect(err).To(MatchError(repository.ErrUserDeleted))
+		})
+	})
+}
+
+func createTestUser(t *testing.T, repo *repository.UserRepository, email, name string) model.User {
+	t.Helper()
+	u, err := repo.Create(t.Context(), model.User{Email: email, Name: name, BranchOfService: "Army"})
+	if err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	return u
+}
+
+func TestUserRepository_Get(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewUserRepository(tx)
+		u, err := repo.Create(t.Context(), model.User{
+			Email:           "get@example.com",
+			Name:            "Get User",
+			BranchOfService: "Navy",
+			FacebookID:      sql.NullString{String: "fb-get", Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("creating user: %v", err)
+		}
+
+		t.Run("by id", func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := repo.GetByID(t.Context(), u.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Email).To(Equal("get@example.com"))
+			g.Expect(got.MembershipStatus).To(Equal(model.MembershipPending))
+		})
+
+		t.Run("by email", func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := repo.GetByEmail(t.Context(), "get@example.com")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.ID).To(Equal(u.ID))
+		})
+
+		t.Run("by facebook id", func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := repo.GetByFacebookID(t.Context(), "fb-get")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.ID).To(Equal(u.ID))
+		})
+
+		t.Run("missing user", func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := repo.GetByEmail(t.Context(), "nobody@example.com")
+			g.Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+}
+
+func TestUserRepository_Update(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewUserRepository(tx)
+		u := createTestUser(t, repo, "update@example.com", "Update User")
+
+		t.Run("profile", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(u.ContactPreference).To(Equal(model.ContactByEmail))
+			got, err := repo.UpdateProfile(t.Context(), u.ID, repository.ProfileUpdate{
+				Name:              "Updated Name",
+				Phone:             sql.NullString{String: "555-0100", Valid: true},
+				BranchOfService:   "Marine Corps",
+				ContactPreference: model.ContactByText,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Name).To(Equal("Updated Name"))
+			g.Expect(got.Phone.String).To(Equal("555-0100"))
+			g.Expect(got.BranchOfService).To(Equal("Marine Corps"))
+			g.Expect(got.ContactPreference).To(Equal(model.ContactByText))
+		})
+
+		t.Run("role", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(repo.ChangeRole(t.Context(), u.ID, model.RoleStaff)).To(Succeed())
+			got, err := repo.GetByID(t.Context(), u.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Role).To(Equal(model.RoleStaff))
+		})
+
+		t.Run("membership status", func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(repo.ChangeMembershipStatus(t.Context(), u.ID, model.MembershipActive)).To(Succeed())
+			got, err := repo.GetByID(t.Context(), u.ID)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.IsActive()).To(BeTrue())
+		})
+	})
+}
+
+func TestUserRepository_SoftDelete(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		repo := repository.NewUserRepository(tx)
+		u := createTestUser(t, repo, "delete@example.com", "Delete User")
+
+		g.Expect(repo.SoftDelete(t.Context(), u.ID)).To(Succeed())
+
+		_, err := repo.GetByID(t.Context(), u.ID)
+		g.Expect(err).To(MatchError(repository.ErrNotFound))
+		_, err = repo.GetByEmail(t.Context(), "delete@example.com")
+		g.Expect(err).To(MatchError(repository.ErrNotFound))
+		g.Expect(repo.ChangeRole(t.Context(), u.ID, model.RoleAdmin)).To(MatchError(repository.ErrNotFound))
+		g.Expect(repo.SoftDelete(t.Context(), u.ID)).To(MatchError(repository.ErrNotFound))
+
+		g.Expect(repo.Restore(t.Context(), u.ID)).To(Succeed())
+		got, err := repo.GetByID(t.Context(), u.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.IsDeleted()).To(BeFalse())
+	})
+}
+
+func TestUserRepository_List(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		repo := repository.NewUserRepository(tx)
+		alice := createTestUser(t, repo, "alice@list.test", "Alice List")
+		bob := createTestUser(t, repo, "bob@list.test", "Bob List")
+		carol := createTestUser(t, repo, "carol@list.test", "Carol List")
+		if err := repo.ChangeRole(t.Context(), bob.ID, model.RoleStaff); err != nil {
+			t.Fatalf("changing role: %v", err)
+		}
+		if err := repo.ChangeMembershipStatus(t.Context(), alice.ID, model.MembershipActive); err != nil {
+			t.Fatalf("changing status: %v", err)
+		}
+		if err := repo.SoftDelete(t.Context(), carol.ID); err != nil {
+			t.Fatalf("deleting user: %v", err)
+		}
+
+		t.Run("excludes deleted users by default", func(t *testing.T) {
+			g := NewWithT(t)
+			users, total, err := repo.List(t.Context(), repository.UserFilter{Search: "@list.test"}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(2))
+			g.Expect(users).To(HaveLen(2))
+			g.Expect(users[0].Name).To(Equal("Alice List"))
+		})
+
+		t.Run("includes deleted users on request", func(t *testing.T) {
+			g := NewWithT(t)
+			_, total, err := repo.List(t.Context(), repository.UserFilter{Search: "@list.test", IncludeDeleted: true}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(3))
+		})
+
+		t.Run("filters by role and status", func(t *testing.T) {
+			g := NewWithT(t)
+			users, _, err := repo.List(t.Context(), repository.UserFilter{Search: "@list.test", Role: model.RoleStaff}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(users).To(HaveLen(1))
+			g.Expect(users[0].ID).To(Equal(bob.ID))
+
+			users, _, err = repo.List(t.Context(), repository.UserFilter{Search: "@list.test", MembershipStatus: model.MembershipActive}, repository.Page{Limit: 10})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(users).To(HaveLen(1))
+			g.Expect(users[0].ID).To(Equal(alice.ID))
+		})
+
+		t.Run("paginates", func(t *testing.T) {
+			g := NewWithT(t)
+			users, total, err := repo.List(t.Context(), repository.UserFilter{Search: "@list.test"}, repository.Page{Limit: 1, Offset: 1})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(total).To(Equal(2))
+			g.Expect(users).To(HaveLen(1))
+			g.Expect(users[0].ID).To(Equal(bob.ID))
+		})
+	})
+}
+
+func TestUserRepository_ListEntrants(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		repo := repository.NewUserRepository(tx)
+		h := createTestHunt(t, repository.NewHuntRepository(tx), testHunt("Entrants", time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+		active := createTestUser(t, repo, "active@entrants.test", "Active")
+		withdrawn := createTestUser(t, repo, "withdrawn@entrants.test", "Withdrawn")
+
+		_, err := tx.ExecContext(t.Context(),
+			`INSERT INTO signups (user_id, hunt_id, withdrawn_at) VALUES ($1, $3, NULL), ($2, $3, NOW())`,
+			active.ID, withdrawn.ID, h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		users, err := repo.ListEntrants(t.Context(), h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(users).To(HaveLen(1))
+		g.Expect(users[0].ID).To(Equal(active.ID))
+	})
+}
+
+func TestUserRepository_ListStaff(t *testing.T) {
+	db := testDB(t)
+
+	repository.WithTestTx(t, db, func(tx *sql.Tx) {
+		g := NewWithT(t)
+		repo := repository.NewUserRepository(tx)
+		staff := createTestUser(t, repo, "staff@liststaff.test", "Staff")
+		admin := createTestUser(t, repo, "admin@liststaff.test", "Admin")
+		pending := createTestUser(t, repo, "pending@liststaff.test", "Pending Staff")
+		member := createTestUser(t, repo, "member@liststaff.test", "Member")
+		for _, u := range []model.User{staff, admin, pending} {
+			g.Expect(repo.ChangeRole(t.Context(), u.ID, model.RoleStaff)).To(Succeed())
+		}
+		g.Expect(repo.ChangeRole(t.Context(), admin.ID, model.RoleAdmin)).To(Succeed())
+		for _, u := range []model.User{staff, admin, member} {
+			g.Expect(repo.ChangeMembershipStatus(t.Context(), u.ID, model.MembershipActive)).To(Succeed())
+		}
+
+		users, err := repo.ListStaff(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ids := make([]uuid.UUID, len(users))
+		for i, u := range users {
+			ids[i] = u.ID
+		}
+		g.Expect(ids).To(ContainElements(staff.ID, admin.ID))
+		g.Expect(ids).ToNot(ContainElement(pending.ID))
+		g.Expect(ids).ToNot(ContainElement(member.ID))
+	})
+}
@@ -0,0 +1,169 @@
+// Package scheduler makes the hunt lifecycle's time-based changes: opening
+// scheduled hunts when signups start, closing hunts and drawing their
+// lotteries when signups end, and expiring selections nobody confirmed.
+//
+// Every replica runs a Scheduler, but only the one holding a Postgres
+// advisory lock acts, so changes aren't attempted twice. The changes
+// themselves lock the hunt and check its status, so a replica that acts
+// after losing the lock only sees its attempts refused.
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/confirmation"
+	"github.com/brian-abo/tfo-webapp/internal/hunt"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// leaderLockKey identifies the advisory lock held by the leading
+// scheduler. It is "tfosched" in ASCII.
+const leaderLockKey int64 = 0x74666f7363686564
+
+// Scheduler periodically runs the time-based hunt changes while it leads.
+type Scheduler struct {
+	db            *sql.DB
+	hunts         *hunt.Service
+	confirmations *confirmation.Service
+
+	// Interval is how often the leader checks for due changes, and how
+	// often the others try to take over.
+	Interval time.Duration
+
+	now func() time.Time
+}
+
+// New creates a Scheduler that checks every minute.
+func New(db *sql.DB, hunts *hunt.Service, confirmations *confirmation.Service) *Scheduler {
+	return &Scheduler{
+		db:            db,
+		hunts:         hunts,
+		confirmations: confirmations,
+		Interval:      time.Minute,
+		now:           time.Now,
+	}
+}
+
+// Run campaigns for leadership until ctx is cancelled, running Tick every
+// Interval while it leads.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		if err := s.lead(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.Interval):
+		}
+	}
+}
+
+// lead takes the leader lock if it's free and ticks until ctx is
+// cancelled or the lock's connection fails. The lock belongs to one
+// database session, so it's taken on a dedicated connection and lost
+// with it.
+func (s *Scheduler) lead(ctx context.Context) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("getting connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	var leading bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, leaderLockKey).Scan(&leading); err != nil {
+		return fmt.Errorf("taking leader lock: %w", err)
+	}
+	if !leading {
+		return nil
+	}
+	log.Printf("scheduler: leading")
+	defer func() {
+		// Unlock explicitly: the pool may keep the connection, and its
+		// session, open.
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, leaderLockKey); err != nil {
+			log.Printf("scheduler: releasing leader lock: %v", err)
+		}
+	}()
+
+	for {
+		if err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.Interval):
+		}
+		if err := conn.PingContext(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("lost leader lock: %w", err)
+		}
+	}
+}
+
+// Tick makes every change due now: opening scheduled hunts whose signups
+// have started, closing hunts whose signups have ended, which draws their
+// lotteries, and expiring overdue selections, which offers the places to
+// alternates. A failure on one hunt doesn't stop the others; the joined
+// errors are returned and the failed hunts are tried again next tick.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	now := s.now()
+	hunts := repository.NewHuntRepository(s.db)
+	var errs []error
+
+	toOpen, err := hunts.ListDueToOpen(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, h := range toOpen {
+		_, err := s.hunts.Open(ctx, h.ID, uuid.NullUUID{})
+		switch {
+		case err == nil:
+			log.Printf("scheduler: opened %q", h.Title)
+		case errors.Is(err, hunt.ErrInvalidTransition):
+			// Staff changed the hunt since it was listed.
+		default:
+			errs = append(errs, fmt.Errorf("opening hunt %s: %w", h.ID, err))
+		}
+	}
+
+	toClose, err := hunts.ListDueToClose(ctx, now)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, h := range toClose {
+		_, err := s.hunts.Close(ctx, h.ID, uuid.NullUUID{})
+		switch {
+		case err == nil:
+			log.Printf("scheduler: closed %q", h.Title)
+		case errors.Is(err, hunt.ErrInvalidTransition):
+			// Staff changed the hunt since it was listed.
+		default:
+			errs = append(errs, fmt.Errorf("closing hunt %s: %w", h.ID, err))
+		}
+	}
+
+	overdue, err := repository.NewConfirmationRepository(s.db).ListHuntsWithOverdue(ctx, now)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, id := range overdue {
+		promoted, err := s.confirmations.Promote(ctx, id, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("expiring confirmations for hunt %s: %w", id, err))
+			continue
+		}
+		log.Printf("scheduler: expired overdue confirmations for hunt %s, offered %d places", id, promoted)
+	}
+	return errors.Join(errs...)
+}
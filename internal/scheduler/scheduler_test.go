@@ -0,0 +1,311 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/confirmation"
+	"github.com/brian-abo/tfo-webapp/internal/hunt"
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+	"github.com/brian-abo/tfo-webapp/internal/mail"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/notify"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+// testDB returns a connection to the test database, skipping the test if
+// DATABASE_URL is not set. The scheduler runs its own transactions, so
+// unlike the repository tests these commit; fixtures clean up after
+// themselves.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL not set, skipping integration test")
+	}
+
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+
+	if err := db.PingContext(t.Context()); err != nil {
+		t.Fatalf("pinging test database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Logf("closing test database: %v", err)
+		}
+	})
+	return db
+}
+
+// fixtures creates committed test data and deletes it when the test ends.
+type fixtures struct {
+	t       *testing.T
+	db      *sql.DB
+	huntIDs []uuid.UUID
+	userIDs []uuid.UUID
+}
+
+func newFixtures(t *testing.T, db *sql.DB) *fixtures {
+	f := &fixtures{t: t, db: db}
+	t.Cleanup(f.delete)
+	return f
+}
+
+func (f *fixtures) user() model.User {
+	f.t.Helper()
+	u, err := repository.NewUserRepository(f.db).Create(f.t.Context(), model.User{
+		Email:           uuid.NewString() + "@scheduler.test",
+		Name:            "Scheduler Test",
+		BranchOfService: "Army",
+	})
+	if err != nil {
+		f.t.Fatalf("creating user: %v", err)
+	}
+	f.userIDs = append(f.userIDs, u.ID)
+	return u
+}
+
+// hunt creates a hunt whose signups run from start to end.
+func (f *fixtures) hunt(status model.HuntStatus, autoOpen bool, start, end time.Time) model.Hunt {
+	f.t.Helper()
+	h, err := repository.NewHuntRepository(f.db).Create(f.t.Context(), model.Hunt{
+		Title:             "Scheduler Test Hunt",
+		Description:       "A test hunt",
+		Location:          "Somewhere, TX",
+		State:             "TX",
+		HuntDate:          end.AddDate(0, 1, 0),
+		SignupWindowStart: start,
+		SignupWindowEnd:   end,
+		PrimaryCapacity:   1,
+		AlternateCapacity: 1,
+		Status:            status,
+		AutoOpen:          autoOpen,
+	})
+	if err != nil {
+		f.t.Fatalf("creating hunt: %v", err)
+	}
+	f.huntIDs = append(f.huntIDs, h.ID)
+	return h
+}
+
+func (f *fixtures) signup(h model.Hunt) model.Signup {
+	f.t.Helper()
+	s, err := repository.NewSignupRepository(f.db).Create(f.t.Context(), f.user().ID, h.ID, model.EligibilitySnapshot{})
+	if err != nil {
+		f.t.Fatalf("creating signup: %v", err)
+	}
+	return s
+}
+
+// delete removes the fixtures and everything the scheduler made from
+// them, dependents first.
+func (f *fixtures) delete() {
+	ctx := context.Background()
+	for _, id := range f.huntIDs {
+		for _, query := range []string{
+			`DELETE FROM jobs WHERE payload->>'confirmation_id' IN (SELECT id::text FROM confirmations WHERE hunt_id = $1)`,
+			`DELETE FROM confirmations WHERE hunt_id = $1`,
+			`DELETE FROM lottery_results WHERE hunt_id = $1`,
+			`DELETE FROM signups WHERE hunt_id = $1`,
+			`DELETE FROM hunt_status_changes WHERE hunt_id = $1`,
+			`DELETE FROM hunts WHERE id = $1`,
+		} {
+			if _, err := f.db.ExecContext(ctx, query, id); err != nil {
+				f.t.Errorf("cleaning up hunt: %v", err)
+			}
+		}
+	}
+	for _, id := range f.userIDs {
+		if _, err := f.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id); err != nil {
+			f.t.Errorf("cleaning up user: %v", err)
+		}
+	}
+}
+
+func newTestScheduler(db *sql.DB, now time.Time) *Scheduler {
+	mailer := notify.NewMailer(&mail.Recorder{}, "https://example.org")
+	s := New(db,
+		hunt.NewService(db, lottery.NewDrawer(), mailer),
+		confirmation.NewService(db, mailer),
+	)
+	s.Interval = 10 * time.Millisecond
+	s.now = func() time.Time { return now }
+	return s
+}
+
+// countOfferJobs returns how many place-offered jobs were enqueued for
+// the hunt's confirmations, and how many of those were promotions.
+func countOfferJobs(t *testing.T, db *sql.DB, huntID uuid.UUID) (offers, promotions int) {
+	t.Helper()
+	err := db.QueryRowContext(t.Context(),
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE (payload->>'promoted')::boolean)
+		 FROM jobs
+		 WHERE kind = $1 AND payload->>'confirmation_id' IN (SELECT id::text FROM confirmations WHERE hunt_id = $2)`,
+		confirmation.JobPlaceOffered, huntID,
+	).Scan(&offers, &promotions)
+	if err != nil {
+		t.Fatalf("counting jobs: %v", err)
+	}
+	return offers, promotions
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	db := testDB(t)
+	hunts := repository.NewHuntRepository(db)
+	confirmations := repository.NewConfirmationRepository(db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	t.Run("opens scheduled drafts whose signups have started", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newFixtures(t, db)
+		scheduled := f.hunt(model.HuntStatusDraft, true, now.Add(-time.Hour), now.Add(24*time.Hour))
+		unscheduled := f.hunt(model.HuntStatusDraft, false, now.Add(-time.Hour), now.Add(24*time.Hour))
+		notYet := f.hunt(model.HuntStatusDraft, true, now.Add(time.Hour), now.Add(24*time.Hour))
+
+		g.Expect(newTestScheduler(db, now).Tick(t.Context())).To(Succeed())
+
+		for h, want := range map[uuid.UUID]model.HuntStatus{
+			scheduled.ID:   model.HuntStatusOpen,
+			unscheduled.ID: model.HuntStatusDraft,
+			notYet.ID:      model.HuntStatusDraft,
+		} {
+			got, err := hunts.GetByID(t.Context(), h)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Status).To(Equal(want))
+		}
+	})
+
+	t.Run("closes hunts whose signups have ended and draws them", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newFixtures(t, db)
+		h := f.hunt(model.HuntStatusOpen, false, now.Add(-48*time.Hour), now.Add(-time.Minute))
+		f.signup(h)
+		f.signup(h)
+
+		g.Expect(newTestScheduler(db, now).Tick(t.Context())).To(Succeed())
+
+		got, err := hunts.GetByID(t.Context(), h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got.Status).To(Equal(model.HuntStatusClosed))
+
+		results, err := repository.NewLotteryResultRepository(db).ListByHunt(t.Context(), h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(results).To(HaveLen(2))
+
+		offered, err := confirmations.ListByHunt(t.Context(), h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(offered).To(HaveLen(1))
+		g.Expect(offered[0].LotteryResultID).To(Equal(results[0].ID))
+
+		offers, promotions := countOfferJobs(t, db, h.ID)
+		g.Expect(offers).To(Equal(1))
+		g.Expect(promotions).To(BeZero())
+	})
+
+	t.Run("expires overdue confirmations and promotes alternates", func(t *testing.T) {
+		g := NewWithT(t)
+		f := newFixtures(t, db)
+		h := f.hunt(model.HuntStatusClosed, false, now.Add(-72*time.Hour), now.Add(-48*time.Hour))
+		results := repository.NewLotteryResultRepository(db)
+		entries := []model.Signup{f.signup(h), f.signup(h)}
+		var drawn []model.LotteryResult
+		for i, s := range entries {
+			res, err := results.Insert(t.Context(), model.LotteryResult{
+				HuntID: h.ID, SignupID: s.ID, Position: i + 1,
+				AuditSeed: 42, AlgorithmVersion: "test", DrawnAt: now.Add(-48 * time.Hour),
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			drawn = append(drawn, res)
+		}
+		overdue, err := confirmations.Create(t.Context(), model.Confirmation{
+			LotteryResultID: drawn[0].ID, HuntID: h.ID, UserID: entries[0].UserID, Deadline: now.Add(-time.Minute),
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(newTestScheduler(db, now).Tick(t.Context())).To(Succeed())
+
+		expired, err := confirmations.GetByID(t.Context(), overdue.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(expired.Decision).To(Equal(model.ConfirmationExpired))
+
+		offered, err := confirmations.ListByHunt(t.Context(), h.ID)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(offered).To(ContainElement(And(
+			HaveField("LotteryResultID", drawn[1].ID),
+			HaveField("Decision", model.ConfirmationPending),
+		)))
+
+		offers, promotions := countOfferJobs(t, db, h.ID)
+		g.Expect(offers).To(Equal(1))
+		g.Expect(promotions).To(Equal(1))
+	})
+}
+
+func TestScheduler_Lead(t *testing.T) {
+	db := testDB(t)
+	g := NewWithT(t)
+	f := newFixtures(t, db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	// Due for the second scheduler, whose clock runs ahead, but not for
+	// the first.
+	h := f.hunt(model.HuntStatusDraft, true, now.Add(time.Hour), now.Add(24*time.Hour))
+	first := newTestScheduler(db, now)
+	first.Interval = time.Hour
+	second := newTestScheduler(db, now.Add(2*time.Hour))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	firstDone := make(chan error, 1)
+	go func() { firstDone <- first.lead(ctx) }()
+	g.Eventually(func() bool { return leaderLockHeld(t, db) }).Should(BeTrue())
+
+	g.Expect(second.lead(t.Context())).To(Succeed())
+	got, err := repository.NewHuntRepository(db).GetByID(t.Context(), h.ID)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Status).To(Equal(model.HuntStatusDraft))
+
+	cancel()
+	g.Expect(<-firstDone).To(Succeed())
+	g.Expect(leaderLockHeld(t, db)).To(BeFalse())
+
+	// With the first scheduler gone, the second takes over.
+	ctx, cancel = context.WithCancel(t.Context())
+	secondDone := make(chan error, 1)
+	go func() { secondDone <- second.lead(ctx) }()
+	g.Eventually(func() (model.HuntStatus, error) {
+		got, err := repository.NewHuntRepository(db).GetByID(t.Context(), h.ID)
+		return got.Status, err
+	}).Should(Equal(model.HuntStatusOpen))
+	cancel()
+	g.Expect(<-secondDone).To(Succeed())
+}
+
+// leaderLockHeld reports whether any session holds the leader lock.
+// Postgres splits a bigint advisory lock key across classid and objid.
+func leaderLockHeld(t *testing.T, db *sql.DB) bool {
+	t.Helper()
+	var held bool
+	err := db.QueryRowContext(t.Context(),
+		`SELECT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory' AND granted AND classid = $1::bigint::oid AND objid = $2::bigint::oid AND objsubid = 1
+		 )`,
+		leaderLockKey>>32, leaderLockKey&0xffffffff,
+	).Scan(&held)
+	if err != nil {
+		t.Fatalf("checking leader lock: %v", err)
+	}
+	return held
+}
@@ -0,0 +1,183 @@
+// Package signup implements members entering and leaving hunt lotteries.
+package signup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/internal/repository"
+)
+
+var (
+	// ErrNotActive is returned when the member's account can't enter
+	// lotteries.
+	ErrNotActive = errors.New("membership is not active")
+	// ErrSignupsClosed is returned when the hunt isn't accepting signups.
+	ErrSignupsClosed = errors.New("signups are closed")
+	// ErrAlreadyEntered is returned when the member already has an active
+	// signup for the hunt.
+	ErrAlreadyEntered = repository.ErrAlreadyEntered
+	// ErrWithdrawn is returned by Enter when the member previously
+	// withdrew; they must re-enter explicitly with Reenter.
+	ErrWithdrawn = errors.New("previously withdrawn")
+	// ErrNotEntered is returned when the member has no active signup.
+	ErrNotEntered = errors.New("not entered")
+	// ErrQualifiersNotAttested is returned when the member hasn't attested
+	// to every one of the hunt's qualifiers.
+	ErrQualifiersNotAttested = errors.New("qualifiers not attested")
+)
+
+// Service enters members into hunt lotteries and withdraws them.
+type Service struct {
+	db *sql.DB
+}
+
+// NewService creates a signup Service.
+func NewService(db *sql.DB) *Service {
+	return &Service{db: db}
+}
+
+// Enter signs member up for a hunt, recording an eligibility snapshot
+// with the signup. attested lists the hunt qualifiers the member confirmed
+// they meet; all of them are required. The hunt row is locked so the
+// entry can't race a status change closing the hunt.
+func (s *Service) Enter(ctx context.Context, member model.User, huntID uuid.UUID, attested []string, now time.Time) (model.Signup, error) {
+	var created model.Signup
+	err := s.withOpenHunt(ctx, member, huntID, now, func(signups *repository.SignupRepository, hunt model.Hunt) error {
+		existing, err := signups.GetByUserAndHunt(ctx, member.ID, huntID)
+		switch {
+		case err == nil && existing.IsWithdrawn():
+			return ErrWithdrawn
+		case err == nil:
+			return ErrAlreadyEntered
+		case !errors.Is(err, repository.ErrNotFound):
+			return err
+		}
+		snapshot, err := takeSnapshot(ctx, signups, member, hunt, attested, now)
+		if err != nil {
+			return err
+		}
+		created, err = signups.Create(ctx, member.ID, huntID, snapshot)
+		return err
+	})
+	if err != nil {
+		return model.Signup{}, fmt.Errorf("entering hunt: %w", err)
+	}
+	return created, nil
+}
+
+// Reenter reactivates a member's withdrawn signup. The member attests to
+// the hunt's qualifiers again and the eligibility snapshot is retaken.
+func (s *Service) Reenter(ctx context.Context, member model.User, huntID uuid.UUID, attested []string, now time.Time) error {
+	err := s.withOpenHunt(ctx, member, huntID, now, func(signups *repository.SignupRepository, hunt model.Hunt) error {
+		existing, err := signups.GetByUserAndHunt(ctx, member.ID, huntID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotEntered
+		}
+		if err != nil {
+			return err
+		}
+		if !existing.IsWithdrawn() {
+			return ErrAlreadyEntered
+		}
+		snapshot, err := takeSnapshot(ctx, signups, member, hunt, attested, now)
+		if err != nil {
+			return err
+		}
+		return signups.Reenter(ctx, existing.ID, snapshot)
+	})
+	if err != nil {
+		return fmt.Errorf("re-entering hunt: %w", err)
+	}
+	return nil
+}
+
+// Withdraw takes a member out of a hunt's lottery. Withdrawal is only
+// possible while signups are open; after the draw, selected members
+// decline instead.
+func (s *Service) Withdraw(ctx context.Context, member model.User, huntID uuid.UUID, now time.Time) error {
+	err := s.withOpenHunt(ctx, member, huntID, now, func(signups *repository.SignupRepository, _ model.Hunt) error {
+		existing, err := signups.GetByUserAndHunt(ctx, member.ID, huntID)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && existing.IsWithdrawn()) {
+			return ErrNotEntered
+		}
+		if err != nil {
+			return err
+		}
+		return signups.Withdraw(ctx, existing.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("withdrawing from hunt: %w", err)
+	}
+	return nil
+}
+
+// withOpenHunt runs fn in a transaction holding the hunt's row lock, after
+// checking the member may take part and the hunt is accepting signups.
+func (s *Service) withOpenHunt(ctx context.Context, member model.User, huntID uuid.UUID, now time.Time, fn func(*repository.SignupRepository, model.Hunt) error) error {
+	if !member.IsActive() {
+		return ErrNotActive
+	}
+	return repository.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		hunt, err := repository.NewHuntRepository(tx).GetByIDForUpdate(ctx, huntID)
+		if err != nil {
+			return err
+		}
+		if !hunt.CanAcceptSignups(now) {
+			return ErrSignupsClosed
+		}
+		return fn(repository.NewSignupRepository(tx), hunt)
+	})
+}
+
+// takeSnapshot records member's eligibility for hunt as of now.
+func takeSnapshot(ctx context.Context, signups *repository.SignupRepository, member model.User, hunt model.Hunt, attested []string, now time.Time) (model.EligibilitySnapshot, error) {
+	qualifiers, err := attestedQualifiers(hunt, attested)
+	if err != nil {
+		return model.EligibilitySnapshot{}, err
+	}
+	history, err := signups.GetParticipationHistory(ctx, member.ID)
+	if err != nil {
+		return model.EligibilitySnapshot{}, err
+	}
+	snapshot := model.EligibilitySnapshot{
+		MembershipStatus:   member.MembershipStatus,
+		BranchOfService:    member.BranchOfService,
+		PriorHunts:         history.CompletedHunts,
+		MissedDraws:        history.MissedDraws,
+		AttestedQualifiers: qualifiers,
+		CapturedAt:         now.UTC(),
+	}
+	if history.LastSelectedAt.Valid {
+		last := history.LastSelectedAt.Time.UTC()
+		snapshot.LastSelectedAt = &last
+	}
+	return snapshot, nil
+}
+
+// attestedQualifiers checks that attested covers each of the hunt's
+// qualifiers and returns them in the hunt's order. Extra values are
+// ignored so a stale form can't record qualifiers the hunt doesn't have.
+func attestedQualifiers(hunt model.Hunt, attested []string) ([]string, error) {
+	seen := make(map[string]bool, len(attested))
+	for _, a := range attested {
+		seen[strings.TrimSpace(a)] = true
+	}
+	qualifiers := hunt.QualifierList()
+	for _, q := range qualifiers {
+		if !seen[q] {
+			return nil, ErrQualifiersNotAttested
+		}
+	}
+	if qualifiers == nil {
+		return []string{}, nil
+	}
+	return qualifiers, nil
+}
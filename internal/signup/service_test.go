@@ -0,0 +1,41 @@
+package signup
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestAttestedQualifiers(t *testing.T) {
+	hunt := model.Hunt{Qualifiers: sql.NullString{String: "Hunter safety card\nCan walk 5 miles", Valid: true}}
+
+	t.Run("returns the hunt's qualifiers when all are attested", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := attestedQualifiers(hunt, []string{"Can walk 5 miles", "Hunter safety card", "Something else"})
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal([]string{"Hunter safety card", "Can walk 5 miles"}))
+	})
+
+	t.Run("rejects a missing attestation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := attestedQualifiers(hunt, []string{"Hunter safety card"})
+
+		g.Expect(err).To(MatchError(ErrQualifiersNotAttested))
+	})
+
+	t.Run("returns an empty list for hunts without qualifiers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := attestedQualifiers(model.Hunt{}, nil)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(BeEmpty())
+		g.Expect(got).ToNot(BeNil())
+	})
+}
@@ -0,0 +1,19 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/web/layout"
+)
+
+// withViewer exposes the session user to page layouts so the header shows
+// the logged-in state on every page.
+func withViewer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, ok := auth.UserFromContext(r.Context()); ok {
+			r = r.WithContext(layout.WithViewer(r.Context(), layout.Viewer{Name: u.Name}))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
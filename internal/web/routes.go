@@ -3,39 +3,140 @@ package web
 import (
 	"database/sql"
 	"net/http"
+	"time"
 
+	"github.com/brian-abo/tfo-webapp/internal/auth"
+	"github.com/brian-abo/tfo-webapp/internal/config"
+	"github.com/brian-abo/tfo-webapp/internal/handler/aaradmin"
 	"github.com/brian-abo/tfo-webapp/internal/handler/about"
+	confirmationHandler "github.com/brian-abo/tfo-webapp/internal/handler/confirmation"
 	contactHandler "github.com/brian-abo/tfo-webapp/internal/handler/contact"
+	"github.com/brian-abo/tfo-webapp/internal/handler/contactadmin"
+	"github.com/brian-abo/tfo-webapp/internal/handler/errorpage"
 	"github.com/brian-abo/tfo-webapp/internal/handler/gallery"
 	"github.com/brian-abo/tfo-webapp/internal/handler/home"
+	"github.com/brian-abo/tfo-webapp/internal/handler/huntadmin"
+	huntsHandler "github.com/brian-abo/tfo-webapp/internal/handler/hunts"
+	"github.com/brian-abo/tfo-webapp/internal/handler/jobadmin"
+	membershipHandler "github.com/brian-abo/tfo-webapp/internal/handler/membership"
+	"github.com/brian-abo/tfo-webapp/internal/handler/profile"
+	"github.com/brian-abo/tfo-webapp/internal/handler/regionadmin"
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+	"github.com/brian-abo/tfo-webapp/internal/model"
 	"github.com/brian-abo/tfo-webapp/internal/repository"
 )
 
-// NewRouter creates and configures the HTTP router.
-func NewRouter(db *sql.DB) *http.ServeMux {
+// NewRouter creates and configures the HTTP router, with handlers backed
+// by services. Every request except for static assets passes through the
+// session middleware so handlers and layouts can see the logged-in user;
+// asset requests skip the session lookup.
+func NewRouter(db *sql.DB, cfg config.Config, services Services) http.Handler {
 	mux := http.NewServeMux()
 
 	// Repositories
 	contactRepo := repository.NewContactRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	huntRepo := repository.NewHuntRepository(db)
+	signupRepo := repository.NewSignupRepository(db)
+	confirmationRepo := repository.NewConfirmationRepository(db)
+	lotteryResultRepo := repository.NewLotteryResultRepository(db)
+	aarRepo := repository.NewAARRepository(db)
+	regionRepo := repository.NewRegionRepository(db)
+	jobRepo := repository.NewJobRepository(db)
 
-	// Handlers
-	contact := contactHandler.NewHandler(contactRepo)
+	// Auth
+	sessions := auth.NewSessions(sessionRepo, userRepo, cfg.Session)
+	authz := auth.NewAuthorizer(errorpage.Render)
+	staffOnly := func(h http.HandlerFunc) http.Handler { return authz.RequireRole(model.RoleStaff, h) }
 
-	// Static assets
-	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static"))))
+	// Handlers
+	contactForm := contactHandler.NewHandler(services.Contact, regionRepo)
+	login := auth.NewHandler(auth.NewProvider(cfg.OAuth), userRepo, sessions)
+	members := membershipHandler.NewHandler(userRepo, services.Membership)
+	profiles := profile.NewHandler(userRepo, signupRepo)
+	hunts := huntsHandler.NewHandler(huntRepo, signupRepo, confirmationRepo, aarRepo, regionRepo, services.Signup, lottery.NewVerifier(db))
+	reports := aaradmin.NewHandler(huntRepo, userRepo, aarRepo, services.AAR)
+	confirmations := confirmationHandler.NewHandler(confirmationRepo, huntRepo, services.Confirmation)
+	inbox := contactadmin.NewHandler(contactRepo, userRepo, regionRepo, services.Contact)
+	regionEditor := regionadmin.NewHandler(regionRepo, services.Region)
+	jobQueue := jobadmin.NewHandler(jobRepo)
+	huntConsole := huntadmin.NewHandler(huntRepo, signupRepo, lotteryResultRepo, confirmationRepo, services.Hunt, services.Confirmation, time.Local)
 
 	// Home
 	mux.HandleFunc("GET /", home.Index)
 
+	// Auth
+	mux.HandleFunc("GET /login", login.Login)
+	mux.HandleFunc("GET /auth/facebook/callback", login.Callback)
+	mux.HandleFunc("POST /logout", sessions.Logout)
+
+	// Onboarding and profile
+	mux.Handle("GET /onboarding", authz.RequireLogin(http.HandlerFunc(profiles.Onboarding)))
+	mux.Handle("POST /onboarding", authz.RequireLogin(http.HandlerFunc(profiles.CompleteOnboarding)))
+	mux.Handle("GET /profile", authz.RequireLogin(http.HandlerFunc(profiles.Edit)))
+	mux.Handle("POST /profile", authz.RequireLogin(http.HandlerFunc(profiles.Update)))
+
+	// Hunts
+	mux.HandleFunc("GET /hunts", hunts.Index)
+	mux.HandleFunc("GET /hunts/{id}", hunts.Show)
+	mux.HandleFunc("GET /hunts/{id}/lottery/verify", hunts.Verify)
+	mux.HandleFunc("GET /hunts/{id}/report", hunts.Report)
+	mux.Handle("POST /hunts/{id}/signup", authz.RequireMember(http.HandlerFunc(hunts.Enter)))
+	mux.Handle("POST /hunts/{id}/reenter", authz.RequireMember(http.HandlerFunc(hunts.Reenter)))
+	mux.Handle("POST /hunts/{id}/withdraw", authz.RequireMember(http.HandlerFunc(hunts.Withdraw)))
+
+	// Confirmations
+	mux.Handle("GET /confirmations/{id}", authz.RequireMember(http.HandlerFunc(confirmations.Show)))
+	mux.Handle("POST /confirmations/{id}", authz.RequireMember(http.HandlerFunc(confirmations.Respond)))
+	mux.Handle("POST /confirmations/{id}/withdraw", authz.RequireMember(http.HandlerFunc(confirmations.Withdraw)))
+
 	// About
 	mux.HandleFunc("GET /about", about.Index)
 
 	// Contact
-	mux.HandleFunc("GET /contact", contact.Index)
-	mux.HandleFunc("POST /contact", contact.Submit)
+	mux.HandleFunc("GET /contact", contactForm.Index)
+	mux.HandleFunc("POST /contact", contactForm.Submit)
 
 	// Gallery
 	mux.HandleFunc("GET /gallery", gallery.Index)
 
-	return mux
+	// Admin: membership review
+	mux.Handle("GET /admin/members", staffOnly(members.Queue))
+	mux.Handle("POST /admin/members/{id}/decision", staffOnly(members.Decide))
+
+	// Admin: contact inbox
+	mux.Handle("GET /admin/contact", staffOnly(inbox.Inbox))
+	mux.Handle("GET /admin/contact/{id}", staffOnly(inbox.Show))
+	mux.Handle("POST /admin/contact/{id}/triage", staffOnly(inbox.Triage))
+	mux.Handle("POST /admin/contact/{id}/notes", staffOnly(inbox.AddNote))
+
+	// Admin: regions
+	mux.Handle("GET /admin/regions", staffOnly(regionEditor.Index))
+	mux.Handle("GET /admin/regions/new", staffOnly(regionEditor.New))
+	mux.Handle("POST /admin/regions", staffOnly(regionEditor.Create))
+	mux.Handle("GET /admin/regions/{id}", staffOnly(regionEditor.Edit))
+	mux.Handle("POST /admin/regions/{id}", staffOnly(regionEditor.Update))
+
+	// Admin: background jobs
+	mux.Handle("GET /admin/jobs", staffOnly(jobQueue.Index))
+	mux.Handle("POST /admin/jobs/{id}/retry", staffOnly(jobQueue.Retry))
+
+	// Admin: hunt management
+	mux.Handle("GET /admin/hunts", staffOnly(huntConsole.Index))
+	mux.Handle("GET /admin/hunts/new", staffOnly(huntConsole.New))
+	mux.Handle("POST /admin/hunts", staffOnly(huntConsole.Create))
+	mux.Handle("GET /admin/hunts/{id}", staffOnly(huntConsole.Edit))
+	mux.Handle("POST /admin/hunts/{id}", staffOnly(huntConsole.Update))
+	mux.Handle("POST /admin/hunts/{id}/status", staffOnly(huntConsole.ChangeStatus))
+	mux.Handle("GET /admin/hunts/{id}/entries", staffOnly(huntConsole.Entries))
+	mux.Handle("GET /admin/hunts/{id}/roster", staffOnly(huntConsole.Roster))
+	mux.Handle("POST /admin/hunts/{id}/roster/promote", staffOnly(huntConsole.Promote))
+	mux.Handle("GET /admin/hunts/{id}/aar", staffOnly(reports.Edit))
+	mux.Handle("POST /admin/hunts/{id}/aar", staffOnly(reports.Save))
+
+	root := http.NewServeMux()
+	root.Handle("GET /static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static"))))
+	root.Handle("/", sessions.Middleware(withViewer(mux)))
+	return root
 }
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"database/sql"
+
+	"github.com/brian-abo/tfo-webapp/internal/aar"
+	"github.com/brian-abo/tfo-webapp/internal/confirmation"
+	"github.com/brian-abo/tfo-webapp/internal/contact"
+	"github.com/brian-abo/tfo-webapp/internal/hunt"
+	"github.com/brian-abo/tfo-webapp/internal/jobs"
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+	"github.com/brian-abo/tfo-webapp/internal/mail"
+	"github.com/brian-abo/tfo-webapp/internal/membership"
+	"github.com/brian-abo/tfo-webapp/internal/notify"
+	"github.com/brian-abo/tfo-webapp/internal/region"
+	"github.com/brian-abo/tfo-webapp/internal/signup"
+)
+
+// Services holds the application services. They are built once and
+// shared by the router, the job worker and the scheduler.
+type Services struct {
+	Membership   *membership.Service
+	Hunt         *hunt.Service
+	Signup       *signup.Service
+	Confirmation *confirmation.Service
+	AAR          *aar.Service
+	Contact      *contact.Service
+	Region       *region.Service
+}
+
+// NewServices creates the services. Notifications are emailed through
+// sender, with links to siteURL.
+func NewServices(db *sql.DB, sender mail.Sender, siteURL string) Services {
+	mailer := notify.NewMailer(sender, siteURL)
+	return Services{
+		Membership:   membership.NewService(db, mailer),
+		Hunt:         hunt.NewService(db, lottery.NewDrawer(), mailer),
+		Signup:       signup.NewService(db),
+		Confirmation: confirmation.NewService(db, mailer),
+		AAR:          aar.NewService(db),
+		Contact:      contact.NewService(db, mailer),
+		Region:       region.NewService(db),
+	}
+}
+
+// RegisterJobs registers the handlers for the jobs the services enqueue.
+func (s Services) RegisterJobs(w *jobs.Worker) {
+	s.Membership.RegisterJobs(w)
+	s.Hunt.RegisterJobs(w)
+	s.Confirmation.RegisterJobs(w)
+	s.Contact.RegisterJobs(w)
+}
@@ -10,6 +10,7 @@ type NavItem struct {
 func DefaultNavItems() []NavItem {
 	return []NavItem{
 		{Label: "Home", Href: "/"},
+		{Label: "Hunts", Href: "/hunts"},
 		{Label: "About", Href: "/about"},
 		{Label: "Gallery", Href: "/gallery"},
 		{Label: "Contact", Href: "/contact"},
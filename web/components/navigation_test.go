@@ -12,10 +12,12 @@ func TestDefaultNavItems(t *testing.T) {
 
 		items := DefaultNavItems()
 
-		g.Expect(items).To(HaveLen(4))
+		g.Expect(items).To(HaveLen(5))
 		g.Expect(items[0].Label).To(Equal("Home"))
 		g.Expect(items[0].Href).To(Equal("/"))
-		g.Expect(items[1].Label).To(Equal("About"))
-		g.Expect(items[3].Label).To(Equal("Contact"))
+		g.Expect(items[1].Label).To(Equal("Hunts"))
+		g.Expect(items[1].Href).To(Equal("/hunts"))
+		g.Expect(items[2].Label).To(Equal("About"))
+		g.Expect(items[4].Label).To(Equal("Contact"))
 	})
 }
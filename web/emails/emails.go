@@ -0,0 +1,181 @@
+// Package emails renders the site's outgoing messages. Each has an HTML
+// template and a plain-text alternative built from the same props.
+package emails
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/web/layout"
+)
+
+// ContactMessageProps contains data for a contact form message forwarded
+// to a regional leader.
+type ContactMessageProps struct {
+	SiteURL     string
+	LeaderName  string
+	SenderName  string
+	SenderEmail string
+	Message     string
+	// InboxURL is the staff page for the submission.
+	InboxURL string
+}
+
+// Subject returns the message's subject line.
+func (p ContactMessageProps) Subject() string {
+	return "New message from " + p.SenderName
+}
+
+// ContactMessageText returns the plain-text alternative of ContactMessage.
+func ContactMessageText(p ContactMessageProps) string {
+	body := fmt.Sprintf("Hi %s,\n\n%s <%s> sent a message to your region through the contact page:\n\n%s\n\nReply to this email to answer them, or see the message in the inbox:\n%s",
+		p.LeaderName, p.SenderName, p.SenderEmail, quote(p.Message), p.InboxURL)
+	return layout.EmailText(layout.EmailProps{Title: p.Subject(), SiteURL: p.SiteURL}, body)
+}
+
+// MembershipDecisionProps contains data for telling a member their
+// membership status changed.
+type MembershipDecisionProps struct {
+	SiteURL string
+	Name    string
+	Status  model.MembershipStatus
+	Reason  string
+}
+
+// Subject returns the message's subject line.
+func (p MembershipDecisionProps) Subject() string {
+	switch p.Status {
+	case model.MembershipActive:
+		return "Your membership is active"
+	case model.MembershipSuspended:
+		return "Your membership has been suspended"
+	case model.MembershipInactive:
+		return "Your membership is inactive"
+	default:
+		return "Your membership is under review"
+	}
+}
+
+// Summary returns the sentence explaining what the new status means.
+func (p MembershipDecisionProps) Summary() string {
+	switch p.Status {
+	case model.MembershipActive:
+		return "Welcome aboard. You can now enter hunt lotteries."
+	case model.MembershipSuspended:
+		return "You can't enter hunt lotteries while your membership is suspended."
+	case model.MembershipInactive:
+		return "You can't enter hunt lotteries until your membership is active again."
+	default:
+		return "Our staff will review your membership and let you know."
+	}
+}
+
+// LinkURL returns where the message points the member next.
+func (p MembershipDecisionProps) LinkURL() string {
+	if p.Status == model.MembershipActive {
+		return p.SiteURL + "/hunts"
+	}
+	return p.SiteURL + "/profile"
+}
+
+// LinkLabel returns the text of the link to LinkURL.
+func (p MembershipDecisionProps) LinkLabel() string {
+	if p.Status == model.MembershipActive {
+		return "Browse hunts"
+	}
+	return "View your profile"
+}
+
+// MembershipDecisionText returns the plain-text alternative of
+// MembershipDecision.
+func MembershipDecisionText(p MembershipDecisionProps) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\n%s", p.Name, p.Summary())
+	if p.Reason != "" {
+		fmt.Fprintf(&b, "\n\nReason: %s", p.Reason)
+	}
+	fmt.Fprintf(&b, "\n\n%s: %s", p.LinkLabel(), p.LinkURL())
+	return layout.EmailText(layout.EmailProps{Title: p.Subject(), SiteURL: p.SiteURL}, b.String())
+}
+
+// HuntCancelledProps contains data for telling an entrant a hunt was
+// cancelled.
+type HuntCancelledProps struct {
+	SiteURL   string
+	Name      string
+	HuntTitle string
+	HuntDate  time.Time
+	Reason    string
+	HuntURL   string
+}
+
+// Subject returns the message's subject line.
+func (p HuntCancelledProps) Subject() string {
+	return p.HuntTitle + " has been cancelled"
+}
+
+// DateLabel formats the hunt's date.
+func (p HuntCancelledProps) DateLabel() string {
+	return p.HuntDate.Format("Monday, January 2, 2006")
+}
+
+// HuntCancelledText returns the plain-text alternative of HuntCancelled.
+func HuntCancelledText(p HuntCancelledProps) string {
+	body := fmt.Sprintf("Hi %s,\n\nWe're sorry: %s on %s has been cancelled.\n\nReason: %s\n\n%s",
+		p.Name, p.HuntTitle, p.DateLabel(), p.Reason, p.HuntURL)
+	return layout.EmailText(layout.EmailProps{Title: p.Subject(), SiteURL: p.SiteURL}, body)
+}
+
+// PlaceOfferedProps contains data for offering a member a place on a
+// hunt, either drawn in its lottery or promoted from the alternates.
+type PlaceOfferedProps struct {
+	SiteURL   string
+	Name      string
+	HuntTitle string
+	HuntDate  time.Time
+	Deadline  time.Time
+	// Promoted is true for an alternate offered a place someone gave up.
+	Promoted bool
+	// ConfirmURL is where the member accepts or declines.
+	ConfirmURL string
+}
+
+// Subject returns the message's subject line.
+func (p PlaceOfferedProps) Subject() string {
+	if p.Promoted {
+		return "A place opened up on " + p.HuntTitle
+	}
+	return "You've been selected for " + p.HuntTitle
+}
+
+// Summary returns the sentence explaining how the member got the place.
+func (p PlaceOfferedProps) Summary() string {
+	date := p.HuntDate.Format("Monday, January 2, 2006")
+	if p.Promoted {
+		return fmt.Sprintf("A place has opened up on %s on %s, and as the next alternate it's yours if you want it.", p.HuntTitle, date)
+	}
+	return fmt.Sprintf("Congratulations! You were drawn in the lottery for %s on %s.", p.HuntTitle, date)
+}
+
+// DeadlineLabel formats the response deadline.
+func (p PlaceOfferedProps) DeadlineLabel() string {
+	return p.Deadline.Format("Monday, January 2 at 3:04 PM MST")
+}
+
+// PlaceOfferedText returns the plain-text alternative of PlaceOffered.
+func PlaceOfferedText(p PlaceOfferedProps) string {
+	body := fmt.Sprintf("Hi %s,\n\n%s\n\nPlease accept or decline by %s. If you haven't answered by then, the place goes to the next alternate.\n\nAccept or decline: %s",
+		p.Name, p.Summary(), p.DeadlineLabel(), p.ConfirmURL)
+	return layout.EmailText(layout.EmailProps{Title: p.Subject(), SiteURL: p.SiteURL}, body)
+}
+
+// quote prefixes each line of s with "> ".
+func quote(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, l := range lines {
+		lines[i] = "> " + strings.TrimRight(l, "\r")
+	}
+	return strings.Join(lines, "\n")
+}
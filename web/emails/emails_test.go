@@ -0,0 +1,112 @@
+package emails
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestContactMessageText(t *testing.T) {
+	t.Run("quotes the message and links to the inbox", func(t *testing.T) {
+		g := NewWithT(t)
+
+		text := ContactMessageText(ContactMessageProps{
+			SiteURL:     "https://example.org",
+			LeaderName:  "James Wilson",
+			SenderName:  "Jane Doe",
+			SenderEmail: "jane@example.com",
+			Message:     "Hello\nAre there hunts in Iowa?\n",
+			InboxURL:    "https://example.org/admin/contact/1",
+		})
+
+		g.Expect(text).To(HavePrefix("New message from Jane Doe\n\nHi James Wilson,"))
+		g.Expect(text).To(ContainSubstring("Jane Doe <jane@example.com> sent a message"))
+		g.Expect(text).To(ContainSubstring("> Hello\n> Are there hunts in Iowa?\n\n"))
+		g.Expect(text).To(ContainSubstring("https://example.org/admin/contact/1"))
+	})
+}
+
+func TestMembershipDecisionProps(t *testing.T) {
+	t.Run("welcomes approved members to the hunts", func(t *testing.T) {
+		g := NewWithT(t)
+		p := MembershipDecisionProps{SiteURL: "https://example.org", Name: "Jane", Status: model.MembershipActive}
+
+		g.Expect(p.Subject()).To(Equal("Your membership is active"))
+		g.Expect(p.LinkURL()).To(Equal("https://example.org/hunts"))
+		g.Expect(MembershipDecisionText(p)).To(ContainSubstring("Browse hunts: https://example.org/hunts"))
+	})
+
+	t.Run("gives the reason for a suspension", func(t *testing.T) {
+		g := NewWithT(t)
+		p := MembershipDecisionProps{SiteURL: "https://example.org", Name: "Jane", Status: model.MembershipSuspended, Reason: "Missed two hunts"}
+
+		text := MembershipDecisionText(p)
+
+		g.Expect(p.Subject()).To(Equal("Your membership has been suspended"))
+		g.Expect(text).To(ContainSubstring("Reason: Missed two hunts"))
+		g.Expect(text).To(ContainSubstring("https://example.org/profile"))
+	})
+
+	t.Run("leaves out an empty reason", func(t *testing.T) {
+		g := NewWithT(t)
+		p := MembershipDecisionProps{Name: "Jane", Status: model.MembershipInactive}
+
+		g.Expect(MembershipDecisionText(p)).ToNot(ContainSubstring("Reason:"))
+	})
+}
+
+func TestHuntCancelledText(t *testing.T) {
+	t.Run("names the hunt, its date and the reason", func(t *testing.T) {
+		g := NewWithT(t)
+
+		text := HuntCancelledText(HuntCancelledProps{
+			SiteURL:   "https://example.org",
+			Name:      "Jane",
+			HuntTitle: "Iowa Whitetail",
+			HuntDate:  time.Date(2030, 11, 2, 6, 0, 0, 0, time.UTC),
+			Reason:    "Flooding",
+			HuntURL:   "https://example.org/hunts/1",
+		})
+
+		g.Expect(text).To(HavePrefix("Iowa Whitetail has been cancelled\n\n"))
+		g.Expect(text).To(ContainSubstring("Iowa Whitetail on Saturday, November 2, 2030 has been cancelled"))
+		g.Expect(text).To(ContainSubstring("Reason: Flooding"))
+		g.Expect(text).To(ContainSubstring("https://example.org/hunts/1"))
+	})
+}
+
+func TestPlaceOfferedText(t *testing.T) {
+	p := PlaceOfferedProps{
+		SiteURL:    "https://example.org",
+		Name:       "Jane",
+		HuntTitle:  "Iowa Whitetail",
+		HuntDate:   time.Date(2030, 11, 2, 6, 0, 0, 0, time.UTC),
+		Deadline:   time.Date(2030, 10, 4, 17, 0, 0, 0, time.UTC),
+		ConfirmURL: "https://example.org/confirmations/1",
+	}
+
+	t.Run("congratulates members drawn in the lottery", func(t *testing.T) {
+		g := NewWithT(t)
+
+		text := PlaceOfferedText(p)
+
+		g.Expect(text).To(HavePrefix("You've been selected for Iowa Whitetail\n\n"))
+		g.Expect(text).To(ContainSubstring("drawn in the lottery for Iowa Whitetail on Saturday, November 2, 2030"))
+		g.Expect(text).To(ContainSubstring("by Friday, October 4 at 5:00 PM UTC"))
+		g.Expect(text).To(ContainSubstring("https://example.org/confirmations/1"))
+	})
+
+	t.Run("tells promoted alternates a place opened up", func(t *testing.T) {
+		g := NewWithT(t)
+		p := p
+		p.Promoted = true
+
+		text := PlaceOfferedText(p)
+
+		g.Expect(text).To(HavePrefix("A place opened up on Iowa Whitetail\n\n"))
+		g.Expect(text).To(ContainSubstring("as the next alternate"))
+	})
+}
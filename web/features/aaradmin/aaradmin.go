@@ -0,0 +1,119 @@
+// Package aaradmin renders the staff editor for hunt after-action reports.
+package aaradmin
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// URL returns the after-action report editor for a hunt; the form posts
+// back to it.
+func URL(huntID uuid.UUID) string {
+	return "/admin/hunts/" + huntID.String() + "/aar"
+}
+
+// Errors maps form field names to validation messages.
+type Errors map[string]string
+
+// Form holds a submitted report as entered.
+type Form struct {
+	// ReportID is empty until the report is first saved.
+	ReportID     string
+	Description  string
+	ImageURLs    string // one URL per line
+	Participants []string
+}
+
+// FormFromReport pre-fills the form with a saved report and its tagged
+// participants.
+func FormFromReport(a model.HuntAfterActionReport, participants []model.User) Form {
+	f := Form{
+		ReportID:    a.ID.String(),
+		Description: a.Description,
+		ImageURLs:   strings.Join(a.ImageURLs, "\n"),
+	}
+	for _, u := range participants {
+		f.Participants = append(f.Participants, u.ID.String())
+	}
+	return f
+}
+
+// IsNew returns true if the report hasn't been saved yet.
+func (f Form) IsNew() bool {
+	return f.ReportID == ""
+}
+
+// Tagged returns true if the user is ticked as a participant.
+func (f Form) Tagged(id uuid.UUID) bool {
+	for _, p := range f.Participants {
+		if p == id.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the form and returns the report it describes for
+// huntID and the tagged user IDs. The results are only meaningful when
+// errs is empty.
+func (f Form) Validate(huntID uuid.UUID) (model.HuntAfterActionReport, []uuid.UUID, Errors) {
+	errs := Errors{}
+	a := model.HuntAfterActionReport{
+		HuntID:      huntID,
+		Description: strings.TrimSpace(f.Description),
+	}
+	if !f.IsNew() {
+		id, err := uuid.Parse(f.ReportID)
+		if err != nil {
+			errs["report_id"] = "This report couldn't be found; reload the page"
+		}
+		a.ID = id
+	}
+	if a.Description == "" {
+		errs["description"] = "Write a few words about the hunt"
+	}
+
+	for _, line := range strings.Split(f.ImageURLs, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if u, err := url.Parse(line); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			errs["image_urls"] = "Each image must be a full http(s) URL on its own line"
+			continue
+		}
+		a.ImageURLs = append(a.ImageURLs, line)
+	}
+
+	var participants []uuid.UUID
+	for _, p := range f.Participants {
+		id, err := uuid.Parse(p)
+		if err != nil {
+			errs["participants"] = "Choose participants from the confirmed roster"
+			continue
+		}
+		participants = append(participants, id)
+	}
+	return a, participants, errs
+}
+
+// Props contains data for the after-action report editor.
+type Props struct {
+	Hunt model.Hunt
+	// Roster is the hunt's confirmed roster, who may be tagged.
+	Roster  []model.User
+	Form    Form
+	Errors  Errors
+	Message string
+	Error   string
+}
+
+// CanEdit returns true if the hunt is completed, so a report may be
+// written.
+func (p Props) CanEdit() bool {
+	return p.Hunt.Status == model.HuntStatusCompleted
+}
@@ -0,0 +1,74 @@
+package aaradmin
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestForm_Validate(t *testing.T) {
+	huntID := uuid.New()
+
+	t.Run("accepts a new report", func(t *testing.T) {
+		g := NewWithT(t)
+		member := uuid.New()
+		f := Form{
+			Description:  "  Clear skies.\n\nTwo bucks taken.  ",
+			ImageURLs:    "https://example.com/a.jpg\n\nhttps://example.com/b.jpg",
+			Participants: []string{member.String()},
+		}
+
+		a, participants, errs := f.Validate(huntID)
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(a.HuntID).To(Equal(huntID))
+		g.Expect(a.ID).To(Equal(uuid.Nil))
+		g.Expect(a.Description).To(Equal("Clear skies.\n\nTwo bucks taken."))
+		g.Expect(a.ImageURLs).To(HaveLen(2))
+		g.Expect(participants).To(Equal([]uuid.UUID{member}))
+	})
+
+	t.Run("keeps the id of a saved report", func(t *testing.T) {
+		g := NewWithT(t)
+		id := uuid.New()
+
+		a, _, errs := Form{ReportID: id.String(), Description: "Done"}.Validate(huntID)
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(a.ID).To(Equal(id))
+	})
+
+	t.Run("rejects missing narrative, bad images and bad participants", func(t *testing.T) {
+		g := NewWithT(t)
+		f := Form{ImageURLs: "/static/a.jpg", Participants: []string{"nope"}}
+
+		_, _, errs := f.Validate(huntID)
+
+		g.Expect(errs).To(HaveKey("description"))
+		g.Expect(errs).To(HaveKey("image_urls"))
+		g.Expect(errs).To(HaveKey("participants"))
+	})
+}
+
+func TestFormFromReport(t *testing.T) {
+	g := NewWithT(t)
+	tagged := model.User{ID: uuid.New()}
+	a := model.HuntAfterActionReport{ID: uuid.New(), Description: "Done", ImageURLs: []string{"https://example.com/a.jpg"}}
+
+	f := FormFromReport(a, []model.User{tagged})
+
+	g.Expect(f.IsNew()).To(BeFalse())
+	g.Expect(f.ImageURLs).To(Equal("https://example.com/a.jpg"))
+	g.Expect(f.Tagged(tagged.ID)).To(BeTrue())
+	g.Expect(f.Tagged(uuid.New())).To(BeFalse())
+}
+
+func TestProps_CanEdit(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Props{Hunt: model.Hunt{Status: model.HuntStatusCompleted}}.CanEdit()).To(BeTrue())
+	g.Expect(Props{Hunt: model.Hunt{Status: model.HuntStatusClosed}}.CanEdit()).To(BeFalse())
+}
@@ -0,0 +1,73 @@
+package confirmation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// Props contains data for a member's selection confirmation page.
+type Props struct {
+	Confirmation model.Confirmation
+	Hunt         model.Hunt
+	Now          time.Time
+	// Reason is the decline reason as entered, kept when re-rendering.
+	Reason  string
+	Message string
+	Error   string
+}
+
+// CanRespond returns true if the member may still accept or decline.
+// Selections are only answered while the hunt is closed.
+func (p Props) CanRespond() bool {
+	return p.Hunt.Status == model.HuntStatusClosed && p.Confirmation.CanRespond(p.Now)
+}
+
+// StatusLabel describes where the confirmation stands, noting when the
+// hunt was cancelled.
+func (p Props) StatusLabel() string {
+	if p.Hunt.Status == model.HuntStatusCancelled {
+		return "This hunt has been cancelled, so there's nothing to confirm."
+	}
+	return StatusLabel(p.Confirmation, p.Now)
+}
+
+// CanWithdraw returns true if the member may give up a place they
+// accepted. Places are final once the hunt is completed.
+func (p Props) CanWithdraw() bool {
+	return p.Confirmation.IsFinal() && p.Hunt.Status == model.HuntStatusClosed
+}
+
+// URL returns the page where a member answers a confirmation; the form
+// posts back to it.
+func URL(id uuid.UUID) string {
+	return "/confirmations/" + id.String()
+}
+
+// WithdrawURL returns the endpoint for giving up an accepted place.
+func WithdrawURL(id uuid.UUID) string {
+	return URL(id) + "/withdraw"
+}
+
+// StatusLabel describes where a confirmation stands.
+func StatusLabel(c model.Confirmation, now time.Time) string {
+	switch c.Decision {
+	case model.ConfirmationAccepted:
+		return "You accepted your place on this hunt."
+	case model.ConfirmationDeclined:
+		return "You declined your place on this hunt."
+	case model.ConfirmationExpired:
+		return "The deadline passed before you responded, so your place was released."
+	}
+	if !c.CanRespond(now) {
+		return "The deadline to respond has passed."
+	}
+	return "You've been selected! Please accept or decline your place by " + DeadlineLabel(c.Deadline) + "."
+}
+
+// DeadlineLabel formats a response deadline.
+func DeadlineLabel(t time.Time) string {
+	return t.Format("Mon, Jan 2 at 3:04 PM MST")
+}
@@ -0,0 +1,89 @@
+package confirmation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestStatusLabel(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("asks pending members to respond", func(t *testing.T) {
+		g := NewWithT(t)
+		c := model.Confirmation{Decision: model.ConfirmationPending, Deadline: deadline}
+
+		g.Expect(StatusLabel(c, deadline.Add(-time.Hour))).To(Equal(
+			"You've been selected! Please accept or decline your place by Sun, Mar 1 at 12:00 PM UTC."))
+	})
+
+	t.Run("notes a passed deadline", func(t *testing.T) {
+		g := NewWithT(t)
+		c := model.Confirmation{Decision: model.ConfirmationPending, Deadline: deadline}
+
+		g.Expect(StatusLabel(c, deadline)).To(ContainSubstring("deadline to respond has passed"))
+	})
+
+	t.Run("describes answers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(StatusLabel(model.Confirmation{Decision: model.ConfirmationAccepted}, deadline)).To(ContainSubstring("accepted"))
+		g.Expect(StatusLabel(model.Confirmation{Decision: model.ConfirmationExpired}, deadline)).To(ContainSubstring("released"))
+	})
+}
+
+func TestProps(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	props := Props{
+		Confirmation: model.Confirmation{Decision: model.ConfirmationPending, Deadline: deadline},
+		Hunt:         model.Hunt{Status: model.HuntStatusClosed},
+		Now:          deadline.Add(-time.Hour),
+	}
+
+	t.Run("offers a response while the hunt is closed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(props.CanRespond()).To(BeTrue())
+		g.Expect(props.StatusLabel()).To(ContainSubstring("You've been selected!"))
+	})
+
+	t.Run("offers no response once the hunt is cancelled", func(t *testing.T) {
+		g := NewWithT(t)
+		props := props
+		props.Hunt.Status = model.HuntStatusCancelled
+
+		g.Expect(props.CanRespond()).To(BeFalse())
+		g.Expect(props.StatusLabel()).To(ContainSubstring("cancelled"))
+	})
+}
+
+func TestURL(t *testing.T) {
+	g := NewWithT(t)
+	id := uuid.MustParse("11111111-2222-3333-4444-555555555555")
+
+	g.Expect(URL(id)).To(Equal("/confirmations/11111111-2222-3333-4444-555555555555"))
+}
+
+func TestProps_CanWithdraw(t *testing.T) {
+	accepted := model.Confirmation{Decision: model.ConfirmationAccepted}
+
+	t.Run("allows accepted places before the hunt is completed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Props{Confirmation: accepted, Hunt: model.Hunt{Status: model.HuntStatusClosed}}.CanWithdraw()).To(BeTrue())
+	})
+
+	t.Run("refuses completed hunts and unaccepted places", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Props{Confirmation: accepted, Hunt: model.Hunt{Status: model.HuntStatusCompleted}}.CanWithdraw()).To(BeFalse())
+		g.Expect(Props{
+			Confirmation: model.Confirmation{Decision: model.ConfirmationPending},
+			Hunt:         model.Hunt{Status: model.HuntStatusClosed},
+		}.CanWithdraw()).To(BeFalse())
+	})
+}
@@ -1,5 +1,11 @@
 package contact
 
+import (
+	"encoding/json"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
 // RegionalLeader represents a regional director/leader.
 type RegionalLeader struct {
 	ID     string
@@ -8,42 +14,62 @@ type RegionalLeader struct {
 	Email  string
 }
 
-// DefaultRegionalLeaders returns placeholder regional leaders.
-func DefaultRegionalLeaders() []RegionalLeader {
-	return []RegionalLeader{
-		{
-			ID:     "west-coast",
-			Name:   "David Lee",
-			Region: "West Coast",
-			Email:  "westcoast@thefallenoutdoors.org",
-		},
-		{
-			ID:     "midwest",
-			Name:   "James Wilson",
-			Region: "Midwest",
-			Email:  "midwest@thefallenoutdoors.org",
-		},
-		{
-			ID:     "east-coast",
-			Name:   "Tom Anderson",
-			Region: "East Coast",
-			Email:  "eastcoast@thefallenoutdoors.org",
-		},
-		{
-			ID:     "southern",
-			Name:   "Maria Garcia",
-			Region: "Southern",
-			Email:  "southern@thefallenoutdoors.org",
-		},
+// Leaders pairs each region with its leader, in region order. Regions
+// without a leader are left out.
+func Leaders(regions []model.Region, leaders []model.RegionalLeader) []RegionalLeader {
+	byRegion := make(map[string]model.RegionalLeader, len(leaders))
+	for _, l := range leaders {
+		byRegion[l.RegionID] = l
+	}
+	var out []RegionalLeader
+	for _, r := range regions {
+		if l, ok := byRegion[r.ID]; ok {
+			out = append(out, RegionalLeader{ID: r.ID, Name: l.Name, Region: r.Name, Email: l.Email})
+		}
 	}
+	return out
 }
 
-// LeadersByID returns a map of leaders keyed by their region ID.
-func LeadersByID() map[string]RegionalLeader {
-	leaders := DefaultRegionalLeaders()
-	result := make(map[string]RegionalLeader, len(leaders))
+// PageProps contains data for the contact page.
+type PageProps struct {
+	Regions []model.Region
+	Leaders []RegionalLeader
+}
+
+// leaderData is a leader as the page's Alpine component sees it.
+type leaderData struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+	Email  string `json:"email"`
+}
+
+// PageData returns the page's Alpine component. The leaders it shows when
+// a region is clicked are rendered from the same data as the leader list,
+// so the two can't disagree.
+func PageData(leaders []RegionalLeader) string {
+	return `{
+		selectedRegion: null,
+		selectedDirector: null,
+		leaders: ` + leadersJSON(leaders) + `,
+		selectRegion(regionId) {
+			this.selectedRegion = regionId;
+			this.selectedDirector = this.leaders[regionId];
+		},
+		sendMessage() {
+			document.getElementById('contact-form').scrollIntoView({ behavior: 'smooth' });
+		}
+	}`
+}
+
+// leadersJSON encodes leaders as a JSON object keyed by region ID.
+func leadersJSON(leaders []RegionalLeader) string {
+	data := make(map[string]leaderData, len(leaders))
 	for _, l := range leaders {
-		result[l.ID] = l
+		data[l.ID] = leaderData{Name: l.Name, Region: l.Region, Email: l.Email}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "{}"
 	}
-	return result
+	return string(b)
 }
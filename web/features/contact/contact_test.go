@@ -4,63 +4,118 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
 )
 
-func TestDefaultRegionalLeaders(t *testing.T) {
-	t.Run("returns placeholder regional leaders", func(t *testing.T) {
+func testRegions() []model.Region {
+	return []model.Region{
+		{ID: "west-coast", Name: "West Coast", Color: "#009FDE", States: []string{"CA", "OR", "WA"}},
+		{ID: "midwest", Name: "Midwest", Color: "#81D742", States: []string{"IA", "MN"}},
+	}
+}
+
+func TestLeaders(t *testing.T) {
+	t.Run("pairs leaders with their regions in region order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		leaders := Leaders(testRegions(), []model.RegionalLeader{
+			{RegionID: "midwest", Name: "James Wilson", Email: "midwest@thefallenoutdoors.org"},
+			{RegionID: "west-coast", Name: "David Lee", Email: "westcoast@thefallenoutdoors.org"},
+		})
+
+		g.Expect(leaders).To(Equal([]RegionalLeader{
+			{ID: "west-coast", Name: "David Lee", Region: "West Coast", Email: "westcoast@thefallenoutdoors.org"},
+			{ID: "midwest", Name: "James Wilson", Region: "Midwest", Email: "midwest@thefallenoutdoors.org"},
+		}))
+	})
+
+	t.Run("leaves out regions without a leader", func(t *testing.T) {
 		g := NewWithT(t)
 
-		leaders := DefaultRegionalLeaders()
+		leaders := Leaders(testRegions(), []model.RegionalLeader{
+			{RegionID: "midwest", Name: "James Wilson", Email: "midwest@thefallenoutdoors.org"},
+		})
 
-		g.Expect(leaders).To(HaveLen(4))
-		g.Expect(leaders[0].Name).ToNot(BeEmpty())
-		g.Expect(leaders[0].Region).ToNot(BeEmpty())
-		g.Expect(leaders[0].Email).ToNot(BeEmpty())
+		g.Expect(leaders).To(HaveLen(1))
+		g.Expect(leaders[0].ID).To(Equal("midwest"))
 	})
 }
 
-func TestUSRegions(t *testing.T) {
-	t.Run("returns 4 regions with correct state counts", func(t *testing.T) {
+func TestPageData(t *testing.T) {
+	t.Run("includes each leader keyed by region", func(t *testing.T) {
 		g := NewWithT(t)
 
-		regions := USRegions()
+		data := PageData([]RegionalLeader{
+			{ID: "midwest", Name: "James Wilson", Region: "Midwest", Email: "midwest@thefallenoutdoors.org"},
+		})
 
-		g.Expect(regions).To(HaveLen(4))
+		g.Expect(data).To(ContainSubstring(`leaders: {"midwest":{"name":"James Wilson","region":"Midwest","email":"midwest@thefallenoutdoors.org"}}`))
+		g.Expect(data).To(ContainSubstring("selectRegion(regionId)"))
+	})
 
-		byID := make(map[string]Region, len(regions))
-		for _, r := range regions {
-			byID[r.ID] = r
-		}
+	t.Run("escapes markup in leader details", func(t *testing.T) {
+		g := NewWithT(t)
 
-		g.Expect(byID).To(HaveKey("west-coast"))
-		g.Expect(byID).To(HaveKey("midwest"))
-		g.Expect(byID).To(HaveKey("east-coast"))
-		g.Expect(byID).To(HaveKey("southern"))
+		data := PageData([]RegionalLeader{{ID: "midwest", Name: "</script>"}})
 
-		g.Expect(byID["west-coast"].States).To(HaveLen(13))
-		g.Expect(byID["midwest"].States).To(HaveLen(12))
-		g.Expect(byID["east-coast"].States).To(HaveLen(16))
-		g.Expect(byID["southern"].States).To(HaveLen(10))
+		g.Expect(data).ToNot(ContainSubstring("</script>"))
 	})
 
-	t.Run("each region has a color", func(t *testing.T) {
+	t.Run("renders an empty object without leaders", func(t *testing.T) {
 		g := NewWithT(t)
 
-		for _, r := range USRegions() {
-			g.Expect(r.Color).ToNot(BeEmpty(), "region %s missing color", r.ID)
-		}
+		g.Expect(PageData(nil)).To(ContainSubstring("leaders: {},"))
 	})
+}
 
-	t.Run("leader IDs match region IDs", func(t *testing.T) {
+func TestMapRegions(t *testing.T) {
+	t.Run("draws each region's states", func(t *testing.T) {
 		g := NewWithT(t)
 
-		regionIDs := make(map[string]bool)
-		for _, r := range USRegions() {
-			regionIDs[r.ID] = true
+		regions := MapRegions(testRegions())
+
+		g.Expect(regions).To(HaveLen(2))
+		g.Expect(regions[0].ID).To(Equal("west-coast"))
+		g.Expect(regions[0].Color).To(Equal("#009FDE"))
+		g.Expect(regions[0].States).To(HaveLen(3))
+		for _, s := range regions[0].States {
+			g.Expect(s.Path).ToNot(BeEmpty(), "state %s missing path", s.ID)
 		}
+	})
 
-		for _, l := range DefaultRegionalLeaders() {
-			g.Expect(regionIDs).To(HaveKey(l.ID), "leader %s has no matching region", l.ID)
+	t.Run("skips states not on the map", func(t *testing.T) {
+		g := NewWithT(t)
+
+		regions := MapRegions([]model.Region{{ID: "other", States: []string{"PR", "TX"}}})
+
+		g.Expect(regions[0].States).To(HaveLen(1))
+		g.Expect(regions[0].States[0].ID).To(Equal("TX"))
+	})
+}
+
+func TestUnassignedStates(t *testing.T) {
+	t.Run("returns states no region covers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		unassigned := UnassignedStates(testRegions())
+
+		g.Expect(unassigned).To(HaveLen(len(StateCodes()) - 5))
+		for _, s := range unassigned {
+			g.Expect(s.ID).ToNot(BeElementOf("CA", "OR", "WA", "IA", "MN"))
 		}
 	})
 }
+
+func TestStateCodes(t *testing.T) {
+	t.Run("lists every state on the map once", func(t *testing.T) {
+		g := NewWithT(t)
+
+		codes := StateCodes()
+
+		g.Expect(codes).To(HaveLen(51))
+		g.Expect(codes).To(ContainElements("AK", "DC", "HI", "TX"))
+		g.Expect(IsStateCode("TX")).To(BeTrue())
+		g.Expect(IsStateCode("PR")).To(BeFalse())
+	})
+}
@@ -1,5 +1,11 @@
 package contact
 
+import (
+	"slices"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
 // Region represents a clickable US map region.
 type Region struct {
 	ID     string
@@ -14,14 +20,68 @@ type State struct {
 	Path string
 }
 
-// USRegions returns all regions with their states for the interactive map.
-func USRegions() []Region {
-	return []Region{
-		{ID: "west-coast", Name: "West Coast", Color: "#009FDE", States: westCoastStates()},
-		{ID: "midwest", Name: "Midwest", Color: "#81D742", States: midwestStates()},
-		{ID: "east-coast", Name: "East Coast", Color: "#DD3333", States: eastCoastStates()},
-		{ID: "southern", Name: "Southern", Color: "#EEEE22", States: southernStates()},
+// UnassignedColor fills states that aren't part of any region.
+const UnassignedColor = "#D4D4D4"
+
+// MapRegions groups the map's states into regions for the interactive
+// map, in the order given. Region membership comes from the database;
+// only the state outlines live here.
+func MapRegions(regions []model.Region) []Region {
+	paths := statePaths()
+	out := make([]Region, 0, len(regions))
+	for _, r := range regions {
+		region := Region{ID: r.ID, Name: r.Name, Color: r.Color}
+		for _, code := range r.States {
+			if path, ok := paths[code]; ok {
+				region.States = append(region.States, State{ID: code, Path: path})
+			}
+		}
+		out = append(out, region)
+	}
+	return out
+}
+
+// UnassignedStates returns the map's states that none of regions covers.
+func UnassignedStates(regions []model.Region) []State {
+	var out []State
+	for _, s := range usStates() {
+		if !slices.ContainsFunc(regions, func(r model.Region) bool { return r.HasState(s.ID) }) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// StateCodes returns the sorted postal codes of every state on the map.
+func StateCodes() []string {
+	states := usStates()
+	codes := make([]string, len(states))
+	for i, s := range states {
+		codes[i] = s.ID
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// IsStateCode returns true if code is a state on the map.
+func IsStateCode(code string) bool {
+	_, ok := statePaths()[code]
+	return ok
+}
+
+func statePaths() map[string]string {
+	states := usStates()
+	paths := make(map[string]string, len(states))
+	for _, s := range states {
+		paths[s.ID] = s.Path
 	}
+	return paths
+}
+
+// usStates returns the outline of every state on the map, grouped as the
+// regions were originally drawn.
+func usStates() []State {
+	return slices.Concat(westCoastStates(), midwestStates(), eastCoastStates(), southernStates())
 }
 
 func westCoastStates() []State {
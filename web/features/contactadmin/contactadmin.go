@@ -0,0 +1,213 @@
+package contactadmin
+
+import (
+	"database/sql"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// PageSize is the number of submissions shown per inbox page.
+const PageSize = 25
+
+// InboxURL is the staff contact inbox.
+const InboxURL = "/admin/contact"
+
+// Assignee filter values besides a staff member's ID.
+const (
+	AssignedToMe = "me"
+	Unassigned   = "none"
+)
+
+// DetailURL returns the staff page for a submission.
+func DetailURL(id uuid.UUID) string {
+	return InboxURL + "/" + id.String()
+}
+
+// TriageURL returns the form action for changing a submission's status
+// and assignee.
+func TriageURL(id uuid.UUID) string {
+	return DetailURL(id) + "/triage"
+}
+
+// NotesURL returns the form action for adding an internal note.
+func NotesURL(id uuid.UUID) string {
+	return DetailURL(id) + "/notes"
+}
+
+// StatusLabel returns the display name of a triage status.
+func StatusLabel(s model.ContactStatus) string {
+	switch s {
+	case model.ContactNew:
+		return "New"
+	case model.ContactInProgress:
+		return "In progress"
+	case model.ContactResolved:
+		return "Resolved"
+	case model.ContactSpam:
+		return "Spam"
+	default:
+		return string(s)
+	}
+}
+
+// RegionOption is a region messages can be sent to.
+type RegionOption struct {
+	ID   string
+	Name string
+}
+
+// RegionName returns the name of the region a message was sent to, or ""
+// if it wasn't sent to one.
+func RegionName(regions []RegionOption, id sql.NullString) string {
+	if !id.Valid {
+		return ""
+	}
+	for _, r := range regions {
+		if r.ID == id.String {
+			return r.Name
+		}
+	}
+	return id.String
+}
+
+// Filter holds the inbox's query parameters as submitted.
+type Filter struct {
+	Status   string
+	Assignee string
+	Region   string
+	Search   string
+}
+
+// FilterFromQuery reads a Filter from URL query values. Unknown statuses
+// are ignored.
+func FilterFromQuery(q url.Values) Filter {
+	f := Filter{
+		Status:   q.Get("status"),
+		Assignee: q.Get("assignee"),
+		Region:   q.Get("region"),
+		Search:   strings.TrimSpace(q.Get("q")),
+	}
+	if !model.ContactStatus(f.Status).IsValid() {
+		f.Status = ""
+	}
+	return f
+}
+
+// Query encodes the filter as URL query values, omitting empty fields.
+func (f Filter) Query() url.Values {
+	q := url.Values{}
+	for k, v := range map[string]string{"status": f.Status, "assignee": f.Assignee, "region": f.Region, "q": f.Search} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	return q
+}
+
+// WithStatus returns a copy of the filter showing status instead; ""
+// shows every status.
+func (f Filter) WithStatus(status model.ContactStatus) Filter {
+	f.Status = string(status)
+	return f
+}
+
+// URL returns the inbox URL for the filter.
+func (f Filter) URL() string {
+	q := f.Query()
+	if len(q) == 0 {
+		return InboxURL
+	}
+	return InboxURL + "?" + q.Encode()
+}
+
+// InboxProps contains data for the contact inbox.
+type InboxProps struct {
+	Filter      Filter
+	Submissions []model.ContactSubmission
+	Total       int
+	Page        int
+	// Staff are the people submissions can be assigned to.
+	Staff   []model.User
+	Regions []RegionOption
+}
+
+// HasPrev returns true if there is a page before the current one.
+func (p InboxProps) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true if there is a page after the current one.
+func (p InboxProps) HasNext() bool {
+	return p.Page*PageSize < p.Total
+}
+
+// PageURL returns the inbox URL for page n with the current filter.
+func (p InboxProps) PageURL(n int) string {
+	q := p.Filter.Query()
+	if n > 1 {
+		q.Set("page", strconv.Itoa(n))
+	}
+	if len(q) == 0 {
+		return InboxURL
+	}
+	return InboxURL + "?" + q.Encode()
+}
+
+// AssigneeName returns the name of the staff member handling a
+// submission, or "Unassigned".
+func AssigneeName(staff []model.User, id uuid.NullUUID) string {
+	if !id.Valid {
+		return "Unassigned"
+	}
+	for _, u := range staff {
+		if u.ID == id.UUID {
+			return u.Name
+		}
+	}
+	return "Former staff"
+}
+
+// Excerpt shortens a message to at most n characters for the inbox list.
+func Excerpt(message string, n int) string {
+	message = strings.Join(strings.Fields(message), " ")
+	runes := []rune(message)
+	if len(runes) <= n {
+		return message
+	}
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
+
+// Note is an internal note as shown on a submission's page.
+type Note struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// DetailProps contains data for a single submission's page.
+type DetailProps struct {
+	Submission model.ContactSubmission
+	Notes      []Note
+	Staff      []model.User
+	Regions    []RegionOption
+	// Note is the unsaved note, redisplayed after a failed save.
+	Note    string
+	Message string
+	Error   string
+}
+
+// IsAssignedTo returns true if the submission is assigned to id.
+func (p DetailProps) IsAssignedTo(id uuid.UUID) bool {
+	return p.Submission.AssigneeID.Valid && p.Submission.AssigneeID.UUID == id
+}
+
+// TimeLabel formats a submission or note timestamp for display.
+func TimeLabel(t time.Time) string {
+	return t.Format("Jan 2, 2006 3:04 PM")
+}
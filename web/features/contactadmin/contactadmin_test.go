@@ -0,0 +1,111 @@
+package contactadmin
+
+import (
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestFilterFromQuery(t *testing.T) {
+	t.Run("reads status, assignee and search", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := FilterFromQuery(url.Values{"status": {"in_progress"}, "assignee": {"me"}, "region": {"midwest"}, "q": {"  elk tags "}})
+
+		g.Expect(f).To(Equal(Filter{Status: "in_progress", Assignee: AssignedToMe, Region: "midwest", Search: "elk tags"}))
+	})
+
+	t.Run("ignores unknown statuses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(FilterFromQuery(url.Values{"status": {"archived"}}).Status).To(BeEmpty())
+	})
+}
+
+func TestFilter_URL(t *testing.T) {
+	t.Run("returns the bare inbox without filters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Filter{}.URL()).To(Equal(InboxURL))
+	})
+
+	t.Run("keeps the search when switching status", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := Filter{Status: "new", Search: "elk"}.WithStatus(model.ContactSpam)
+
+		g.Expect(f.URL()).To(Equal("/admin/contact?q=elk&status=spam"))
+	})
+}
+
+func TestInboxProps_PageURL(t *testing.T) {
+	t.Run("adds the page to the filter", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := InboxProps{Filter: Filter{Assignee: Unassigned}}
+
+		g.Expect(p.PageURL(1)).To(Equal("/admin/contact?assignee=none"))
+		g.Expect(p.PageURL(3)).To(Equal("/admin/contact?assignee=none&page=3"))
+	})
+
+	t.Run("knows when there are more pages", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := InboxProps{Page: 1, Total: PageSize + 1}
+
+		g.Expect(p.HasPrev()).To(BeFalse())
+		g.Expect(p.HasNext()).To(BeTrue())
+	})
+}
+
+func TestAssigneeName(t *testing.T) {
+	staff := []model.User{{ID: uuid.New(), Name: "Sam Staff"}}
+
+	t.Run("names the assigned staff member", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(AssigneeName(staff, uuid.NullUUID{UUID: staff[0].ID, Valid: true})).To(Equal("Sam Staff"))
+	})
+
+	t.Run("describes unassigned and departed assignees", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(AssigneeName(staff, uuid.NullUUID{})).To(Equal("Unassigned"))
+		g.Expect(AssigneeName(staff, uuid.NullUUID{UUID: uuid.New(), Valid: true})).To(Equal("Former staff"))
+	})
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Run("returns short messages whole with whitespace collapsed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Excerpt("Hello\n\nthere", 20)).To(Equal("Hello there"))
+	})
+
+	t.Run("truncates long messages", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Excerpt("Question about elk tags", 14)).To(Equal("Question about…"))
+	})
+}
+
+func TestRegionName(t *testing.T) {
+	regions := []RegionOption{{ID: "midwest", Name: "Midwest"}}
+
+	t.Run("names the region a message was sent to", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(RegionName(regions, sql.NullString{String: "midwest", Valid: true})).To(Equal("Midwest"))
+	})
+
+	t.Run("returns empty for messages without a region", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(RegionName(regions, sql.NullString{})).To(BeEmpty())
+	})
+}
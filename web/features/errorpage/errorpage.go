@@ -0,0 +1,35 @@
+package errorpage
+
+import "net/http"
+
+// Props describes an error page.
+type Props struct {
+	Status    int
+	Title     string
+	Message   string
+	ShowLogin bool
+}
+
+// PropsFor returns the error page content for an HTTP status. A non-empty
+// message overrides the default explanation for that status.
+func PropsFor(status int, message string) Props {
+	p := Props{Status: status, Title: http.StatusText(status)}
+	switch status {
+	case http.StatusUnauthorized:
+		p.Title = "Log In Required"
+		p.Message = "Please log in to continue."
+		p.ShowLogin = true
+	case http.StatusForbidden:
+		p.Title = "Access Denied"
+		p.Message = "You don't have permission to view this page."
+	case http.StatusNotFound:
+		p.Title = "Page Not Found"
+		p.Message = "We couldn't find the page you were looking for."
+	default:
+		p.Message = "Something went wrong. Please try again later."
+	}
+	if message != "" {
+		p.Message = message
+	}
+	return p
+}
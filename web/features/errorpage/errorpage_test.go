@@ -0,0 +1,37 @@
+package errorpage
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPropsFor(t *testing.T) {
+	t.Run("offers login for 401", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := PropsFor(http.StatusUnauthorized, "")
+
+		g.Expect(p.Status).To(Equal(http.StatusUnauthorized))
+		g.Expect(p.ShowLogin).To(BeTrue())
+		g.Expect(p.Message).ToNot(BeEmpty())
+	})
+
+	t.Run("does not offer login for 403", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := PropsFor(http.StatusForbidden, "")
+
+		g.Expect(p.Title).To(Equal("Access Denied"))
+		g.Expect(p.ShowLogin).To(BeFalse())
+	})
+
+	t.Run("message overrides the default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := PropsFor(http.StatusForbidden, "Membership pending")
+
+		g.Expect(p.Message).To(Equal("Membership pending"))
+	})
+}
@@ -0,0 +1,590 @@
+package huntadmin
+
+import (
+	"database/sql"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// PageSize is the number of hunts shown per console page.
+const PageSize = 25
+
+// inputLayout is the format of datetime-local inputs.
+const inputLayout = "2006-01-02T15:04"
+
+// Tab is a hunt status filter on the console.
+type Tab struct {
+	Status model.HuntStatus
+	Label  string
+}
+
+// StatusTabs returns the console's status tabs. The empty status lists
+// every hunt.
+func StatusTabs() []Tab {
+	return []Tab{
+		{Status: "", Label: "All"},
+		{Status: model.HuntStatusDraft, Label: "Drafts"},
+		{Status: model.HuntStatusOpen, Label: "Open"},
+		{Status: model.HuntStatusClosed, Label: "Closed"},
+		{Status: model.HuntStatusCompleted, Label: "Completed"},
+		{Status: model.HuntStatusCancelled, Label: "Cancelled"},
+	}
+}
+
+// IsEditable returns true if staff may still change a hunt's details.
+// Once signups close the lottery depends on them, so they are frozen.
+func IsEditable(h model.Hunt) bool {
+	return h.Status == model.HuntStatusDraft || h.Status == model.HuntStatusOpen
+}
+
+// Action is a status change staff can make from the console.
+type Action struct {
+	Label string
+	To    model.HuntStatus
+	// NeedsReason actions ask staff to explain the change.
+	NeedsReason bool
+	// Destructive actions are styled as warnings.
+	Destructive bool
+}
+
+// ActionsFor returns the status changes available for a hunt in status.
+// Publishing drafts happens from the edit form instead.
+func ActionsFor(status model.HuntStatus) []Action {
+	var actions []Action
+	switch status {
+	case model.HuntStatusOpen:
+		actions = append(actions, Action{Label: "Close signups", To: model.HuntStatusClosed})
+	case model.HuntStatusClosed:
+		actions = append(actions, Action{Label: "Mark completed", To: model.HuntStatusCompleted})
+	}
+	if status.CanTransitionTo(model.HuntStatusCancelled) {
+		actions = append(actions, Action{Label: "Cancel hunt", To: model.HuntStatusCancelled, NeedsReason: true, Destructive: true})
+	}
+	return actions
+}
+
+// Form holds submitted hunt values as entered.
+type Form struct {
+	Title             string
+	Description       string
+	Location          string
+	State             string
+	ImageURLs         string // one URL per line
+	Qualifiers        string
+	HuntDate          string
+	SignupWindowStart string
+	SignupWindowEnd   string
+	PrimaryCapacity   string
+	AlternateCapacity string
+	ConfirmationHours string
+	LotteryAlgorithm  string
+	MissedDrawTickets string // comma-separated, e.g. "1, 2, 3"
+	FirstTimerBonus   string
+}
+
+// NewForm returns the form for a new hunt, starting from the default
+// lottery settings.
+func NewForm() Form {
+	weights := model.DefaultLotteryWeights()
+	return Form{
+		PrimaryCapacity:   "0",
+		AlternateCapacity: "0",
+		ConfirmationHours: strconv.Itoa(model.DefaultConfirmationWindowHours),
+		LotteryAlgorithm:  lottery.UniformV1,
+		MissedDrawTickets: formatTickets(weights.MissedDrawTickets),
+		FirstTimerBonus:   strconv.Itoa(weights.FirstTimerBonus),
+	}
+}
+
+// AlgorithmOption is a lottery algorithm staff can choose for a hunt.
+type AlgorithmOption struct {
+	Version string
+	Label   string
+}
+
+// AlgorithmOptions returns the lottery algorithms offered on the form.
+func AlgorithmOptions() []AlgorithmOption {
+	return []AlgorithmOption{
+		{Version: lottery.UniformV1, Label: "Uniform: every entry has the same odds"},
+		{Version: lottery.WeightedV1, Label: "Weighted: favour long waits and first-timers"},
+	}
+}
+
+// FormFromHunt pre-fills the form with a hunt's values, showing times in
+// loc.
+func FormFromHunt(h model.Hunt, loc *time.Location) Form {
+	return Form{
+		Title:             h.Title,
+		Description:       h.Description,
+		Location:          h.Location,
+		State:             h.State,
+		ImageURLs:         strings.Join(h.ImageURLs, "\n"),
+		Qualifiers:        h.Qualifiers.String,
+		HuntDate:          formatInput(h.HuntDate, loc),
+		SignupWindowStart: formatInput(h.SignupWindowStart, loc),
+		SignupWindowEnd:   formatInput(h.SignupWindowEnd, loc),
+		PrimaryCapacity:   strconv.Itoa(h.PrimaryCapacity),
+		AlternateCapacity: strconv.Itoa(h.AlternateCapacity),
+		ConfirmationHours: strconv.Itoa(h.ConfirmationWindowHours),
+		LotteryAlgorithm:  h.LotteryAlgorithm,
+		MissedDrawTickets: formatTickets(h.LotteryWeights.MissedDrawTickets),
+		FirstTimerBonus:   strconv.Itoa(h.LotteryWeights.FirstTimerBonus),
+	}
+}
+
+func formatTickets(tickets []int) string {
+	parts := make([]string, len(tickets))
+	for i, n := range tickets {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CloneForm pre-fills the form from a previous hunt as a template for the
+// next season: every date moves forward one year.
+func CloneForm(h model.Hunt, loc *time.Location) Form {
+	h.HuntDate = h.HuntDate.AddDate(1, 0, 0)
+	h.SignupWindowStart = h.SignupWindowStart.AddDate(1, 0, 0)
+	h.SignupWindowEnd = h.SignupWindowEnd.AddDate(1, 0, 0)
+	return FormFromHunt(h, loc)
+}
+
+func formatInput(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(inputLayout)
+}
+
+// Errors maps form field names to validation messages.
+type Errors map[string]string
+
+// Validate checks the form and returns the hunt it describes, with times
+// interpreted in loc. The returned hunt is only meaningful when errs is
+// empty.
+func (f *Form) Validate(loc *time.Location) (model.Hunt, Errors) {
+	f.Title = strings.TrimSpace(f.Title)
+	f.Description = strings.TrimSpace(f.Description)
+	f.Location = strings.TrimSpace(f.Location)
+	f.State = strings.ToUpper(strings.TrimSpace(f.State))
+	f.Qualifiers = strings.TrimSpace(f.Qualifiers)
+
+	errs := Errors{}
+	h := model.Hunt{
+		Title:       f.Title,
+		Description: f.Description,
+		Location:    f.Location,
+		State:       f.State,
+		Qualifiers:  sql.NullString{String: f.Qualifiers, Valid: f.Qualifiers != ""},
+	}
+
+	if f.Title == "" {
+		errs["title"] = "Title is required"
+	}
+	if f.Description == "" {
+		errs["description"] = "Description is required"
+	}
+	if f.Location == "" {
+		errs["location"] = "Location is required"
+	}
+	if !isStateCode(f.State) {
+		errs["state"] = "Use a two-letter state code, like TX"
+	}
+
+	for _, line := range strings.Split(f.ImageURLs, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if u, err := url.Parse(line); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			errs["image_urls"] = "Each image must be a full http(s) URL on its own line"
+			continue
+		}
+		h.ImageURLs = append(h.ImageURLs, line)
+	}
+
+	h.HuntDate = parseInput(f.HuntDate, loc, "hunt_date", "Hunt date", errs)
+	h.SignupWindowStart = parseInput(f.SignupWindowStart, loc, "signup_window_start", "Signup start", errs)
+	h.SignupWindowEnd = parseInput(f.SignupWindowEnd, loc, "signup_window_end", "Signup end", errs)
+	if _, bad := errs["signup_window_start"]; !bad {
+		if _, bad := errs["signup_window_end"]; !bad && !h.SignupWindowStart.Before(h.SignupWindowEnd) {
+			errs["signup_window_end"] = "Signups must close after they open"
+		}
+	}
+	if _, bad := errs["signup_window_end"]; !bad {
+		if _, bad := errs["hunt_date"]; !bad && !h.SignupWindowEnd.Before(h.HuntDate) {
+			errs["hunt_date"] = "The hunt must be after signups close"
+		}
+	}
+
+	h.PrimaryCapacity = parseCapacity(f.PrimaryCapacity, "primary_capacity", errs)
+	h.AlternateCapacity = parseCapacity(f.AlternateCapacity, "alternate_capacity", errs)
+	if n, err := strconv.Atoi(strings.TrimSpace(f.ConfirmationHours)); err != nil || n < 1 {
+		errs["confirmation_window_hours"] = "Enter a whole number of hours, at least 1"
+	} else {
+		h.ConfirmationWindowHours = n
+	}
+
+	h.LotteryAlgorithm = f.LotteryAlgorithm
+	if _, err := lottery.Lookup(f.LotteryAlgorithm); err != nil {
+		errs["lottery_algorithm"] = "Choose a lottery algorithm"
+	}
+	h.LotteryWeights.MissedDrawTickets = parseTickets(f.MissedDrawTickets, errs)
+	if n, err := strconv.Atoi(strings.TrimSpace(f.FirstTimerBonus)); err != nil || n < 0 || n > maxTickets {
+		errs["first_timer_bonus"] = "Enter a whole number from 0 to " + strconv.Itoa(maxTickets)
+	} else {
+		h.LotteryWeights.FirstTimerBonus = n
+	}
+	return h, errs
+}
+
+const (
+	// maxTicketTiers bounds the ticket table's length.
+	maxTicketTiers = 20
+	// maxTickets bounds each tier and the first-timer bonus, keeping a
+	// draw's ticket total far from overflowing.
+	maxTickets = 1000
+)
+
+func parseTickets(v string, errs Errors) []int {
+	var tickets []int
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 || n > maxTickets {
+			errs["missed_draw_tickets"] = "Enter whole numbers from 1 to " + strconv.Itoa(maxTickets) + ", separated by commas"
+			return nil
+		}
+		tickets = append(tickets, n)
+	}
+	if len(tickets) > maxTicketTiers {
+		errs["missed_draw_tickets"] = "Use at most " + strconv.Itoa(maxTicketTiers) + " values"
+	}
+	return tickets
+}
+
+func parseInput(v string, loc *time.Location, field, label string, errs Errors) time.Time {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		errs[field] = label + " is required"
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(inputLayout, v, loc)
+	if err != nil {
+		errs[field] = label + " is not a valid date and time"
+	}
+	return t
+}
+
+func parseCapacity(v, field string, errs Errors) int {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		errs[field] = "Enter a whole number, zero or more"
+		return 0
+	}
+	return n
+}
+
+func isStateCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexProps contains data for the hunt console listing.
+type IndexProps struct {
+	Status model.HuntStatus
+	Hunts  []model.Hunt
+	Total  int
+	Page   int
+	// Error describes a failed status change on the hunt with ErrorFor.
+	Error    string
+	ErrorFor uuid.UUID
+}
+
+// HasPrev returns true if there is a page before the current one.
+func (p IndexProps) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true if there is a page after the current one.
+func (p IndexProps) HasNext() bool {
+	return p.Page*PageSize < p.Total
+}
+
+// TabURL returns the console URL for a status tab.
+func TabURL(status model.HuntStatus) string {
+	if status == "" {
+		return "/admin/hunts"
+	}
+	return "/admin/hunts?status=" + string(status)
+}
+
+// PageURL returns the console URL for page n of the current tab.
+func (p IndexProps) PageURL(n int) string {
+	u := TabURL(p.Status)
+	if p.Status == "" {
+		return u + "?page=" + strconv.Itoa(n)
+	}
+	return u + "&page=" + strconv.Itoa(n)
+}
+
+// Values of the form's publish button. Saving a draft with any other
+// value leaves it unpublished and unscheduled.
+const (
+	PublishNow = "1"
+	// PublishOnSchedule keeps the hunt a draft until its signup window
+	// starts, when the scheduler opens it.
+	PublishOnSchedule = "scheduled"
+)
+
+// FormProps contains data for the create and edit forms. A zero HuntID
+// means a new hunt.
+type FormProps struct {
+	HuntID uuid.UUID
+	Status model.HuntStatus
+	// AutoOpen is true for a draft scheduled to open when signups do.
+	AutoOpen bool
+	Form     Form
+	Errors   Errors
+}
+
+// IsNew returns true if the form creates a hunt.
+func (p FormProps) IsNew() bool {
+	return p.HuntID == uuid.Nil
+}
+
+// Action returns the form's submit URL.
+func (p FormProps) Action() string {
+	if p.IsNew() {
+		return "/admin/hunts"
+	}
+	return EditURL(p.HuntID)
+}
+
+// CanPublish returns true if saving the form may also publish the hunt.
+func (p FormProps) CanPublish() bool {
+	return p.IsNew() || p.Status == model.HuntStatusDraft
+}
+
+// EditURL returns the edit form for a hunt; the form posts back to it.
+func EditURL(id uuid.UUID) string {
+	return "/admin/hunts/" + id.String()
+}
+
+// CloneURL returns a new-hunt form pre-filled from hunt id.
+func CloneURL(id uuid.UUID) string {
+	return "/admin/hunts/new?from=" + id.String()
+}
+
+// EntriesURL returns the entry audit page for a hunt.
+func EntriesURL(id uuid.UUID) string {
+	return "/admin/hunts/" + id.String() + "/entries"
+}
+
+// RosterURL returns the live roster page for a drawn hunt.
+func RosterURL(id uuid.UUID) string {
+	return "/admin/hunts/" + id.String() + "/roster"
+}
+
+// PromoteURL returns the form action that offers free places to
+// alternates.
+func PromoteURL(id uuid.UUID) string {
+	return RosterURL(id) + "/promote"
+}
+
+// StatusURL returns the form action for changing a hunt's status.
+func StatusURL(id uuid.UUID) string {
+	return "/admin/hunts/" + id.String() + "/status"
+}
+
+// EntriesProps contains data for a hunt's entry audit page.
+type EntriesProps struct {
+	Hunt    model.Hunt
+	Entries []Entry
+}
+
+// Active returns the number of entries that haven't been withdrawn.
+func (p EntriesProps) Active() int {
+	n := 0
+	for _, e := range p.Entries {
+		if !e.Signup.IsWithdrawn() {
+			n++
+		}
+	}
+	return n
+}
+
+// Entry is a signup on the audit page with its decoded eligibility
+// snapshot. Snapshot is nil if none was recorded or it couldn't be read.
+type Entry struct {
+	Name     string
+	Email    string
+	Signup   model.Signup
+	Snapshot *model.EligibilitySnapshot
+}
+
+// NewEntry decodes a signup's eligibility snapshot for display.
+func NewEntry(s model.Signup, name, email string) Entry {
+	e := Entry{Name: name, Email: email, Signup: s}
+	if snapshot, err := s.Eligibility(); err == nil {
+		e.Snapshot = &snapshot
+	}
+	return e
+}
+
+// LastSelectedLabel describes when a snapshot's member was last selected.
+func LastSelectedLabel(s model.EligibilitySnapshot) string {
+	if s.LastSelectedAt == nil {
+		return "Never"
+	}
+	return s.LastSelectedAt.Format("Jan 2, 2006")
+}
+
+// QualifiersLabel lists the qualifiers a snapshot's member attested to.
+func QualifiersLabel(s model.EligibilitySnapshot) string {
+	if len(s.AttestedQualifiers) == 0 {
+		return "None required"
+	}
+	return strings.Join(s.AttestedQualifiers, "; ")
+}
+
+// Slot is where a drawn entrant sits on a hunt's roster.
+type Slot string
+
+const (
+	// SlotPrimary entrants were drawn within the primary capacity.
+	SlotPrimary Slot = "primary"
+	// SlotPromoted alternates have been offered a place that freed up.
+	SlotPromoted Slot = "promoted"
+	// SlotWaiting alternates haven't been offered a place yet.
+	SlotWaiting Slot = "waiting"
+)
+
+// RosterRow is one drawn entrant on the roster. Confirmation is nil until
+// the entrant is offered a place.
+type RosterRow struct {
+	Position     int
+	Name         string
+	Email        string
+	Slot         Slot
+	Confirmation *model.Confirmation
+}
+
+// NewRoster places a hunt's drawn entrants on its roster, in draw order.
+// Entrants drawn past the alternate capacity are left off.
+func NewRoster(hunt model.Hunt, results []model.LotteryResult, entries []Entry, confirmations []model.Confirmation) []RosterRow {
+	bySignup := make(map[uuid.UUID]Entry, len(entries))
+	for _, e := range entries {
+		bySignup[e.Signup.ID] = e
+	}
+	byResult := make(map[uuid.UUID]model.Confirmation, len(confirmations))
+	for _, c := range confirmations {
+		byResult[c.LotteryResultID] = c
+	}
+
+	var rows []RosterRow
+	for _, res := range results {
+		row := RosterRow{Position: res.Position}
+		switch {
+		case res.IsPrimary(hunt.PrimaryCapacity):
+			row.Slot = SlotPrimary
+		case res.IsAlternate(hunt.PrimaryCapacity, hunt.AlternateCapacity):
+			row.Slot = SlotWaiting
+		default:
+			continue
+		}
+		if c, ok := byResult[res.ID]; ok {
+			row.Confirmation = &c
+			if row.Slot == SlotWaiting {
+				row.Slot = SlotPromoted
+			}
+		}
+		e := bySignup[res.SignupID]
+		row.Name, row.Email = e.Name, e.Email
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// RosterProps contains data for a hunt's live roster.
+type RosterProps struct {
+	Hunt model.Hunt
+	Rows []RosterRow
+	Now  time.Time
+}
+
+// InSlot returns the roster rows in slot, in draw order.
+func (p RosterProps) InSlot(slot Slot) []RosterRow {
+	var rows []RosterRow
+	for _, r := range p.Rows {
+		if r.Slot == slot {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// Held returns how many places are held by a pending or accepted
+// confirmation.
+func (p RosterProps) Held() int {
+	n := 0
+	for _, r := range p.Rows {
+		if r.Confirmation != nil && r.Confirmation.HoldsPlace() {
+			n++
+		}
+	}
+	return n
+}
+
+// Accepted returns how many places have been accepted.
+func (p RosterProps) Accepted() int {
+	n := 0
+	for _, r := range p.Rows {
+		if r.Confirmation != nil && r.Confirmation.IsFinal() {
+			n++
+		}
+	}
+	return n
+}
+
+// CanPromote returns true if staff may offer free places to alternates.
+// Only closed hunts promote.
+func (p RosterProps) CanPromote() bool {
+	return p.Hunt.Status == model.HuntStatusClosed
+}
+
+// ConfirmationLabel describes where a roster row's confirmation stands.
+func ConfirmationLabel(c *model.Confirmation, now time.Time) string {
+	if c == nil {
+		return "Not offered"
+	}
+	switch c.Decision {
+	case model.ConfirmationAccepted:
+		return "Accepted"
+	case model.ConfirmationDeclined:
+		return "Declined"
+	case model.ConfirmationExpired:
+		return "Expired"
+	}
+	if !c.CanRespond(now) {
+		return "Overdue"
+	}
+	return "Awaiting reply until " + c.Deadline.Format("Jan 2, 3:04 PM")
+}
@@ -0,0 +1,300 @@
+package huntadmin
+
+import (
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func validForm() Form {
+	return Form{
+		Title:             "Whitetail Weekend",
+		Description:       "Three days in the stand.",
+		Location:          "Hill Country Ranch",
+		State:             "tx",
+		ImageURLs:         "https://example.com/a.jpg\n\nhttps://example.com/b.jpg",
+		SignupWindowStart: "2030-09-01T09:00",
+		SignupWindowEnd:   "2030-09-15T17:00",
+		HuntDate:          "2030-11-01T06:00",
+		PrimaryCapacity:   "10",
+		AlternateCapacity: "0",
+		ConfirmationHours: "72",
+		LotteryAlgorithm:  "uniform-v1",
+		MissedDrawTickets: "1, 2, 3",
+		FirstTimerBonus:   "2",
+	}
+}
+
+func TestForm_Validate(t *testing.T) {
+	t.Run("accepts a complete form", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+
+		h, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(h.State).To(Equal("TX"))
+		g.Expect(h.ImageURLs).To(Equal([]string{"https://example.com/a.jpg", "https://example.com/b.jpg"}))
+		g.Expect(h.Qualifiers.Valid).To(BeFalse())
+		g.Expect(h.HuntDate).To(Equal(time.Date(2030, 11, 1, 6, 0, 0, 0, time.UTC)))
+		g.Expect(h.AlternateCapacity).To(Equal(0))
+	})
+
+	t.Run("requires signups to open before they close", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.SignupWindowEnd = f.SignupWindowStart
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("signup_window_end"))
+	})
+
+	t.Run("requires the hunt after signups close", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.HuntDate = "2030-09-10T06:00"
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("hunt_date"))
+	})
+
+	t.Run("rejects negative capacities", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.PrimaryCapacity = "-1"
+		f.AlternateCapacity = "two"
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("primary_capacity"))
+		g.Expect(errs).To(HaveKey("alternate_capacity"))
+	})
+
+	t.Run("requires a positive confirmation window", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.ConfirmationHours = "0"
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("confirmation_window_hours"))
+	})
+
+	t.Run("rejects relative image urls", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.ImageURLs = "/static/a.jpg"
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("image_urls"))
+	})
+
+	t.Run("parses the weighted lottery settings", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.LotteryAlgorithm = "weighted-v1"
+
+		h, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(h.LotteryAlgorithm).To(Equal("weighted-v1"))
+		g.Expect(h.LotteryWeights).To(Equal(model.LotteryWeights{MissedDrawTickets: []int{1, 2, 3}, FirstTimerBonus: 2}))
+	})
+
+	t.Run("rejects bad lottery settings", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.LotteryAlgorithm = "nope-v0"
+		f.MissedDrawTickets = "1, 0"
+		f.FirstTimerBonus = "-1"
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("lottery_algorithm"))
+		g.Expect(errs).To(HaveKey("missed_draw_tickets"))
+		g.Expect(errs).To(HaveKey("first_timer_bonus"))
+	})
+
+	t.Run("caps ticket counts", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.MissedDrawTickets = "1, 9223372036854775807"
+		f.FirstTimerBonus = "1001"
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("missed_draw_tickets"))
+		g.Expect(errs).To(HaveKey("first_timer_bonus"))
+	})
+
+	t.Run("requires the text fields", func(t *testing.T) {
+		g := NewWithT(t)
+		f := Form{}
+
+		_, errs := f.Validate(time.UTC)
+
+		g.Expect(errs).To(HaveKey("title"))
+		g.Expect(errs).To(HaveKey("description"))
+		g.Expect(errs).To(HaveKey("location"))
+		g.Expect(errs).To(HaveKey("state"))
+		g.Expect(errs).To(HaveKey("hunt_date"))
+	})
+}
+
+func TestCloneForm(t *testing.T) {
+	t.Run("moves dates forward a year", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		h, _ := f.Validate(time.UTC)
+
+		clone := CloneForm(h, time.UTC)
+
+		g.Expect(clone.Title).To(Equal(h.Title))
+		g.Expect(clone.HuntDate).To(Equal("2031-11-01T06:00"))
+		g.Expect(clone.SignupWindowStart).To(Equal("2031-09-01T09:00"))
+	})
+}
+
+func TestActionsFor(t *testing.T) {
+	t.Run("every action is an allowed transition", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, tab := range StatusTabs() {
+			for _, a := range ActionsFor(tab.Status) {
+				g.Expect(tab.Status.CanTransitionTo(a.To)).To(BeTrue(), "%s -> %s", tab.Status, a.To)
+			}
+		}
+	})
+
+	t.Run("final statuses have no actions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ActionsFor(model.HuntStatusCompleted)).To(BeEmpty())
+		g.Expect(ActionsFor(model.HuntStatusCancelled)).To(BeEmpty())
+	})
+}
+
+func TestFormProps(t *testing.T) {
+	t.Run("new hunts post to the collection", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(FormProps{}.Action()).To(Equal("/admin/hunts"))
+		g.Expect(FormProps{}.CanPublish()).To(BeTrue())
+	})
+
+	t.Run("existing hunts post to themselves", func(t *testing.T) {
+		g := NewWithT(t)
+		id := uuid.New()
+		p := FormProps{HuntID: id, Status: model.HuntStatusOpen}
+
+		g.Expect(p.Action()).To(Equal("/admin/hunts/" + id.String()))
+		g.Expect(p.CanPublish()).To(BeFalse())
+	})
+}
+
+func TestIndexProps_PageURL(t *testing.T) {
+	t.Run("works with and without a status", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(IndexProps{}.PageURL(2)).To(Equal("/admin/hunts?page=2"))
+		g.Expect(IndexProps{Status: model.HuntStatusOpen}.PageURL(2)).To(Equal("/admin/hunts?status=open&page=2"))
+	})
+}
+
+func TestNewEntry(t *testing.T) {
+	t.Run("decodes the eligibility snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := NewEntry(model.Signup{EligibilitySnapshot: sql.NullString{
+			String: `{"membership_status":"active","branch_of_service":"Army","prior_hunts":1,"attested_qualifiers":[]}`,
+			Valid:  true,
+		}}, "Jane", "jane@example.com")
+
+		g.Expect(e.Snapshot).ToNot(BeNil())
+		g.Expect(e.Snapshot.BranchOfService).To(Equal("Army"))
+		g.Expect(LastSelectedLabel(*e.Snapshot)).To(Equal("Never"))
+		g.Expect(QualifiersLabel(*e.Snapshot)).To(Equal("None required"))
+	})
+
+	t.Run("leaves Snapshot nil for signups without one", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := NewEntry(model.Signup{}, "Jane", "jane@example.com")
+
+		g.Expect(e.Snapshot).To(BeNil())
+	})
+}
+
+func TestEntriesProps_Active(t *testing.T) {
+	g := NewWithT(t)
+
+	props := EntriesProps{Entries: []Entry{
+		{Signup: model.Signup{}},
+		{Signup: model.Signup{WithdrawnAt: sql.NullTime{Time: time.Now(), Valid: true}}},
+	}}
+
+	g.Expect(props.Active()).To(Equal(1))
+}
+
+func TestNewRoster(t *testing.T) {
+	hunt := model.Hunt{PrimaryCapacity: 2, AlternateCapacity: 2}
+	var results []model.LotteryResult
+	var entries []Entry
+	for i := 1; i <= 5; i++ {
+		s := model.Signup{ID: uuid.New()}
+		results = append(results, model.LotteryResult{ID: uuid.New(), SignupID: s.ID, Position: i})
+		entries = append(entries, Entry{Name: "Member " + strconv.Itoa(i), Signup: s})
+	}
+	confirmations := []model.Confirmation{
+		{LotteryResultID: results[0].ID, Decision: model.ConfirmationAccepted},
+		{LotteryResultID: results[1].ID, Decision: model.ConfirmationDeclined},
+		{LotteryResultID: results[2].ID, Decision: model.ConfirmationPending},
+	}
+
+	t.Run("places entrants by position and confirmation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rows := NewRoster(hunt, results, entries, confirmations)
+
+		g.Expect(rows).To(HaveLen(4))
+		g.Expect(rows[0].Name).To(Equal("Member 1"))
+		g.Expect(rows[1].Slot).To(Equal(SlotPrimary))
+		g.Expect(rows[2].Slot).To(Equal(SlotPromoted))
+		g.Expect(rows[3].Slot).To(Equal(SlotWaiting))
+		g.Expect(rows[3].Confirmation).To(BeNil())
+	})
+
+	t.Run("counts held and accepted places", func(t *testing.T) {
+		g := NewWithT(t)
+
+		props := RosterProps{Hunt: hunt, Rows: NewRoster(hunt, results, entries, confirmations)}
+
+		g.Expect(props.Held()).To(Equal(2))
+		g.Expect(props.Accepted()).To(Equal(1))
+		g.Expect(props.InSlot(SlotWaiting)).To(HaveLen(1))
+	})
+}
+
+func TestConfirmationLabel(t *testing.T) {
+	deadline := time.Date(2030, 10, 4, 9, 0, 0, 0, time.UTC)
+
+	t.Run("describes offers and answers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ConfirmationLabel(nil, deadline)).To(Equal("Not offered"))
+		g.Expect(ConfirmationLabel(&model.Confirmation{Decision: model.ConfirmationExpired}, deadline)).To(Equal("Expired"))
+		g.Expect(ConfirmationLabel(&model.Confirmation{Decision: model.ConfirmationPending, Deadline: deadline}, deadline.Add(-time.Hour))).
+			To(Equal("Awaiting reply until Oct 4, 9:00 AM"))
+		g.Expect(ConfirmationLabel(&model.Confirmation{Decision: model.ConfirmationPending, Deadline: deadline}, deadline)).
+			To(Equal("Overdue"))
+	})
+}
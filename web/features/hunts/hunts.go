@@ -0,0 +1,362 @@
+package hunts
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+	"github.com/brian-abo/tfo-webapp/internal/markdown"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// PageSize is the number of hunts shown per listing page.
+const PageSize = 12
+
+// dateLayout is the format of the date filter inputs.
+const dateLayout = "2006-01-02"
+
+// RegionOption is a selectable region filter and the states it covers.
+type RegionOption struct {
+	ID     string
+	Name   string
+	States []string
+}
+
+// Filter holds the listing's query parameters as submitted.
+type Filter struct {
+	Region string
+	State  string
+	From   string
+	To     string
+}
+
+// FilterFromQuery reads a Filter from URL query values.
+func FilterFromQuery(q url.Values) Filter {
+	return Filter{
+		Region: q.Get("region"),
+		State:  q.Get("state"),
+		From:   q.Get("from"),
+		To:     q.Get("to"),
+	}
+}
+
+// FromDate returns the parsed From date, or the zero time if unset or
+// invalid.
+func (f Filter) FromDate() time.Time {
+	t, _ := time.Parse(dateLayout, f.From)
+	return t
+}
+
+// ToDate returns the end of the To date, or the zero time if unset or
+// invalid, so hunts on that day are included.
+func (f Filter) ToDate() time.Time {
+	t, err := time.Parse(dateLayout, f.To)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// States returns the state codes to filter by. A specific state takes
+// precedence over a region; nil means all states.
+func (f Filter) States(regions []RegionOption) []string {
+	if f.State != "" {
+		return []string{f.State}
+	}
+	for _, r := range regions {
+		if r.ID == f.Region {
+			return r.States
+		}
+	}
+	return nil
+}
+
+// Query encodes the filter as URL query values, omitting empty fields.
+func (f Filter) Query() url.Values {
+	q := url.Values{}
+	for k, v := range map[string]string{"region": f.Region, "state": f.State, "from": f.From, "to": f.To} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	return q
+}
+
+// ListProps contains data for the hunts listing.
+type ListProps struct {
+	Hunts   []model.Hunt
+	Total   int
+	Page    int
+	Filter  Filter
+	Regions []RegionOption
+	States  []string
+	Now     time.Time
+}
+
+// HasPrev returns true if there is a page before the current one.
+func (p ListProps) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true if there is a page after the current one.
+func (p ListProps) HasNext() bool {
+	return p.Page*PageSize < p.Total
+}
+
+// PageURL returns the listing URL for page n with the current filter.
+func (p ListProps) PageURL(n int) string {
+	q := p.Filter.Query()
+	if n > 1 {
+		q.Set("page", strconv.Itoa(n))
+	}
+	if len(q) == 0 {
+		return "/hunts"
+	}
+	return "/hunts?" + q.Encode()
+}
+
+// DetailProps contains data for a single hunt's page.
+type DetailProps struct {
+	Hunt  model.Hunt
+	Now   time.Time
+	Panel PanelProps
+	// HasReport is true if the hunt's after-action report is published.
+	HasReport bool
+}
+
+// EntryState is the viewer's relationship to a hunt's lottery.
+type EntryState int
+
+const (
+	EntryAnonymous EntryState = iota
+	EntryNone
+	EntryEntered
+	EntryWithdrawn
+)
+
+// EntryStateOf returns the entry state for a viewer. signup is nil if the
+// viewer never signed up.
+func EntryStateOf(loggedIn bool, signup *model.Signup) EntryState {
+	switch {
+	case !loggedIn:
+		return EntryAnonymous
+	case signup == nil:
+		return EntryNone
+	case signup.IsWithdrawn():
+		return EntryWithdrawn
+	default:
+		return EntryEntered
+	}
+}
+
+// PanelProps contains data for the signup panel on a hunt's page.
+type PanelProps struct {
+	HuntID uuid.UUID
+	State  EntryState
+	// Open is true while the hunt accepts signups.
+	Open bool
+	// Qualifiers are the hunt's qualifiers, attested to when entering.
+	Qualifiers []string
+	// Confirmation is the viewer's selection confirmation, if they were
+	// selected, answered at ConfirmationURL.
+	Confirmation    *model.Confirmation
+	ConfirmationURL string
+	Message         string
+	Error           string
+}
+
+// SelectionLabel summarises a selected viewer's confirmation for the
+// signup panel.
+func SelectionLabel(c model.Confirmation, now time.Time) string {
+	switch {
+	case c.Decision == model.ConfirmationAccepted:
+		return "You're confirmed for this hunt."
+	case c.Decision == model.ConfirmationDeclined:
+		return "You declined your place on this hunt."
+	case c.CanRespond(now):
+		return "You've been selected! Please confirm your place."
+	default:
+		return "Your selection was released because the deadline passed."
+	}
+}
+
+// EnterURL returns the form action for entering a hunt's lottery.
+func EnterURL(id uuid.UUID) string {
+	return DetailURL(id) + "/signup"
+}
+
+// ReenterURL returns the form action for undoing a withdrawal.
+func ReenterURL(id uuid.UUID) string {
+	return DetailURL(id) + "/reenter"
+}
+
+// WithdrawURL returns the form action for withdrawing from a lottery.
+func WithdrawURL(id uuid.UUID) string {
+	return DetailURL(id) + "/withdraw"
+}
+
+// VerifyURL returns the public lottery verification page for a hunt.
+func VerifyURL(id uuid.UUID) string {
+	return DetailURL(id) + "/lottery/verify"
+}
+
+// ReportURL returns the public after-action report for a hunt.
+func ReportURL(id uuid.UUID) string {
+	return DetailURL(id) + "/report"
+}
+
+// ReportProps contains data for a hunt's public after-action report.
+type ReportProps struct {
+	Hunt         model.Hunt
+	Report       model.HuntAfterActionReport
+	Participants []model.User
+}
+
+// Narrative returns the report's markdown narrative rendered to sanitized
+// HTML.
+func (p ReportProps) Narrative() string {
+	return markdown.Render(p.Report.Description)
+}
+
+// IsDrawn returns true if a hunt's lottery has been drawn, so it can be
+// verified.
+func IsDrawn(h model.Hunt) bool {
+	return h.Status == model.HuntStatusClosed || h.Status == model.HuntStatusCompleted
+}
+
+// VerifyProps contains data for a hunt's lottery verification page.
+// Verification is nil if the lottery hasn't been drawn.
+type VerifyProps struct {
+	Hunt         model.Hunt
+	Verification *lottery.Verification
+}
+
+// WeightsLabel describes the ticket table a weighted draw used, or returns
+// "" for draws that don't use one.
+func WeightsLabel(v lottery.Verification) string {
+	if v.AlgorithmVersion != lottery.WeightedV1 {
+		return ""
+	}
+	tickets := make([]string, len(v.Weights.MissedDrawTickets))
+	for i, n := range v.Weights.MissedDrawTickets {
+		tickets[i] = strconv.Itoa(n)
+	}
+	if len(tickets) == 0 {
+		tickets = []string{"1"}
+	}
+	return fmt.Sprintf("%s tickets for 0, 1, 2… missed draws; +%d for first-timers",
+		strings.Join(tickets, ", "), v.Weights.FirstTimerBonus)
+}
+
+// DetailURL returns the public page for a hunt.
+func DetailURL(id uuid.UUID) string {
+	return "/hunts/" + id.String()
+}
+
+// SignupPhase describes where a hunt is relative to its signup window.
+type SignupPhase int
+
+const (
+	SignupNotYetOpen SignupPhase = iota
+	SignupOpen
+	SignupClosed
+)
+
+// PhaseOf returns the hunt's signup phase at now. Only open hunts inside
+// their window accept signups.
+func PhaseOf(h model.Hunt, now time.Time) SignupPhase {
+	switch {
+	case h.Status == model.HuntStatusOpen && h.IsSignupWindowOpen(now):
+		return SignupOpen
+	case (h.Status == model.HuntStatusOpen || h.Status == model.HuntStatusDraft) && now.Before(h.SignupWindowStart):
+		return SignupNotYetOpen
+	default:
+		return SignupClosed
+	}
+}
+
+// CountdownTarget returns the moment the hunt's countdown runs to: the
+// window start before signups open, the window end while they're open.
+// ok is false once signups have closed.
+func CountdownTarget(h model.Hunt, now time.Time) (target time.Time, ok bool) {
+	switch PhaseOf(h, now) {
+	case SignupNotYetOpen:
+		return h.SignupWindowStart, true
+	case SignupOpen:
+		return h.SignupWindowEnd, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// CountdownLabel returns the text shown before the countdown.
+func CountdownLabel(h model.Hunt, now time.Time) string {
+	switch PhaseOf(h, now) {
+	case SignupNotYetOpen:
+		return "Signups open in"
+	case SignupOpen:
+		return "Signups close in"
+	default:
+		return "Signups closed"
+	}
+}
+
+// FormatCountdown renders a remaining duration coarsely, e.g. "3d 4h" or
+// "2h 15m". It is the server-rendered fallback for the live countdown.
+func FormatCountdown(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// CountdownData returns the Alpine x-data expression that ticks a live
+// countdown to target.
+func CountdownData(target time.Time) string {
+	return fmt.Sprintf(`{
+		target: %d,
+		left: '',
+		tick() {
+			const s = Math.max(0, Math.floor((this.target - Date.now()) / 1000));
+			const d = Math.floor(s / 86400), h = Math.floor(s %% 86400 / 3600), m = Math.floor(s %% 3600 / 60);
+			this.left = d > 0 ? d + 'd ' + h + 'h ' + m + 'm' : h + 'h ' + m + 'm ' + (s %% 60) + 's';
+		},
+		init() { this.tick(); setInterval(() => this.tick(), 1000); }
+	}`, target.UnixMilli())
+}
+
+// CapacityLabel describes a hunt's primary and alternate spots.
+func CapacityLabel(h model.Hunt) string {
+	label := plural(h.PrimaryCapacity, "hunter spot", "hunter spots")
+	if h.AlternateCapacity > 0 {
+		label += " + " + plural(h.AlternateCapacity, "alternate", "alternates")
+	}
+	return label
+}
+
+// DateLabel formats a hunt date for display.
+func DateLabel(t time.Time) string {
+	return t.Format("Mon, Jan 2, 2006")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return strconv.Itoa(n) + " " + many
+}
@@ -0,0 +1,215 @@
+package hunts
+
+import (
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/lottery"
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func testHunt(status model.HuntStatus) model.Hunt {
+	return model.Hunt{
+		Status:            status,
+		SignupWindowStart: time.Date(2030, 9, 1, 0, 0, 0, 0, time.UTC),
+		SignupWindowEnd:   time.Date(2030, 9, 15, 0, 0, 0, 0, time.UTC),
+		HuntDate:          time.Date(2030, 11, 1, 0, 0, 0, 0, time.UTC),
+		PrimaryCapacity:   10,
+		AlternateCapacity: 5,
+	}
+}
+
+func TestFilter(t *testing.T) {
+	regions := []RegionOption{
+		{ID: "midwest", Name: "Midwest", States: []string{"IA", "MN"}},
+	}
+
+	t.Run("round-trips through query values", func(t *testing.T) {
+		g := NewWithT(t)
+		q := url.Values{"region": {"midwest"}, "from": {"2030-01-01"}}
+
+		f := FilterFromQuery(q)
+
+		g.Expect(f.Query()).To(Equal(q))
+	})
+
+	t.Run("expands a region to its states", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Filter{Region: "midwest"}.States(regions)).To(Equal([]string{"IA", "MN"}))
+	})
+
+	t.Run("prefers a specific state over the region", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Filter{Region: "midwest", State: "TX"}.States(regions)).To(Equal([]string{"TX"}))
+	})
+
+	t.Run("matches all states by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Filter{Region: "unknown"}.States(regions)).To(BeNil())
+	})
+
+	t.Run("includes the whole To day", func(t *testing.T) {
+		g := NewWithT(t)
+
+		to := Filter{To: "2030-11-01"}.ToDate()
+
+		g.Expect(to.After(time.Date(2030, 11, 1, 23, 59, 0, 0, time.UTC))).To(BeTrue())
+		g.Expect(to.Before(time.Date(2030, 11, 2, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	t.Run("ignores invalid dates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(Filter{From: "soon"}.FromDate().IsZero()).To(BeTrue())
+		g.Expect(Filter{To: "later"}.ToDate().IsZero()).To(BeTrue())
+	})
+}
+
+func TestListProps_PageURL(t *testing.T) {
+	t.Run("omits the first page", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ListProps{}.PageURL(1)).To(Equal("/hunts"))
+	})
+
+	t.Run("keeps the filter", func(t *testing.T) {
+		g := NewWithT(t)
+		p := ListProps{Filter: Filter{State: "TX"}}
+
+		g.Expect(p.PageURL(2)).To(Equal("/hunts?page=2&state=TX"))
+	})
+}
+
+func TestPhaseOf(t *testing.T) {
+	h := testHunt(model.HuntStatusOpen)
+
+	t.Run("before the window", func(t *testing.T) {
+		g := NewWithT(t)
+		now := h.SignupWindowStart.Add(-time.Hour)
+
+		g.Expect(PhaseOf(h, now)).To(Equal(SignupNotYetOpen))
+		target, ok := CountdownTarget(h, now)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(target).To(Equal(h.SignupWindowStart))
+	})
+
+	t.Run("inside the window", func(t *testing.T) {
+		g := NewWithT(t)
+		now := h.SignupWindowStart.Add(time.Hour)
+
+		g.Expect(PhaseOf(h, now)).To(Equal(SignupOpen))
+		target, _ := CountdownTarget(h, now)
+		g.Expect(target).To(Equal(h.SignupWindowEnd))
+	})
+
+	t.Run("after the window", func(t *testing.T) {
+		g := NewWithT(t)
+		now := h.SignupWindowEnd.Add(time.Hour)
+
+		g.Expect(PhaseOf(h, now)).To(Equal(SignupClosed))
+		_, ok := CountdownTarget(h, now)
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("closed hunts are closed inside the window", func(t *testing.T) {
+		g := NewWithT(t)
+		closed := testHunt(model.HuntStatusClosed)
+
+		g.Expect(PhaseOf(closed, closed.SignupWindowStart.Add(time.Hour))).To(Equal(SignupClosed))
+	})
+}
+
+func TestFormatCountdown(t *testing.T) {
+	t.Run("formats days and hours", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(FormatCountdown(3*24*time.Hour + 4*time.Hour + 10*time.Minute)).To(Equal("3d 4h"))
+	})
+
+	t.Run("formats hours and minutes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(FormatCountdown(2*time.Hour + 15*time.Minute)).To(Equal("2h 15m"))
+	})
+
+	t.Run("formats minutes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(FormatCountdown(42 * time.Minute)).To(Equal("42m"))
+	})
+
+	t.Run("rounds down the last minute", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(FormatCountdown(30 * time.Second)).To(Equal("less than a minute"))
+	})
+}
+
+func TestCapacityLabel(t *testing.T) {
+	t.Run("includes alternates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(CapacityLabel(testHunt(model.HuntStatusOpen))).To(Equal("10 hunter spots + 5 alternates"))
+	})
+
+	t.Run("omits zero alternates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(CapacityLabel(model.Hunt{PrimaryCapacity: 1})).To(Equal("1 hunter spot"))
+	})
+}
+
+func TestEntryStateOf(t *testing.T) {
+	t.Run("anonymous viewers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(EntryStateOf(false, nil)).To(Equal(EntryAnonymous))
+	})
+
+	t.Run("members without a signup", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(EntryStateOf(true, nil)).To(Equal(EntryNone))
+	})
+
+	t.Run("active and withdrawn signups", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(EntryStateOf(true, &model.Signup{})).To(Equal(EntryEntered))
+		g.Expect(EntryStateOf(true, &model.Signup{WithdrawnAt: sql.NullTime{Time: time.Now(), Valid: true}})).To(Equal(EntryWithdrawn))
+	})
+}
+
+func TestIsDrawn(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(IsDrawn(model.Hunt{Status: model.HuntStatusOpen})).To(BeFalse())
+	g.Expect(IsDrawn(model.Hunt{Status: model.HuntStatusClosed})).To(BeTrue())
+	g.Expect(IsDrawn(model.Hunt{Status: model.HuntStatusCompleted})).To(BeTrue())
+}
+
+func TestWeightsLabel(t *testing.T) {
+	t.Run("is empty for uniform draws", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(WeightsLabel(lottery.Verification{AlgorithmVersion: lottery.UniformV1})).To(BeEmpty())
+	})
+
+	t.Run("describes the ticket table", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v := lottery.Verification{
+			AlgorithmVersion: lottery.WeightedV1,
+			Weights:          model.LotteryWeights{MissedDrawTickets: []int{1, 2}, FirstTimerBonus: 3},
+		}
+
+		g.Expect(WeightsLabel(v)).To(Equal("1, 2 tickets for 0, 1, 2… missed draws; +3 for first-timers"))
+	})
+}
@@ -0,0 +1,130 @@
+package jobadmin
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// PageSize is the number of jobs shown per page.
+const PageSize = 25
+
+// IndexURL is the staff job queue page.
+const IndexURL = "/admin/jobs"
+
+// RetryURL returns the form action for retrying a dead job.
+func RetryURL(id uuid.UUID) string {
+	return IndexURL + "/" + id.String() + "/retry"
+}
+
+// Tab is a job status filter.
+type Tab struct {
+	Status model.JobStatus
+	Label  string
+}
+
+// StatusTabs returns the page's status tabs, failed jobs first. The empty
+// status lists every job.
+func StatusTabs() []Tab {
+	return []Tab{
+		{Status: model.JobDead, Label: "Failed"},
+		{Status: model.JobPending, Label: "Pending"},
+		{Status: model.JobRunning, Label: "Running"},
+		{Status: model.JobDone, Label: "Done"},
+		{Status: "", Label: "All"},
+	}
+}
+
+// TabURL returns the page URL for a status tab.
+func TabURL(status model.JobStatus) string {
+	if status == model.JobDead {
+		return IndexURL
+	}
+	if status == "" {
+		return IndexURL + "?status=all"
+	}
+	return IndexURL + "?status=" + string(status)
+}
+
+// StatusFromQuery returns the tab named by the status query parameter:
+// failed jobs by default, and every job for "all".
+func StatusFromQuery(v string) model.JobStatus {
+	if v == "all" {
+		return ""
+	}
+	if s := model.JobStatus(v); s.IsValid() {
+		return s
+	}
+	return model.JobDead
+}
+
+// IndexProps contains data for the job queue page.
+type IndexProps struct {
+	Status model.JobStatus
+	Jobs   []model.Job
+	Total  int
+	Page   int
+	Counts map[model.JobStatus]int
+	// Error describes a failed retry.
+	Error string
+}
+
+// Count returns the number of jobs in a tab.
+func (p IndexProps) Count(status model.JobStatus) int {
+	if status != "" {
+		return p.Counts[status]
+	}
+	total := 0
+	for _, n := range p.Counts {
+		total += n
+	}
+	return total
+}
+
+// HasPrev returns true if there is a page before the current one.
+func (p IndexProps) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true if there is a page after the current one.
+func (p IndexProps) HasNext() bool {
+	return p.Page*PageSize < p.Total
+}
+
+// PageURL returns the page URL for page n of the current tab.
+func (p IndexProps) PageURL(n int) string {
+	u := TabURL(p.Status)
+	if p.Status == model.JobDead {
+		return u + "?page=" + strconv.Itoa(n)
+	}
+	return u + "&page=" + strconv.Itoa(n)
+}
+
+// AttemptsLabel describes how many of its attempts a job has used.
+func AttemptsLabel(j model.Job) string {
+	return fmt.Sprintf("%d of %d attempts", j.Attempts, j.MaxAttempts)
+}
+
+// WhenLabel describes when a job next runs or when it finished.
+func WhenLabel(j model.Job) string {
+	switch j.Status {
+	case model.JobPending:
+		return "Runs " + TimeLabel(j.RunAt)
+	case model.JobRunning:
+		return "Started " + TimeLabel(j.UpdatedAt)
+	default:
+		if j.FinishedAt.Valid {
+			return "Finished " + TimeLabel(j.FinishedAt.Time)
+		}
+		return "Updated " + TimeLabel(j.UpdatedAt)
+	}
+}
+
+// TimeLabel formats a job timestamp for display.
+func TimeLabel(t time.Time) string {
+	return t.Format("Jan 2, 2006 3:04:05 PM")
+}
@@ -0,0 +1,75 @@
+package jobadmin
+
+import (
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestTabs(t *testing.T) {
+	t.Run("round-trips every tab through its URL", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, tab := range StatusTabs() {
+			u, err := url.Parse(TabURL(tab.Status))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(StatusFromQuery(u.Query().Get("status"))).To(Equal(tab.Status), tab.Label)
+		}
+	})
+
+	t.Run("shows failed jobs for unknown statuses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(StatusFromQuery("bogus")).To(Equal(model.JobDead))
+	})
+}
+
+func TestIndexProps(t *testing.T) {
+	t.Run("counts jobs per tab", func(t *testing.T) {
+		g := NewWithT(t)
+		props := IndexProps{Counts: map[model.JobStatus]int{model.JobDead: 2, model.JobDone: 5}}
+
+		g.Expect(props.Count(model.JobDead)).To(Equal(2))
+		g.Expect(props.Count(model.JobPending)).To(Equal(0))
+		g.Expect(props.Count("")).To(Equal(7))
+	})
+
+	t.Run("pages within the current tab", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(IndexProps{Status: model.JobDead}.PageURL(2)).To(Equal("/admin/jobs?page=2"))
+		g.Expect(IndexProps{Status: model.JobDone}.PageURL(3)).To(Equal("/admin/jobs?status=done&page=3"))
+		g.Expect(IndexProps{}.PageURL(2)).To(Equal("/admin/jobs?status=all&page=2"))
+	})
+
+	t.Run("knows when there are more pages", func(t *testing.T) {
+		g := NewWithT(t)
+		props := IndexProps{Page: 1, Total: PageSize + 1}
+
+		g.Expect(props.HasPrev()).To(BeFalse())
+		g.Expect(props.HasNext()).To(BeTrue())
+	})
+}
+
+func TestWhenLabel(t *testing.T) {
+	at := time.Date(2026, 10, 18, 14, 5, 0, 0, time.UTC)
+
+	t.Run("shows when pending jobs run", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(WhenLabel(model.Job{Status: model.JobPending, RunAt: at})).To(Equal("Runs Oct 18, 2026 2:05:00 PM"))
+	})
+
+	t.Run("shows when dead jobs finished", func(t *testing.T) {
+		g := NewWithT(t)
+		j := model.Job{Status: model.JobDead, FinishedAt: sql.NullTime{Time: at, Valid: true}}
+
+		g.Expect(WhenLabel(j)).To(Equal("Finished Oct 18, 2026 2:05:00 PM"))
+		g.Expect(AttemptsLabel(model.Job{Attempts: 8, MaxAttempts: 8})).To(Equal("8 of 8 attempts"))
+	})
+}
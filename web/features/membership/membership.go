@@ -0,0 +1,99 @@
+package membership
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// PageSize is the number of members shown per queue page.
+const PageSize = 25
+
+// Tab is a membership status filter on the review queue.
+type Tab struct {
+	Status model.MembershipStatus
+	Label  string
+}
+
+// QueueTabs returns the queue's status tabs, pending first.
+func QueueTabs() []Tab {
+	return []Tab{
+		{Status: model.MembershipPending, Label: "Pending"},
+		{Status: model.MembershipActive, Label: "Active"},
+		{Status: model.MembershipSuspended, Label: "Suspended"},
+		{Status: model.MembershipInactive, Label: "Inactive"},
+	}
+}
+
+// Action is a decision staff can make on a member.
+type Action struct {
+	Label string
+	To    model.MembershipStatus
+	// Destructive actions are styled as warnings.
+	Destructive bool
+}
+
+// ActionsFor returns the decisions available for members in status.
+func ActionsFor(status model.MembershipStatus) []Action {
+	switch status {
+	case model.MembershipPending:
+		return []Action{
+			{Label: "Approve", To: model.MembershipActive},
+			{Label: "Reject", To: model.MembershipInactive, Destructive: true},
+		}
+	case model.MembershipActive:
+		return []Action{
+			{Label: "Suspend", To: model.MembershipSuspended, Destructive: true},
+			{Label: "Deactivate", To: model.MembershipInactive, Destructive: true},
+		}
+	case model.MembershipSuspended:
+		return []Action{
+			{Label: "Reinstate", To: model.MembershipActive},
+			{Label: "Deactivate", To: model.MembershipInactive, Destructive: true},
+		}
+	case model.MembershipInactive:
+		return []Action{
+			{Label: "Reactivate", To: model.MembershipActive},
+		}
+	default:
+		return nil
+	}
+}
+
+// QueueProps contains data for the membership review queue.
+type QueueProps struct {
+	Status  model.MembershipStatus
+	Members []model.User
+	Total   int
+	Page    int
+	// Error describes a failed decision on the member with ErrorFor.
+	Error    string
+	ErrorFor uuid.UUID
+}
+
+// HasPrev returns true if there is a page before the current one.
+func (p QueueProps) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true if there is a page after the current one.
+func (p QueueProps) HasNext() bool {
+	return p.Page*PageSize < p.Total
+}
+
+// TabURL returns the queue URL for a status tab.
+func TabURL(status model.MembershipStatus) string {
+	return "/admin/members?status=" + string(status)
+}
+
+// PageURL returns the queue URL for page n of the current tab.
+func (p QueueProps) PageURL(n int) string {
+	return TabURL(p.Status) + "&page=" + strconv.Itoa(n)
+}
+
+// DecisionURL returns the form action for deciding on a member.
+func DecisionURL(id uuid.UUID) string {
+	return "/admin/members/" + id.String() + "/decision"
+}
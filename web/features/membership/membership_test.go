@@ -0,0 +1,63 @@
+package membership
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func TestQueueTabs(t *testing.T) {
+	t.Run("starts with pending", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tabs := QueueTabs()
+
+		g.Expect(tabs).To(HaveLen(4))
+		g.Expect(tabs[0].Status).To(Equal(model.MembershipPending))
+	})
+}
+
+func TestActionsFor(t *testing.T) {
+	t.Run("every action is an allowed transition", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, tab := range QueueTabs() {
+			actions := ActionsFor(tab.Status)
+			g.Expect(actions).ToNot(BeEmpty(), "status %s", tab.Status)
+			for _, a := range actions {
+				g.Expect(tab.Status.CanTransitionTo(a.To)).To(BeTrue(), "%s -> %s", tab.Status, a.To)
+			}
+		}
+	})
+
+	t.Run("pending members can be approved", func(t *testing.T) {
+		g := NewWithT(t)
+
+		actions := ActionsFor(model.MembershipPending)
+
+		g.Expect(actions[0].Label).To(Equal("Approve"))
+		g.Expect(actions[0].To).To(Equal(model.MembershipActive))
+	})
+}
+
+func TestQueueProps_Pagination(t *testing.T) {
+	t.Run("first page of many", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := QueueProps{Page: 1, Total: PageSize + 1}
+
+		g.Expect(p.HasPrev()).To(BeFalse())
+		g.Expect(p.HasNext()).To(BeTrue())
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := QueueProps{Page: 2, Total: PageSize + 1}
+
+		g.Expect(p.HasPrev()).To(BeTrue())
+		g.Expect(p.HasNext()).To(BeFalse())
+	})
+}
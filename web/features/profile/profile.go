@@ -0,0 +1,187 @@
+package profile
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+// Mode selects between first-login onboarding and later profile editing.
+type Mode int
+
+const (
+	ModeOnboarding Mode = iota
+	ModeEdit
+)
+
+// Action returns the form's submit URL for the mode.
+func (m Mode) Action() string {
+	if m == ModeOnboarding {
+		return "/onboarding"
+	}
+	return "/profile"
+}
+
+// ContactOption is a selectable contact preference.
+type ContactOption struct {
+	Value model.ContactPreference
+	Label string
+}
+
+// ContactOptions returns the contact preferences offered on the form.
+func ContactOptions() []ContactOption {
+	return []ContactOption{
+		{Value: model.ContactByEmail, Label: "Email"},
+		{Value: model.ContactByPhone, Label: "Phone call"},
+		{Value: model.ContactByText, Label: "Text message"},
+	}
+}
+
+// Form holds submitted profile values.
+type Form struct {
+	Name              string
+	Phone             string
+	BranchOfService   string
+	ContactPreference model.ContactPreference
+}
+
+// FormFromUser pre-fills the form with a user's current profile.
+func FormFromUser(u model.User) Form {
+	f := Form{
+		Name:              u.Name,
+		Phone:             u.Phone.String,
+		BranchOfService:   u.BranchOfService,
+		ContactPreference: u.ContactPreference,
+	}
+	if f.ContactPreference == "" {
+		f.ContactPreference = model.ContactByEmail
+	}
+	return f
+}
+
+// Errors maps form field names to validation messages.
+type Errors map[string]string
+
+// Validate trims the form and returns any field errors.
+func (f *Form) Validate() Errors {
+	f.Name = strings.TrimSpace(f.Name)
+	f.Phone = strings.TrimSpace(f.Phone)
+	f.BranchOfService = strings.TrimSpace(f.BranchOfService)
+
+	errs := Errors{}
+	if f.Name == "" {
+		errs["name"] = "Name is required"
+	}
+	if f.BranchOfService == "" {
+		errs["branch_of_service"] = "Branch of service is required"
+	} else if !model.IsValidBranchOfService(f.BranchOfService) {
+		errs["branch_of_service"] = "Please choose a branch from the list"
+	}
+
+	validPref := false
+	for _, o := range ContactOptions() {
+		if o.Value == f.ContactPreference {
+			validPref = true
+		}
+	}
+	if !validPref {
+		errs["contact_preference"] = "Please choose how we should contact you"
+	}
+
+	if f.Phone == "" {
+		if f.ContactPreference.NeedsPhone() {
+			errs["phone"] = "Phone is required for phone or text contact"
+		}
+	} else if !isValidPhone(f.Phone) {
+		errs["phone"] = "Please enter a valid phone number"
+	}
+	return errs
+}
+
+// isValidPhone accepts 10 to 15 digits with common punctuation.
+func isValidPhone(phone string) bool {
+	digits := 0
+	for _, r := range phone {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case strings.ContainsRune(" ()-.+", r):
+		default:
+			return false
+		}
+	}
+	return digits >= 10 && digits <= 15
+}
+
+// Props contains data for rendering the profile form.
+type Props struct {
+	Mode   Mode
+	Email  string
+	Form   Form
+	Errors Errors
+	Saved  bool
+	// History lists the member's hunts, most recent first, shown as "My
+	// hunts" on the profile page.
+	History []HistoryEntry
+}
+
+// HistoryEntry is one hunt in a member's history: one they entered, or
+// were tagged in the after-action report of.
+type HistoryEntry struct {
+	Hunt      model.Hunt
+	Entered   bool
+	Withdrawn bool
+	// Position is the member's place in the draw, or 0 if not drawn.
+	Position int
+	// Decision is the member's answer to an offered place, if any.
+	Decision  model.ConfirmationDecision
+	HasReport bool
+}
+
+// Selected returns true if the member was offered a place on the hunt,
+// in the draw or by promotion from the alternates.
+func (e HistoryEntry) Selected() bool {
+	if e.Decision != "" {
+		return true
+	}
+	r := model.LotteryResult{Position: e.Position}
+	return !e.Withdrawn && e.Position > 0 && r.IsPrimary(e.Hunt.PrimaryCapacity)
+}
+
+// Outcome describes how the hunt went for the member.
+func (e HistoryEntry) Outcome() string {
+	switch {
+	case !e.Entered:
+		return "Took part"
+	case e.Withdrawn:
+		return "Withdrew"
+	case e.Hunt.Status == model.HuntStatusCancelled:
+		return "Hunt cancelled"
+	}
+	switch e.Decision {
+	case model.ConfirmationAccepted:
+		if e.Hunt.Status == model.HuntStatusCompleted {
+			return "Attended"
+		}
+		return "Confirmed"
+	case model.ConfirmationPending:
+		return "Selected, awaiting your reply"
+	case model.ConfirmationDeclined:
+		return "Selected, declined"
+	case model.ConfirmationExpired:
+		return "Selected, didn't reply in time"
+	}
+	r := model.LotteryResult{Position: e.Position}
+	switch {
+	case e.Position == 0:
+		return "Awaiting the draw"
+	case r.IsPrimary(e.Hunt.PrimaryCapacity):
+		return "Selected"
+	case r.IsAlternate(e.Hunt.PrimaryCapacity, e.Hunt.AlternateCapacity):
+		return "Alternate #" + strconv.Itoa(e.Position-e.Hunt.PrimaryCapacity)
+	default:
+		return "Not selected"
+	}
+}
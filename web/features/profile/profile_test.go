@@ -0,0 +1,180 @@
+package profile
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func validForm() Form {
+	return Form{
+		Name:              "Jane Doe",
+		BranchOfService:   "Army",
+		ContactPreference: model.ContactByEmail,
+	}
+}
+
+func TestForm_Validate(t *testing.T) {
+	t.Run("accepts a complete form", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+
+		g.Expect(f.Validate()).To(BeEmpty())
+	})
+
+	t.Run("trims whitespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+		f.Name = "  Jane Doe  "
+		f.Validate()
+
+		g.Expect(f.Name).To(Equal("Jane Doe"))
+	})
+
+	t.Run("requires name and branch", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := Form{ContactPreference: model.ContactByEmail}
+		errs := f.Validate()
+
+		g.Expect(errs).To(HaveKey("name"))
+		g.Expect(errs).To(HaveKey("branch_of_service"))
+	})
+
+	t.Run("rejects branches not on the list", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+		f.BranchOfService = "Starfleet"
+
+		g.Expect(f.Validate()).To(HaveKeyWithValue("branch_of_service", "Please choose a branch from the list"))
+	})
+
+	t.Run("accepts Gold Star family", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+		f.BranchOfService = model.BranchGoldStarFamily
+
+		g.Expect(f.Validate()).To(BeEmpty())
+	})
+
+	t.Run("requires phone for text contact", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+		f.ContactPreference = model.ContactByText
+
+		g.Expect(f.Validate()).To(HaveKey("phone"))
+	})
+
+	t.Run("rejects malformed phone numbers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+		f.Phone = "call me"
+
+		g.Expect(f.Validate()).To(HaveKey("phone"))
+	})
+
+	t.Run("accepts formatted phone numbers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+		f.Phone = "(555) 010-0100"
+		f.ContactPreference = model.ContactByPhone
+
+		g.Expect(f.Validate()).To(BeEmpty())
+	})
+
+	t.Run("rejects unknown contact preferences", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := validForm()
+		f.ContactPreference = "carrier-pigeon"
+
+		g.Expect(f.Validate()).To(HaveKey("contact_preference"))
+	})
+}
+
+func TestFormFromUser(t *testing.T) {
+	t.Run("copies profile fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := FormFromUser(model.User{
+			Name:              "Jane Doe",
+			Phone:             sql.NullString{String: "555-010-0100", Valid: true},
+			BranchOfService:   "Navy",
+			ContactPreference: model.ContactByText,
+		})
+
+		g.Expect(f.Name).To(Equal("Jane Doe"))
+		g.Expect(f.Phone).To(Equal("555-010-0100"))
+		g.Expect(f.BranchOfService).To(Equal("Navy"))
+		g.Expect(f.ContactPreference).To(Equal(model.ContactByText))
+	})
+
+	t.Run("defaults contact preference to email", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := FormFromUser(model.User{})
+
+		g.Expect(f.ContactPreference).To(Equal(model.ContactByEmail))
+	})
+}
+
+func TestHistoryEntry_Outcome(t *testing.T) {
+	hunt := model.Hunt{Status: model.HuntStatusClosed, PrimaryCapacity: 2, AlternateCapacity: 2}
+
+	t.Run("describes hunts the member was only tagged in", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(HistoryEntry{Hunt: hunt}.Outcome()).To(Equal("Took part"))
+	})
+
+	t.Run("describes withdrawn entries", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := HistoryEntry{Hunt: hunt, Entered: true, Withdrawn: true, Position: 1}
+
+		g.Expect(e.Outcome()).To(Equal("Withdrew"))
+		g.Expect(e.Selected()).To(BeFalse())
+	})
+
+	t.Run("describes entries awaiting the draw", func(t *testing.T) {
+		g := NewWithT(t)
+
+		open := hunt
+		open.Status = model.HuntStatusOpen
+
+		g.Expect(HistoryEntry{Hunt: open, Entered: true}.Outcome()).To(Equal("Awaiting the draw"))
+	})
+
+	t.Run("describes the draw position", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(HistoryEntry{Hunt: hunt, Entered: true, Position: 2}.Outcome()).To(Equal("Selected"))
+		g.Expect(HistoryEntry{Hunt: hunt, Entered: true, Position: 4}.Outcome()).To(Equal("Alternate #2"))
+		g.Expect(HistoryEntry{Hunt: hunt, Entered: true, Position: 5}.Outcome()).To(Equal("Not selected"))
+	})
+
+	t.Run("prefers the member's answer to an offered place", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := HistoryEntry{Hunt: hunt, Entered: true, Position: 3, Decision: model.ConfirmationAccepted}
+
+		g.Expect(e.Outcome()).To(Equal("Confirmed"))
+		g.Expect(e.Selected()).To(BeTrue())
+
+		e.Hunt.Status = model.HuntStatusCompleted
+		g.Expect(e.Outcome()).To(Equal("Attended"))
+
+		e.Decision = model.ConfirmationExpired
+		g.Expect(e.Outcome()).To(Equal("Selected, didn't reply in time"))
+	})
+}
@@ -0,0 +1,178 @@
+package regionadmin
+
+import (
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+	"github.com/brian-abo/tfo-webapp/web/features/contact"
+)
+
+// IndexURL lists every region.
+const IndexURL = "/admin/regions"
+
+// NewURL is the form for adding a region.
+const NewURL = IndexURL + "/new"
+
+// EditURL returns the form for editing a region.
+func EditURL(id string) string {
+	return IndexURL + "/" + id
+}
+
+var (
+	slugPattern  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+)
+
+// Form holds submitted region values as entered.
+type Form struct {
+	ID          string
+	Name        string
+	Color       string
+	Position    string
+	States      string // state codes separated by commas or spaces
+	LeaderName  string
+	LeaderEmail string
+}
+
+// NewForm returns the form for a new region, placed after the existing
+// ones.
+func NewForm(regions []model.Region) Form {
+	position := 1
+	for _, r := range regions {
+		if r.Position >= position {
+			position = r.Position + 1
+		}
+	}
+	return Form{Color: "#737373", Position: strconv.Itoa(position)}
+}
+
+// FormFromRegion pre-fills the form with a region's values. leader is nil
+// for a region without one.
+func FormFromRegion(r model.Region, leader *model.RegionalLeader) Form {
+	f := Form{
+		ID:       r.ID,
+		Name:     r.Name,
+		Color:    r.Color,
+		Position: strconv.Itoa(r.Position),
+		States:   strings.Join(r.States, ", "),
+	}
+	if leader != nil {
+		f.LeaderName = leader.Name
+		f.LeaderEmail = leader.Email
+	}
+	return f
+}
+
+// Errors maps form field names to validation messages.
+type Errors map[string]string
+
+// Validate checks the form and returns the region and leader it
+// describes; the leader is nil when both leader fields are blank. The
+// results are only meaningful when errs is empty.
+func (f *Form) Validate() (model.Region, *model.RegionalLeader, Errors) {
+	f.ID = strings.ToLower(strings.TrimSpace(f.ID))
+	f.Name = strings.TrimSpace(f.Name)
+	f.Color = strings.TrimSpace(f.Color)
+	f.LeaderName = strings.TrimSpace(f.LeaderName)
+	f.LeaderEmail = strings.TrimSpace(f.LeaderEmail)
+
+	errs := Errors{}
+	r := model.Region{ID: f.ID, Name: f.Name, Color: f.Color}
+
+	switch {
+	case !slugPattern.MatchString(f.ID):
+		errs["id"] = "Use lowercase letters, numbers and dashes, like west-coast"
+	case EditURL(f.ID) == NewURL:
+		errs["id"] = "This ID is reserved"
+	}
+	if f.Name == "" {
+		errs["name"] = "Name is required"
+	}
+	if !colorPattern.MatchString(f.Color) {
+		errs["color"] = "Use a hex colour, like #009FDE"
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(f.Position)); err != nil || n < 0 {
+		errs["position"] = "Enter a whole number, zero or more"
+	} else {
+		r.Position = n
+	}
+
+	var unknown []string
+	for _, code := range strings.FieldsFunc(strings.ToUpper(f.States), isStateSeparator) {
+		if !contact.IsStateCode(code) {
+			unknown = append(unknown, code)
+			continue
+		}
+		r.States = append(r.States, code)
+	}
+	if len(unknown) > 0 {
+		errs["states"] = "Not states on the map: " + strings.Join(unknown, ", ")
+	}
+
+	if f.LeaderName == "" && f.LeaderEmail == "" {
+		return r, nil, errs
+	}
+	leader := &model.RegionalLeader{RegionID: r.ID, Name: f.LeaderName, Email: f.LeaderEmail}
+	if f.LeaderName == "" {
+		errs["leader_name"] = "Name the leader, or clear both leader fields"
+	}
+	if _, err := mail.ParseAddress(f.LeaderEmail); err != nil {
+		errs["leader_email"] = "Enter a valid email address"
+	}
+	return r, leader, errs
+}
+
+func isStateSeparator(c rune) bool {
+	return c == ',' || unicode.IsSpace(c)
+}
+
+// IndexProps contains data for the region list.
+type IndexProps struct {
+	Regions []model.Region
+	Leaders []model.RegionalLeader
+}
+
+// LeaderFor returns the leader of a region, or nil if it has none.
+func (p IndexProps) LeaderFor(regionID string) *model.RegionalLeader {
+	for i := range p.Leaders {
+		if p.Leaders[i].RegionID == regionID {
+			return &p.Leaders[i]
+		}
+	}
+	return nil
+}
+
+// Unassigned returns the codes of states on the map that no region
+// covers. Visitors there can't pick a region on the contact page.
+func (p IndexProps) Unassigned() []string {
+	var codes []string
+	for _, s := range contact.UnassignedStates(p.Regions) {
+		codes = append(codes, s.ID)
+	}
+	return codes
+}
+
+// FormProps contains data for the create and edit forms. An empty
+// RegionID means a new region.
+type FormProps struct {
+	RegionID string
+	Form     Form
+	Errors   Errors
+}
+
+// IsNew returns true if the form creates a region.
+func (p FormProps) IsNew() bool {
+	return p.RegionID == ""
+}
+
+// Action returns the form's submit URL.
+func (p FormProps) Action() string {
+	if p.IsNew() {
+		return IndexURL
+	}
+	return EditURL(p.RegionID)
+}
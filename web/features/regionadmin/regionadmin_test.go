@@ -0,0 +1,144 @@
+package regionadmin
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/brian-abo/tfo-webapp/internal/model"
+)
+
+func validForm() Form {
+	return Form{
+		ID:          "west-coast",
+		Name:        "West Coast",
+		Color:       "#009FDE",
+		Position:    "1",
+		States:      "CA, OR,WA\nNV",
+		LeaderName:  "David Lee",
+		LeaderEmail: "westcoast@thefallenoutdoors.org",
+	}
+}
+
+func TestFormValidate(t *testing.T) {
+	t.Run("returns the region and leader for a valid form", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+
+		r, leader, errs := f.Validate()
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(r.ID).To(Equal("west-coast"))
+		g.Expect(r.Name).To(Equal("West Coast"))
+		g.Expect(r.Color).To(Equal("#009FDE"))
+		g.Expect(r.Position).To(Equal(1))
+		g.Expect(r.States).To(Equal([]string{"CA", "OR", "WA", "NV"}))
+		g.Expect(leader).To(Equal(&model.RegionalLeader{
+			RegionID: "west-coast",
+			Name:     "David Lee",
+			Email:    "westcoast@thefallenoutdoors.org",
+		}))
+	})
+
+	t.Run("returns no leader when both leader fields are blank", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.LeaderName = " "
+		f.LeaderEmail = ""
+
+		_, leader, errs := f.Validate()
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(leader).To(BeNil())
+	})
+
+	t.Run("accepts lower-case state codes", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.States = "tx ok"
+
+		r, _, errs := f.Validate()
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(r.States).To(Equal([]string{"TX", "OK"}))
+	})
+
+	t.Run("rejects invalid fields", func(t *testing.T) {
+		g := NewWithT(t)
+		f := Form{ID: "West Coast", Color: "blue", Position: "-1", States: "CA, PR, XX", LeaderEmail: "nope"}
+
+		_, _, errs := f.Validate()
+
+		g.Expect(errs).To(HaveKey("id"))
+		g.Expect(errs).To(HaveKey("name"))
+		g.Expect(errs).To(HaveKey("color"))
+		g.Expect(errs).To(HaveKey("position"))
+		g.Expect(errs).To(HaveKeyWithValue("states", "Not states on the map: PR, XX"))
+		g.Expect(errs).To(HaveKey("leader_name"))
+		g.Expect(errs).To(HaveKey("leader_email"))
+	})
+
+	t.Run("reserves the new region path", func(t *testing.T) {
+		g := NewWithT(t)
+		f := validForm()
+		f.ID = "new"
+
+		_, _, errs := f.Validate()
+
+		g.Expect(errs).To(HaveKey("id"))
+	})
+}
+
+func TestNewForm(t *testing.T) {
+	t.Run("places the region after the existing ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := NewForm([]model.Region{{Position: 4}, {Position: 2}})
+
+		g.Expect(f.Position).To(Equal("5"))
+	})
+
+	t.Run("starts at one without regions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(NewForm(nil).Position).To(Equal("1"))
+	})
+}
+
+func TestFormFromRegion(t *testing.T) {
+	t.Run("round-trips through Validate", func(t *testing.T) {
+		g := NewWithT(t)
+		region := model.Region{ID: "midwest", Name: "Midwest", Color: "#81D742", Position: 2, States: []string{"IA", "MN"}}
+		leader := &model.RegionalLeader{RegionID: "midwest", Name: "James Wilson", Email: "midwest@thefallenoutdoors.org"}
+
+		f := FormFromRegion(region, leader)
+		r, l, errs := f.Validate()
+
+		g.Expect(errs).To(BeEmpty())
+		g.Expect(r).To(Equal(region))
+		g.Expect(l).To(Equal(leader))
+	})
+}
+
+func TestIndexProps(t *testing.T) {
+	props := IndexProps{
+		Regions: []model.Region{{ID: "midwest", States: []string{"IA"}}, {ID: "southern"}},
+		Leaders: []model.RegionalLeader{{RegionID: "midwest", Name: "James Wilson"}},
+	}
+
+	t.Run("finds each region's leader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(props.LeaderFor("midwest").Name).To(Equal("James Wilson"))
+		g.Expect(props.LeaderFor("southern")).To(BeNil())
+	})
+
+	t.Run("lists states outside every region", func(t *testing.T) {
+		g := NewWithT(t)
+
+		unassigned := props.Unassigned()
+
+		g.Expect(unassigned).ToNot(ContainElement("IA"))
+		g.Expect(unassigned).To(ContainElements("TX", "CA"))
+	})
+}
@@ -0,0 +1,24 @@
+package layout
+
+import "strings"
+
+// EmailProps contains configuration for the email layout.
+type EmailProps struct {
+	// Title is the message's heading, usually its subject.
+	Title string
+	// SiteURL is the public base URL links in the footer point to.
+	SiteURL string
+}
+
+// EmailText wraps the plain-text alternative of an email with the same
+// heading and sign-off as the HTML layout.
+func EmailText(props EmailProps, body string) string {
+	var b strings.Builder
+	b.WriteString(props.Title)
+	b.WriteString("\n\n")
+	b.WriteString(strings.TrimSpace(body))
+	b.WriteString("\n\n-- \nThe Fallen Outdoors\n")
+	b.WriteString(props.SiteURL)
+	b.WriteString("\n")
+	return b.String()
+}
@@ -1,8 +1,44 @@
 package layout
 
+import (
+	"context"
+
+	"github.com/brian-abo/tfo-webapp/web/components"
+)
+
 // PageProps contains configuration for the base page layout.
 type PageProps struct {
 	Title      string
 	IsLoggedIn bool
 	UserName   string
 }
+
+// Viewer identifies the logged-in user shown in the page chrome.
+type Viewer struct {
+	Name string
+}
+
+type viewerKey struct{}
+
+// WithViewer returns a copy of ctx carrying the logged-in viewer. Pages
+// rendered with this context show the viewer in the header without each
+// feature having to set IsLoggedIn and UserName.
+func WithViewer(ctx context.Context, v Viewer) context.Context {
+	return context.WithValue(ctx, viewerKey{}, v)
+}
+
+// headerProps builds the header configuration, filling auth state from the
+// viewer in ctx when props don't set it explicitly.
+func headerProps(ctx context.Context, props PageProps) components.HeaderProps {
+	hp := components.HeaderProps{
+		IsLoggedIn: props.IsLoggedIn,
+		UserName:   props.UserName,
+	}
+	if !hp.IsLoggedIn {
+		if v, ok := ctx.Value(viewerKey{}).(Viewer); ok {
+			hp.IsLoggedIn = true
+			hp.UserName = v.Name
+		}
+	}
+	return hp
+}
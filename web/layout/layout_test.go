@@ -0,0 +1,48 @@
+package layout
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestHeaderProps(t *testing.T) {
+	t.Run("is logged out without a viewer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hp := headerProps(context.Background(), PageProps{Title: "Home"})
+
+		g.Expect(hp.IsLoggedIn).To(BeFalse())
+		g.Expect(hp.UserName).To(BeEmpty())
+	})
+
+	t.Run("fills auth state from the viewer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx := WithViewer(context.Background(), Viewer{Name: "Jane Doe"})
+		hp := headerProps(ctx, PageProps{Title: "Home"})
+
+		g.Expect(hp.IsLoggedIn).To(BeTrue())
+		g.Expect(hp.UserName).To(Equal("Jane Doe"))
+	})
+
+	t.Run("prefers explicit props", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx := WithViewer(context.Background(), Viewer{Name: "Jane Doe"})
+		hp := headerProps(ctx, PageProps{IsLoggedIn: true, UserName: "Preview"})
+
+		g.Expect(hp.UserName).To(Equal("Preview"))
+	})
+}
+
+func TestEmailText(t *testing.T) {
+	t.Run("wraps the body with the heading and sign-off", func(t *testing.T) {
+		g := NewWithT(t)
+
+		text := EmailText(EmailProps{Title: "Hunt cancelled", SiteURL: "https://example.org"}, "\nSorry about this.\n\n")
+
+		g.Expect(text).To(Equal("Hunt cancelled\n\nSorry about this.\n\n-- \nThe Fallen Outdoors\nhttps://example.org\n"))
+	})
+}